@@ -39,6 +39,16 @@ func main() {
 	}
 	defer database.CloseDatabase()
 
+	// Record version 1 of contracts created before amendments were versioned
+	if count, err := handlers.BackfillContractVersions(); err != nil {
+		log.Fatal("Failed to record initial contract versions:", err)
+	} else if count > 0 {
+		log.Printf("Recorded version 1 of %d contract(s)", count)
+	}
+
+	// Put renewed contracts into effect on their start date
+	handlers.StartContractActivation()
+
 	// Initialize Gin router
 	r := gin.Default()
 
@@ -134,6 +144,14 @@ func main() {
 			auth.POST("/register", handlers.Register)
 		}
 
+		// Contract e-signing through one-time links (the token is the credential)
+		sign := api.Group("/sign")
+		{
+			sign.GET("/:token", handlers.GetSigningDocument)
+			sign.GET("/:token/document", handlers.GetSigningDocumentPDF)
+			sign.POST("/:token", handlers.SubmitSignature)
+		}
+
 		// Protected routes
 		protected := api.Group("/")
 		protected.Use(middleware.AuthMiddleware())
@@ -145,7 +163,28 @@ func main() {
 				employees.POST("", middleware.RequireRole("admin", "hr"), handlers.CreateEmployee)
 				employees.POST("/with-contract", middleware.RequireRole("admin", "hr"), handlers.CreateEmployeeWithContract)
 				employees.GET("/:id", handlers.GetEmployee)
+				employees.GET("/:id/fixed-term-status", handlers.GetEmployeeFixedTermStatus)
+				employees.GET("/:id/work-schedule", handlers.GetEmployeeWorkSchedule)
+				employees.GET("/:id/work-weeks", handlers.GetEmployeeWorkWeeks)
+				employees.POST("/:id/convert-permanent", middleware.RequireRole("admin", "hr"), handlers.ConvertToPermanent)
 				employees.PUT("/:id", middleware.RequireRole("admin", "hr"), handlers.UpdateEmployee)
+				employees.GET("/:id/withholding", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeWithholding)
+				employees.PUT("/:id/withholding", middleware.RequireRole("admin", "hr"), handlers.UpdateEmployeeWithholding)
+				employees.GET("/:id/insurance-bases", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeInsuranceBases)
+				employees.POST("/:id/insurance-bases", middleware.RequireRole("admin", "hr"), handlers.CreateEmployeeInsuranceBase)
+				employees.GET("/:id/ordinary-wage", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeOrdinaryWage)
+				employees.GET("/:id/average-wage", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeAverageWage)
+				employees.GET("/:id/severance", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeSeveranceEstimate)
+				employees.GET("/:id/retirement-pension", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeRetirementPension)
+				employees.PUT("/:id/retirement-pension", middleware.RequireRole("admin", "hr"), handlers.UpdateEmployeeRetirementPension)
+				employees.GET("/:id/bank-account", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeBankAccount)
+				employees.PUT("/:id/bank-account", middleware.RequireRole("admin", "hr"), handlers.UpdateEmployeeBankAccount)
+				employees.GET("/:id/year-end-settlements/:year", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeYearEndSettlement)
+				employees.PUT("/:id/year-end-settlements/:year", middleware.RequireRole("admin", "hr"), handlers.UpdateEmployeeYearEndSettlement)
+				employees.PUT("/:id/year-end-settlements/:year/confirm", middleware.RequireRole("admin", "hr"), handlers.ConfirmEmployeeYearEndSettlement)
+				employees.PUT("/:id/year-end-settlements/:year/reopen", middleware.RequireRole("admin", "hr"), handlers.ReopenEmployeeYearEndSettlement)
+				employees.GET("/:id/withholding-receipts/:year", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeWithholdingReceipt)
+				employees.GET("/:id/withholding-statements/:year", middleware.RequireRole("admin", "hr"), handlers.GetEmployeeWithholdingStatementFile)
 				employees.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteEmployee)
 			}
 
@@ -155,19 +194,143 @@ func main() {
 				contracts.GET("", handlers.GetContracts)
 				contracts.POST("", middleware.RequireRole("admin", "hr"), handlers.CreateContract)
 				contracts.POST("/with-employee", middleware.RequireRole("admin", "hr"), handlers.CreateContractWithEmployee)
+				contracts.POST("/validate", handlers.ValidateContract)
+				contracts.GET("/fixed-term-status", middleware.RequireRole("admin", "hr"), handlers.GetFixedTermStatuses)
 				contracts.GET("/:id", handlers.GetContract)
 				contracts.PUT("/:id", middleware.RequireRole("admin", "hr"), handlers.UpdateContract)
+				contracts.GET("/:id/compliance", handlers.GetContractCompliance)
+				contracts.GET("/:id/versions", handlers.GetContractVersions)
+				contracts.GET("/:id/versions/:version", handlers.GetContractVersion)
+				contracts.PUT("/:id/versions/:version/sign", middleware.RequireRole("admin", "hr"), handlers.SignContractVersion)
+				contracts.GET("/:id/signature-requests", middleware.RequireRole("admin", "hr"), handlers.GetSignatureRequests)
+				contracts.POST("/:id/signature-requests", middleware.RequireRole("admin", "hr"), handlers.CreateSignatureRequest)
+				contracts.DELETE("/:id/signature-requests/:requestId", middleware.RequireRole("admin", "hr"), handlers.CancelSignatureRequest)
+				contracts.POST("/:id/renew", middleware.RequireRole("admin", "hr"), handlers.RenewContract)
 				contracts.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteContract)
 			}
 
+			// Work schedules referenced by contracts
+			schedules := protected.Group("/work-schedules")
+			{
+				schedules.GET("", handlers.GetWorkSchedules)
+				schedules.POST("", middleware.RequireRole("admin", "hr"), handlers.CreateWorkSchedule)
+				schedules.GET("/:id", handlers.GetWorkSchedule)
+				schedules.PUT("/:id", middleware.RequireRole("admin", "hr"), handlers.UpdateWorkSchedule)
+				schedules.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteWorkSchedule)
+			}
+
+			// Allowance types (수당 항목)
+			allowanceTypes := protected.Group("/allowance-types")
+			{
+				allowanceTypes.GET("", handlers.GetAllowanceTypes)
+				allowanceTypes.POST("", middleware.RequireRole("admin", "hr"), handlers.CreateAllowanceType)
+				allowanceTypes.PUT("/:id", middleware.RequireRole("admin", "hr"), handlers.UpdateAllowanceType)
+				allowanceTypes.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteAllowanceType)
+			}
+
 			// Payroll
 			payroll := protected.Group("/payroll")
 			{
 				payroll.GET("", handlers.GetPayrollRecords)
+				payroll.GET("/ledger", middleware.RequireRole("admin", "hr"), handlers.GetPayrollLedger)
+				payroll.GET("/withholding-status", middleware.RequireRole("admin", "hr"), handlers.GetWithholdingStatusReport)
 				payroll.POST("", middleware.RequireRole("admin", "hr"), handlers.CreatePayrollRecord)
 				payroll.GET("/:id", handlers.GetPayrollRecord)
 				payroll.PUT("/:id", middleware.RequireRole("admin", "hr"), handlers.UpdatePayrollRecord)
 				payroll.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeletePayrollRecord)
+				payroll.GET("/:id/adjustments", middleware.RequireRole("admin", "hr"), handlers.GetPayrollAdjustments)
+				payroll.POST("/:id/adjustments", middleware.RequireRole("admin", "hr"), handlers.CreatePayrollAdjustment)
+			}
+
+			// Withholding tax tables (근로소득 간이세액표)
+			withholdingTax := protected.Group("/withholding-tax-tables")
+			withholdingTax.Use(middleware.RequireRole("admin", "hr"))
+			{
+				withholdingTax.GET("", handlers.GetWithholdingTaxTables)
+				withholdingTax.POST("", middleware.RequireRole("admin"), handlers.ImportWithholdingTaxTable)
+				withholdingTax.GET("/lookup", handlers.LookupWithholdingTax)
+			}
+
+			// Social insurance rates
+			insuranceRates := protected.Group("/insurance-rates")
+			insuranceRates.Use(middleware.RequireRole("admin", "hr"))
+			{
+				insuranceRates.GET("", handlers.GetInsuranceRates)
+				insuranceRates.GET("/current", handlers.GetCurrentInsuranceRates)
+				insuranceRates.POST("", middleware.RequireRole("admin"), handlers.CreateInsuranceRate)
+				insuranceRates.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteInsuranceRate)
+				insuranceRates.GET("/limits", handlers.GetContributionLimits)
+				insuranceRates.POST("/limits", middleware.RequireRole("admin"), handlers.CreateContributionLimit)
+				insuranceRates.GET("/reconciliation", handlers.GetInsuranceReconciliation)
+			}
+
+			// Minimum wage by year
+			minimumWages := protected.Group("/minimum-wages")
+			minimumWages.Use(middleware.RequireRole("admin", "hr"))
+			{
+				minimumWages.GET("", handlers.GetMinimumWages)
+				minimumWages.POST("", middleware.RequireRole("admin"), handlers.CreateMinimumWage)
+				minimumWages.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteMinimumWage)
+				minimumWages.GET("/compliance", handlers.GetMinimumWageCompliance)
+			}
+
+			// Payroll runs
+			payrollRuns := protected.Group("/payroll-runs")
+			payrollRuns.Use(middleware.RequireRole("admin", "hr"))
+			{
+				payrollRuns.GET("", handlers.GetPayrollRuns)
+				payrollRuns.POST("", handlers.CreatePayrollRun)
+				payrollRuns.GET("/:id", handlers.GetPayrollRun)
+				payrollRuns.GET("/:id/minimum-wage", handlers.GetPayrollRunMinimumWage)
+				payrollRuns.POST("/:id/regenerate", handlers.RegeneratePayrollRun)
+				payrollRuns.PUT("/:id/submit", handlers.SubmitPayrollRun)
+				payrollRuns.PUT("/:id/return", handlers.ReturnPayrollRun)
+				payrollRuns.PUT("/:id/approve", middleware.RequireRole("admin"), handlers.ApprovePayrollRun)
+				payrollRuns.PUT("/:id/pay", middleware.RequireRole("admin"), handlers.MarkPayrollRunPaid)
+				payrollRuns.GET("/:id/transfers", handlers.GetPayrollTransferBatches)
+				payrollRuns.POST("/:id/transfers", middleware.RequireRole("admin"), handlers.CreatePayrollTransferBatch)
+				payrollRuns.GET("/:id/transfers/:batchId/file", handlers.DownloadPayrollTransferBatch)
+				payrollRuns.PUT("/:id/transfers/:batchId/transferred", middleware.RequireRole("admin"), handlers.MarkPayrollTransferBatchTransferred)
+				payrollRuns.DELETE("/:id/transfers/:batchId", middleware.RequireRole("admin"), handlers.DeletePayrollTransferBatch)
+			}
+
+			// Year-end tax settlements
+			yearEndSettlements := protected.Group("/year-end-settlements")
+			yearEndSettlements.Use(middleware.RequireRole("admin", "hr"))
+			{
+				yearEndSettlements.GET("", handlers.GetYearEndSettlements)
+				yearEndSettlements.POST("", handlers.CalculateYearEndSettlements)
+				yearEndSettlements.GET("/receipts", handlers.GetWithholdingReceipts)
+				yearEndSettlements.GET("/statement-file", handlers.GetWithholdingStatementFile)
+			}
+
+			// Retroactive payroll adjustments
+			payrollAdjustments := protected.Group("/payroll-adjustments")
+			payrollAdjustments.Use(middleware.RequireRole("admin", "hr"))
+			{
+				payrollAdjustments.GET("", handlers.ListPayrollAdjustments)
+				payrollAdjustments.DELETE("/:id", handlers.DeletePayrollAdjustment)
+			}
+
+			// Severance pay settlements (퇴직금)
+			severanceSettlements := protected.Group("/severance-settlements")
+			severanceSettlements.Use(middleware.RequireRole("admin", "hr"))
+			{
+				severanceSettlements.GET("", handlers.GetSeveranceSettlements)
+				severanceSettlements.POST("", handlers.CreateSeveranceSettlement)
+				severanceSettlements.GET("/:id", handlers.GetSeveranceSettlement)
+				severanceSettlements.PUT("/:id/confirm", handlers.ConfirmSeveranceSettlement)
+				severanceSettlements.PUT("/:id/pay", middleware.RequireRole("admin"), handlers.PaySeveranceSettlement)
+				severanceSettlements.DELETE("/:id", handlers.DeleteSeveranceSettlement)
+			}
+
+			// Defined-contribution retirement pension contributions
+			pensionContributions := protected.Group("/retirement-pension-contributions")
+			pensionContributions.Use(middleware.RequireRole("admin", "hr"))
+			{
+				pensionContributions.GET("", handlers.GetRetirementPensionContributions)
+				pensionContributions.POST("", handlers.CalculateRetirementPensionContributions)
+				pensionContributions.PUT("/:id/paid", middleware.RequireRole("admin"), handlers.RecordRetirementPensionContribution)
 			}
 
 			// Attendance
@@ -177,6 +340,7 @@ func main() {
 				attendance.POST("/clock-in", handlers.ClockIn)
 				attendance.POST("/clock-out", handlers.ClockOut)
 				attendance.GET("/employee/:id", handlers.GetEmployeeAttendance)
+				attendance.PUT("/:id/overtime", middleware.RequireRole("admin", "hr"), handlers.ReviewOvertime)
 			}
 
 			// Leave requests
@@ -193,6 +357,10 @@ func main() {
 			documents := protected.Group("/documents")
 			{
 				documents.GET("/templates", handlers.GetDocumentTemplates)
+				documents.GET("/contract-templates", handlers.GetContractTemplates)
+				documents.POST("/contract-templates", middleware.RequireRole("admin", "hr"), handlers.CreateContractTemplate)
+				documents.PUT("/contract-templates/:id", middleware.RequireRole("admin", "hr"), handlers.UpdateContractTemplate)
+				documents.PUT("/contract-types/:contractType/template", middleware.RequireRole("admin", "hr"), handlers.SetContractTypeTemplate)
 				documents.POST("/generate/:type", handlers.GenerateDocument)
 				documents.GET("/employee/:id", handlers.GetEmployeeDocuments)
 			}
@@ -59,6 +59,11 @@ func InitDatabase(dbPath string) error {
 		return fmt.Errorf("failed to execute schema: %v", err)
 	}
 
+	// Add columns introduced after the initial schema
+	if err = applyColumnMigrations(); err != nil {
+		return fmt.Errorf("failed to apply column migrations: %v", err)
+	}
+
 	log.Println("✅ Database initialized successfully")
 	return nil
 }
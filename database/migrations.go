@@ -0,0 +1,79 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// columnMigration describes a column added to a table after the table was first created.
+// CREATE TABLE IF NOT EXISTS in the schema files does not touch existing tables,
+// so new columns on existing tables are listed here as well.
+type columnMigration struct {
+	Table      string
+	Column     string
+	Definition string
+}
+
+var columnMigrations = []columnMigration{
+	{"employment_contracts", "current_version", "INTEGER DEFAULT 1"},
+	{"employment_contracts", "locked_at", "TIMESTAMP"},
+	{"employment_contracts", "break_time", "TEXT"},
+	{"employment_contracts", "weekly_holiday", "TEXT"},
+	{"employment_contracts", "work_schedule_id", "INTEGER REFERENCES work_schedules(id)"},
+	{"employment_contracts", "previous_contract_id", "INTEGER REFERENCES employment_contracts(id)"},
+	{"employment_contracts", "next_contract_id", "INTEGER REFERENCES employment_contracts(id)"},
+	{"payroll_records", "taxable_pay", "DECIMAL(10,2) DEFAULT 0"},
+	{"payroll_records", "non_taxable_pay", "DECIMAL(10,2) DEFAULT 0"},
+	{"payroll_records", "payroll_run_id", "INTEGER REFERENCES payroll_runs(id)"},
+	{"payroll_records", "unpaid_leave_days", "DECIMAL(5,2) DEFAULT 0"},
+	{"payroll_records", "unpaid_leave_deduction", "DECIMAL(10,2) DEFAULT 0"},
+	{"attendance_logs", "overtime_status", "VARCHAR(20) DEFAULT 'none'"},
+	{"payroll_records", "pension_base", "DECIMAL(12,2) DEFAULT 0"},
+	{"payroll_records", "health_insurance_base", "DECIMAL(12,2) DEFAULT 0"},
+	{"employees", "dependents", "INTEGER DEFAULT 1"},
+	{"employees", "withholding_rate", "INTEGER DEFAULT 100"},
+	{"payroll_records", "salary_type", "VARCHAR(20) DEFAULT 'monthly'"},
+	{"payroll_records", "wage_rate", "DECIMAL(12,0) DEFAULT 0"},
+	{"payroll_records", "worked_hours", "DECIMAL(6,2) DEFAULT 0"},
+	{"payroll_records", "worked_days", "DECIMAL(5,2) DEFAULT 0"},
+	{"payroll_records", "holiday_overtime_hours", "DECIMAL(5,2) DEFAULT 0"},
+	{"payroll_records", "night_hours", "DECIMAL(5,2) DEFAULT 0"},
+	{"payroll_records", "night_pay", "DECIMAL(10,2) DEFAULT 0"},
+	{"payroll_records", "weekly_holiday_hours", "DECIMAL(5,2) DEFAULT 0"},
+	{"payroll_records", "weekly_holiday_pay", "DECIMAL(10,2) DEFAULT 0"},
+	{"payroll_records", "year_end_tax", "DECIMAL(12,0) DEFAULT 0"},
+	{"payroll_records", "year_end_local_tax", "DECIMAL(12,0) DEFAULT 0"},
+	{"employees", "resident_number_encrypted", "TEXT"},
+	{"payroll_records", "adjustment_pay", "DECIMAL(12,0) DEFAULT 0"},
+	{"payroll_records", "adjustment_deductions", "DECIMAL(12,0) DEFAULT 0"},
+	{"allowance_types", "minimum_wage", "BOOLEAN DEFAULT TRUE"},
+	{"employees", "retirement_pension_type", "VARCHAR(20) DEFAULT 'severance'"},
+	{"payroll_transfer_batches", "file_format", "VARCHAR(10) DEFAULT 'csv'"},
+}
+
+// applyColumnMigrations adds any missing columns listed in columnMigrations
+func applyColumnMigrations() error {
+	isPostgres := os.Getenv("DATABASE_URL") != ""
+
+	for _, m := range columnMigrations {
+		var stmt string
+		if isPostgres {
+			stmt = fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", m.Table, m.Column, m.Definition)
+		} else {
+			stmt = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.Table, m.Column, m.Definition)
+		}
+
+		if _, err := DB.Exec(stmt); err != nil {
+			// SQLite has no IF NOT EXISTS for columns
+			if strings.Contains(err.Error(), "duplicate column name") {
+				continue
+			}
+			return fmt.Errorf("failed to add column %s.%s: %v", m.Table, m.Column, err)
+		}
+		log.Printf("Added column %s.%s", m.Table, m.Column)
+	}
+
+	return nil
+}
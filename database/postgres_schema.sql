@@ -24,6 +24,49 @@ CREATE TABLE IF NOT EXISTS employees (
     email VARCHAR(100),
     address TEXT,
     status VARCHAR(20) DEFAULT 'active',
+    dependents INTEGER DEFAULT 1,
+    withholding_rate INTEGER DEFAULT 100,
+    resident_number_encrypted TEXT,
+    retirement_pension_type VARCHAR(20) DEFAULT 'severance',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 근무 스케줄
+CREATE TABLE IF NOT EXISTS work_schedules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    weekly_holiday INTEGER NOT NULL DEFAULT 0,
+    late_grace_minutes INTEGER DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 근무 스케줄 요일별 근무시간
+CREATE TABLE IF NOT EXISTS work_schedule_days (
+    id SERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES work_schedules(id),
+    weekday INTEGER NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    breaks TEXT,
+    UNIQUE(schedule_id, weekday)
+);
+
+-- 수당 항목
+CREATE TABLE IF NOT EXISTS allowance_types (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(30) UNIQUE NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    payment_type VARCHAR(20) DEFAULT 'fixed',
+    taxable BOOLEAN DEFAULT TRUE,
+    non_taxable_limit DECIMAL(12,2) DEFAULT 0,
+    ordinary_wage BOOLEAN DEFAULT FALSE,
+    minimum_wage BOOLEAN DEFAULT TRUE,
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -38,8 +81,75 @@ CREATE TABLE IF NOT EXISTS employment_contracts (
     salary DECIMAL(12,2) NOT NULL,
     working_hours INTEGER DEFAULT 8,
     work_days VARCHAR(50) DEFAULT '월~금',
+    break_time TEXT,
+    weekly_holiday TEXT,
+    work_schedule_id INTEGER REFERENCES work_schedules(id),
     contract_terms TEXT,
     status VARCHAR(20) DEFAULT 'active',
+    locked_at TIMESTAMP,
+    current_version INTEGER DEFAULT 1,
+    previous_contract_id INTEGER REFERENCES employment_contracts(id),
+    next_contract_id INTEGER REFERENCES employment_contracts(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 근로계약별 수당 항목
+CREATE TABLE IF NOT EXISTS contract_allowances (
+    id SERIAL PRIMARY KEY,
+    contract_id INTEGER NOT NULL REFERENCES employment_contracts(id),
+    allowance_type_id INTEGER NOT NULL REFERENCES allowance_types(id),
+    amount DECIMAL(12,2) NOT NULL,
+    UNIQUE(contract_id, allowance_type_id)
+);
+
+-- 근로계약 버전 (최초 계약 및 변경계약)
+CREATE TABLE IF NOT EXISTS contract_versions (
+    id SERIAL PRIMARY KEY,
+    contract_id INTEGER NOT NULL REFERENCES employment_contracts(id),
+    version INTEGER NOT NULL,
+    effective_date DATE NOT NULL,
+    terms TEXT NOT NULL,
+    changes TEXT,
+    reason TEXT,
+    signed_date DATE,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(contract_id, version)
+);
+
+-- 근로계약 전자서명 요청 (일회용 서명 링크)
+CREATE TABLE IF NOT EXISTS contract_signature_requests (
+    id SERIAL PRIMARY KEY,
+    contract_id INTEGER NOT NULL REFERENCES employment_contracts(id),
+    version INTEGER NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    signer_name VARCHAR(50),
+    signature_type VARCHAR(20),
+    signature_data TEXT,
+    signer_ip VARCHAR(45),
+    signer_user_agent TEXT,
+    document_hash VARCHAR(64),
+    signed_at TIMESTAMP,
+    signed_file_path VARCHAR(255),
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 급여 정산 (월별 급여 대장)
+CREATE TABLE IF NOT EXISTS payroll_runs (
+    id SERIAL PRIMARY KEY,
+    pay_period VARCHAR(7) NOT NULL UNIQUE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    pay_date DATE,
+    status VARCHAR(20) DEFAULT 'draft',
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    approved_by INTEGER REFERENCES users(id),
+    approved_at TIMESTAMP,
+    paid_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -48,20 +158,40 @@ CREATE TABLE IF NOT EXISTS employment_contracts (
 CREATE TABLE IF NOT EXISTS payroll_records (
     id SERIAL PRIMARY KEY,
     employee_id INTEGER NOT NULL REFERENCES employees(id),
+    payroll_run_id INTEGER REFERENCES payroll_runs(id),
     pay_period VARCHAR(7) NOT NULL,
     pay_period_start DATE,
     pay_period_end DATE,
+    salary_type VARCHAR(20) DEFAULT 'monthly', -- 급여 형태 (monthly, hourly, daily)
+    wage_rate DECIMAL(12,0) DEFAULT 0, -- 월급, 시급 또는 일급
+    worked_hours DECIMAL(6,2) DEFAULT 0, -- 시급제 근로시간
+    worked_days DECIMAL(5,2) DEFAULT 0, -- 일급제 근무일수
     base_salary DECIMAL(12,2) NOT NULL,
+    holiday_overtime_hours DECIMAL(5,2) DEFAULT 0, -- 휴일근로 8시간 초과
+    night_hours DECIMAL(5,2) DEFAULT 0, -- 야간근로 (22:00~06:00)
+    night_pay DECIMAL(12,2) DEFAULT 0, -- 야간근로 가산수당
+    weekly_holiday_hours DECIMAL(5,2) DEFAULT 0, -- 주휴시간
+    weekly_holiday_pay DECIMAL(12,2) DEFAULT 0, -- 주휴수당
     allowances DECIMAL(12,2) DEFAULT 0,
     bonus DECIMAL(12,2) DEFAULT 0,
+    unpaid_leave_days DECIMAL(5,2) DEFAULT 0,
+    unpaid_leave_deduction DECIMAL(12,2) DEFAULT 0,
     gross_pay DECIMAL(12,2) NOT NULL,
+    taxable_pay DECIMAL(12,2) DEFAULT 0,
+    non_taxable_pay DECIMAL(12,2) DEFAULT 0,
     income_tax DECIMAL(12,2) DEFAULT 0,
     local_tax DECIMAL(12,2) DEFAULT 0,
     national_pension DECIMAL(12,2) DEFAULT 0,
     health_insurance DECIMAL(12,2) DEFAULT 0,
+    pension_base DECIMAL(12,2) DEFAULT 0,
+    health_insurance_base DECIMAL(12,2) DEFAULT 0,
     employment_insurance DECIMAL(12,2) DEFAULT 0,
     long_term_care DECIMAL(12,2) DEFAULT 0,
     other_deductions DECIMAL(12,2) DEFAULT 0,
+    year_end_tax DECIMAL(12,0) DEFAULT 0, -- 연말정산 소득세 (환급은 음수)
+    year_end_local_tax DECIMAL(12,0) DEFAULT 0, -- 연말정산 지방소득세
+    adjustment_pay DECIMAL(12,0) DEFAULT 0,
+    adjustment_deductions DECIMAL(12,0) DEFAULT 0,
     total_deductions DECIMAL(12,2) NOT NULL,
     net_pay DECIMAL(12,2) NOT NULL,
     pay_date DATE,
@@ -70,6 +200,242 @@ CREATE TABLE IF NOT EXISTS payroll_records (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- 급여 수당 항목별 지급 내역
+CREATE TABLE IF NOT EXISTS payroll_allowance_items (
+    id SERIAL PRIMARY KEY,
+    payroll_id INTEGER NOT NULL REFERENCES payroll_records(id),
+    allowance_type_id INTEGER NOT NULL REFERENCES allowance_types(id),
+    name VARCHAR(50) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    taxable_amount DECIMAL(12,2) DEFAULT 0,
+    non_taxable_amount DECIMAL(12,2) DEFAULT 0
+);
+
+-- 근로소득 간이세액표 (국세청, 시행일별 버전)
+CREATE TABLE IF NOT EXISTS withholding_tax_tables (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    effective_date DATE NOT NULL UNIQUE,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 간이세액표 구간별 세액 (월급여액 이상/미만, 공제대상가족 수)
+CREATE TABLE IF NOT EXISTS withholding_tax_brackets (
+    id SERIAL PRIMARY KEY,
+    table_id INTEGER NOT NULL REFERENCES withholding_tax_tables(id),
+    pay_from DECIMAL(12,0) NOT NULL,
+    pay_to DECIMAL(12,0) NOT NULL,
+    dependents INTEGER NOT NULL,
+    tax_amount DECIMAL(12,0) NOT NULL
+);
+
+-- 최저임금 (연도별 시급)
+CREATE TABLE IF NOT EXISTS minimum_wages (
+    id SERIAL PRIMARY KEY,
+    year INTEGER UNIQUE NOT NULL,
+    hourly_wage DECIMAL(12,0) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 4대보험 요율 (시행일별)
+CREATE TABLE IF NOT EXISTS insurance_rates (
+    id SERIAL PRIMARY KEY,
+    insurance_type VARCHAR(30) NOT NULL,
+    effective_date DATE NOT NULL,
+    employee_rate DECIMAL(8,6) NOT NULL,
+    employer_rate DECIMAL(8,6) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(insurance_type, effective_date)
+);
+
+-- 4대보험 기준소득월액(보수월액) 상·하한 (시행일별)
+CREATE TABLE IF NOT EXISTS insurance_contribution_limits (
+    id SERIAL PRIMARY KEY,
+    insurance_type VARCHAR(30) NOT NULL,
+    effective_date DATE NOT NULL,
+    lower_limit DECIMAL(12,0) NOT NULL,
+    upper_limit DECIMAL(12,0) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(insurance_type, effective_date)
+);
+
+-- 직원별 4대보험 신고 기준소득월액(보수월액)
+CREATE TABLE IF NOT EXISTS employee_insurance_bases (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    insurance_type VARCHAR(30) NOT NULL,
+    effective_date DATE NOT NULL,
+    reported_amount DECIMAL(12,0) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(employee_id, insurance_type, effective_date)
+);
+
+-- 연말정산
+CREATE TABLE IF NOT EXISTS year_end_settlements (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    tax_year INTEGER NOT NULL,
+    insurance_premiums DECIMAL(12,0) DEFAULT 0,
+    medical_expenses DECIMAL(12,0) DEFAULT 0,
+    medical_expenses_special DECIMAL(12,0) DEFAULT 0,
+    education_expenses DECIMAL(12,0) DEFAULT 0,
+    donations DECIMAL(12,0) DEFAULT 0,
+    credit_card_spending DECIMAL(12,0) DEFAULT 0,
+    debit_card_spending DECIMAL(12,0) DEFAULT 0,
+    dependents INTEGER DEFAULT 1,
+    total_pay DECIMAL(14,0) DEFAULT 0,
+    non_taxable_pay DECIMAL(14,0) DEFAULT 0,
+    earned_income_deduction DECIMAL(14,0) DEFAULT 0,
+    earned_income_amount DECIMAL(14,0) DEFAULT 0,
+    personal_deduction DECIMAL(14,0) DEFAULT 0,
+    pension_deduction DECIMAL(14,0) DEFAULT 0,
+    special_income_deduction DECIMAL(14,0) DEFAULT 0,
+    card_deduction DECIMAL(14,0) DEFAULT 0,
+    tax_base DECIMAL(14,0) DEFAULT 0,
+    calculated_tax DECIMAL(14,0) DEFAULT 0,
+    earned_income_tax_credit DECIMAL(14,0) DEFAULT 0,
+    special_tax_credit DECIMAL(14,0) DEFAULT 0,
+    standard_tax_credit DECIMAL(14,0) DEFAULT 0,
+    determined_tax DECIMAL(14,0) DEFAULT 0,
+    determined_local_tax DECIMAL(14,0) DEFAULT 0,
+    withheld_tax DECIMAL(14,0) DEFAULT 0,
+    withheld_local_tax DECIMAL(14,0) DEFAULT 0,
+    balance_tax DECIMAL(14,0) DEFAULT 0,
+    balance_local_tax DECIMAL(14,0) DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'draft',
+    payroll_id INTEGER REFERENCES payroll_records(id),
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(employee_id, tax_year)
+);
+
+-- 급여 소급·정정
+CREATE TABLE IF NOT EXISTS payroll_adjustments (
+    id SERIAL PRIMARY KEY,
+    original_payroll_id INTEGER NOT NULL REFERENCES payroll_records(id),
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    reason TEXT NOT NULL,
+    wage_rate DECIMAL(12,0) NOT NULL,
+    worked_hours DECIMAL(6,2) DEFAULT 0,
+    worked_days DECIMAL(5,2) DEFAULT 0,
+    overtime_hours DECIMAL(6,2) DEFAULT 0,
+    holiday_hours DECIMAL(6,2) DEFAULT 0,
+    holiday_overtime_hours DECIMAL(6,2) DEFAULT 0,
+    night_hours DECIMAL(6,2) DEFAULT 0,
+    unpaid_leave_days DECIMAL(5,2) DEFAULT 0,
+    base_salary DECIMAL(12,0) DEFAULT 0,
+    overtime_pay DECIMAL(12,0) DEFAULT 0,
+    holiday_pay DECIMAL(12,0) DEFAULT 0,
+    night_pay DECIMAL(12,0) DEFAULT 0,
+    weekly_holiday_pay DECIMAL(12,0) DEFAULT 0,
+    allowances DECIMAL(12,0) DEFAULT 0,
+    bonus DECIMAL(12,0) DEFAULT 0,
+    unpaid_leave_deduction DECIMAL(12,0) DEFAULT 0,
+    gross_pay DECIMAL(12,0) DEFAULT 0,
+    taxable_pay DECIMAL(12,0) DEFAULT 0,
+    non_taxable_pay DECIMAL(12,0) DEFAULT 0,
+    income_tax DECIMAL(12,0) DEFAULT 0,
+    local_tax DECIMAL(12,0) DEFAULT 0,
+    national_pension DECIMAL(12,0) DEFAULT 0,
+    health_insurance DECIMAL(12,0) DEFAULT 0,
+    long_term_care DECIMAL(12,0) DEFAULT 0,
+    employment_insurance DECIMAL(12,0) DEFAULT 0,
+    other_deductions DECIMAL(12,0) DEFAULT 0,
+    total_deductions DECIMAL(12,0) DEFAULT 0,
+    net_pay DECIMAL(12,0) DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'pending',
+    applied_payroll_id INTEGER REFERENCES payroll_records(id),
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP
+);
+
+-- 퇴직금 정산
+CREATE TABLE IF NOT EXISTS severance_settlements (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    retirement_date DATE NOT NULL,
+    pension_type VARCHAR(20) NOT NULL,
+    service_start DATE NOT NULL,
+    service_end DATE NOT NULL,
+    excluded_days INTEGER DEFAULT 0,
+    service_days INTEGER NOT NULL,
+    average_daily_wage DECIMAL(12,2) DEFAULT 0,
+    severance_pay DECIMAL(14,0) DEFAULT 0,
+    dc_contributions DECIMAL(14,0) DEFAULT 0,
+    dc_shortfall DECIMAL(14,0) DEFAULT 0,
+    retirement_income DECIMAL(14,0) DEFAULT 0,
+    service_years INTEGER DEFAULT 0,
+    service_years_deduction DECIMAL(14,0) DEFAULT 0,
+    converted_pay DECIMAL(14,0) DEFAULT 0,
+    converted_pay_deduction DECIMAL(14,0) DEFAULT 0,
+    tax_base DECIMAL(14,0) DEFAULT 0,
+    converted_tax DECIMAL(14,0) DEFAULT 0,
+    income_tax DECIMAL(14,0) DEFAULT 0,
+    local_tax DECIMAL(14,0) DEFAULT 0,
+    company_payment DECIMAL(14,0) DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'draft',
+    pay_date DATE,
+    created_by INTEGER REFERENCES users(id),
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(employee_id, retirement_date)
+);
+
+-- 확정기여형 퇴직연금 연간 부담금
+CREATE TABLE IF NOT EXISTS retirement_pension_contributions (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    plan_year INTEGER NOT NULL,
+    annual_wages DECIMAL(14,0) DEFAULT 0,
+    required_amount DECIMAL(14,0) DEFAULT 0,
+    paid_amount DECIMAL(14,0) DEFAULT 0,
+    paid_date DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(employee_id, plan_year)
+);
+
+-- 급여 이체 계좌 (계좌번호는 암호화하여 저장)
+CREATE TABLE IF NOT EXISTS employee_bank_accounts (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL UNIQUE REFERENCES employees(id),
+    bank_code VARCHAR(3) NOT NULL,
+    account_number_encrypted TEXT NOT NULL,
+    account_holder VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 급여 대량이체 파일
+CREATE TABLE IF NOT EXISTS payroll_transfer_batches (
+    id SERIAL PRIMARY KEY,
+    payroll_run_id INTEGER NOT NULL REFERENCES payroll_runs(id),
+    bank_code VARCHAR(3) NOT NULL,
+    transfer_date DATE NOT NULL,
+    record_count INTEGER NOT NULL,
+    total_amount DECIMAL(14,0) NOT NULL,
+    file_format VARCHAR(10) DEFAULT 'csv', -- csv, fixed
+    file_content BYTEA NOT NULL,
+    status VARCHAR(20) DEFAULT 'exported',
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    transferred_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS payroll_transfer_items (
+    id SERIAL PRIMARY KEY,
+    batch_id INTEGER NOT NULL REFERENCES payroll_transfer_batches(id),
+    payroll_id INTEGER NOT NULL REFERENCES payroll_records(id),
+    amount DECIMAL(12,0) NOT NULL
+);
+
 -- 근태 기록
 CREATE TABLE IF NOT EXISTS attendance_logs (
     id SERIAL PRIMARY KEY,
@@ -81,6 +447,7 @@ CREATE TABLE IF NOT EXISTS attendance_logs (
     break_end TIME,
     total_hours DECIMAL(4,2),
     overtime_hours DECIMAL(4,2) DEFAULT 0,
+    overtime_status VARCHAR(20) DEFAULT 'none',
     status VARCHAR(20) DEFAULT 'present',
     notes TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -130,6 +497,13 @@ CREATE TABLE IF NOT EXISTS document_templates (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- 근로계약 유형별 계약서 템플릿
+CREATE TABLE IF NOT EXISTS contract_type_templates (
+    contract_type VARCHAR(30) PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES document_templates(id),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- 생성된 문서 기록
 CREATE TABLE IF NOT EXISTS generated_documents (
     id SERIAL PRIMARY KEY,
@@ -159,6 +533,12 @@ CREATE INDEX IF NOT EXISTS idx_payroll_employee_period ON payroll_records(employ
 CREATE INDEX IF NOT EXISTS idx_attendance_employee_date ON attendance_logs(employee_id, work_date);
 CREATE INDEX IF NOT EXISTS idx_leave_requests_employee ON leave_requests(employee_id);
 CREATE INDEX IF NOT EXISTS idx_leave_requests_status ON leave_requests(status);
+CREATE INDEX IF NOT EXISTS idx_contract_versions_contract ON contract_versions(contract_id);
+CREATE INDEX IF NOT EXISTS idx_contract_signature_requests_contract ON contract_signature_requests(contract_id);
+CREATE INDEX IF NOT EXISTS idx_contract_allowances_contract ON contract_allowances(contract_id);
+CREATE INDEX IF NOT EXISTS idx_payroll_allowance_items_payroll ON payroll_allowance_items(payroll_id);
+CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_employee ON payroll_adjustments(employee_id, status);
+CREATE INDEX IF NOT EXISTS idx_withholding_tax_brackets_table ON withholding_tax_brackets(table_id, pay_from);
 
 -- 기본 데이터 삽입
 INSERT INTO system_settings (setting_key, setting_value, description) VALUES
@@ -166,12 +546,269 @@ INSERT INTO system_settings (setting_key, setting_value, description) VALUES
 ('company_address', '서울특별시 강남구 테헤란로 123', '회사 주소'),
 ('company_phone', '02-1234-5678', '회사 전화번호'),
 ('company_registration_number', '123-45-67890', '사업자등록번호'),
-('min_wage', '9860', '최저임금 (시급)'),
+('company_representative', '', '대표자 성명'),
+('tax_office_code', '', '관할 세무서 코드'),
+('hometax_id', '', '홈택스 사용자 ID (지급명세서 제출자)'),
+('payroll_bank_code', '004', '급여 출금 은행 코드'),
 ('work_hours_per_day', '8', '1일 근무시간'),
 ('work_days_per_week', '5', '주 근무일수'),
-('annual_leave_base', '15', '기본 연차 일수')
+('annual_leave_base', '15', '기본 연차 일수'),
+('severance_excluded_leave_types', 'personal', '퇴직금 근속기간에서 제외하는 휴가 유형 (쉼표 구분)')
 ON CONFLICT (setting_key) DO NOTHING;
 
+-- 문서 생성 기록(generated_documents)이 참조하는 기본 템플릿
+INSERT INTO document_templates (id, name, type, content) VALUES
+(1, '급여명세서', 'payslip', ''),
+(2, '재직증명서', 'employment_certificate', ''),
+(3, '근로계약서', 'employment_contract', '')
+ON CONFLICT (id) DO NOTHING;
+
+-- 근로계약 유형별 표준근로계약서 (고용노동부 표준근로계약서 양식)
+INSERT INTO document_templates (id, name, type, content, variables) VALUES
+(4, '표준근로계약서 (기간의 정함이 없는 경우)', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로개시일
+{{contract_start_date}}부터
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+## 5. 근무일/휴일
+매주 {{work_days}} 근무, 주휴일 매주 {{weekly_holiday}}
+## 6. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 9. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 10. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(5, '표준근로계약서 (기간의 정함이 있는 경우)', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+## 5. 근무일/휴일
+매주 {{work_days}} 근무, 주휴일 매주 {{weekly_holiday}}
+## 6. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 9. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 10. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "contract_end_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(6, '단시간근로자 표준근로계약서', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 근로일 및 근로일별 근로시간
+{{daily_schedule}}
+주 소정근로시간: {{weekly_hours}}시간
+주휴일: 매주 {{weekly_holiday}}
+## 5. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+- 초과근로에 대한 가산임금률: 단시간근로자와 사용자 사이에 근로하기로 정한 시간을 초과하여 근로하면 법정 근로시간 내라도 통상임금의 100분의 50 이상의 가산임금 지급 (기간제 및 단시간근로자 보호 등에 관한 법률 제6조)
+## 6. 연차유급휴가
+통상근로자의 근로시간에 비례하여 연차유급휴가 부여
+## 7. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 8. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 9. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "contract_end_date", "workplace", "job_description", "daily_schedule", "weekly_hours", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(7, '연소근로자(18세 미만인 자) 표준근로계약서', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+근로자 생년월일: {{employee_birth_date}}
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+연소근로자의 근로시간은 1일 7시간, 1주 35시간을 초과할 수 없음 (근로기준법 제69조)
+## 5. 근무일/휴일
+매주 {{work_days}} 근무, 주휴일 매주 {{weekly_holiday}}
+## 6. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 가족관계증명서 및 동의서
+- 가족관계기록사항에 관한 증명서 제출 여부: 제출
+- 친권자 또는 후견인의 동의서 구비 여부: 구비
+13세 이상 15세 미만인 자에 대해서는 고용노동부장관으로부터 취직인허증을 교부받아야 함
+## 9. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 10. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 11. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 12. 기타 유의사항
+18세 미만인 자의 야간근로와 휴일근로는 본인의 동의와 고용노동부장관의 인가를 받아야 함 (근로기준법 제70조)
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "employee_birth_date", "contract_start_date", "contract_end_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(8, '건설일용근로자 표준근로계약서', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소 (현장)
+{{workplace}}
+## 3. 업무의 내용 (직종)
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+## 5. 근무일/휴일
+{{work_days}} 근무, 주휴일 매주 {{weekly_holiday}} (해당자에 한함)
+## 6. 임금
+- 일급: {{base_salary}}
+- 수당: {{allowances}}
+- 연장, 야간, 휴일근로에 대해서는 통상임금의 100분의 50을 가산하여 지급
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 사회보험 적용여부
+고용보험, 산재보험 (국민연금, 건강보험은 관계 법령에 따른 적용 대상인 경우)
+## 9. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 10. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "contract_end_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO contract_type_templates (contract_type, template_id) VALUES
+('permanent', 4),
+('temporary', 5),
+('contract', 5),
+('intern', 5),
+('part_time', 6),
+('minor', 7),
+('construction_daily', 8)
+ON CONFLICT (contract_type) DO NOTHING;
+
+-- 기본 근무 스케줄 (주 5일, 09:00-18:00, 일요일 주휴)
+INSERT INTO work_schedules (id, name, description, weekly_holiday) VALUES
+(1, '주 5일 통상근무', '월-금 09:00-18:00, 휴게 12:00-13:00', 0)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO work_schedule_days (schedule_id, weekday, start_time, end_time, breaks) VALUES
+(1, 1, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 2, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 3, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 4, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 5, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]')
+ON CONFLICT (schedule_id, weekday) DO NOTHING;
+
+-- 고정 id로 넣은 기본 데이터 이후부터 SERIAL이 이어지도록 시퀀스 조정
+SELECT setval(pg_get_serial_sequence('document_templates', 'id'), (SELECT MAX(id) FROM document_templates));
+SELECT setval(pg_get_serial_sequence('work_schedules', 'id'), (SELECT MAX(id) FROM work_schedules));
+
+-- 기본 수당 항목 (비과세 한도: 소득세법 시행령 제12조, 2024년 기준)
+INSERT INTO allowance_types (code, name, payment_type, taxable, non_taxable_limit, ordinary_wage, minimum_wage, description) VALUES
+('meal', '식대', 'fixed', FALSE, 200000, TRUE, TRUE, '월 20만원 이하 비과세'),
+('car', '자가운전보조금', 'fixed', FALSE, 200000, FALSE, FALSE, '본인 차량으로 업무 수행 시 월 20만원 이하 비과세'),
+('childcare', '보육수당', 'fixed', FALSE, 200000, FALSE, TRUE, '6세 이하 자녀 보육 관련 월 20만원 이하 비과세'),
+('position', '직책수당', 'fixed', TRUE, 0, TRUE, TRUE, NULL),
+('family', '가족수당', 'fixed', TRUE, 0, FALSE, TRUE, NULL),
+('incentive', '성과수당', 'variable', TRUE, 0, FALSE, FALSE, NULL)
+ON CONFLICT (code) DO NOTHING;
+
+-- 최저임금 (최저임금위원회 고시)
+INSERT INTO minimum_wages (year, hourly_wage, description) VALUES
+(2020, 8590, '월 1,795,310원 (209시간 기준)'),
+(2021, 8720, '월 1,822,480원 (209시간 기준)'),
+(2022, 9160, '월 1,914,440원 (209시간 기준)'),
+(2023, 9620, '월 2,010,580원 (209시간 기준)'),
+(2024, 9860, '월 2,060,740원 (209시간 기준)'),
+(2025, 10030, '월 2,096,270원 (209시간 기준)'),
+(2026, 10320, '월 2,156,880원 (209시간 기준)')
+ON CONFLICT (year) DO NOTHING;
+
+-- 4대보험 요율 (근로자/사업주 부담률)
+INSERT INTO insurance_rates (insurance_type, effective_date, employee_rate, employer_rate, description) VALUES
+('national_pension', '2024-01-01', 0.045, 0.045, '국민연금 9%'),
+('health_insurance', '2024-01-01', 0.03545, 0.03545, '건강보험 7.09%'),
+('long_term_care', '2024-01-01', 0.1295, 0.1295, '장기요양보험 건강보험료의 12.95%'),
+('employment_insurance', '2024-01-01', 0.009, 0.0115, '고용보험 실업급여 1.8%, 고용안정·직업능력개발 0.25% (150인 미만)'),
+('national_pension', '2026-01-01', 0.0475, 0.0475, '국민연금 9.5%'),
+('health_insurance', '2026-01-01', 0.03595, 0.03595, '건강보험 7.19%'),
+('long_term_care', '2026-01-01', 0.1314, 0.1314, '장기요양보험 건강보험료의 13.14%')
+ON CONFLICT (insurance_type, effective_date) DO NOTHING;
+
+-- 기준소득월액 상·하한 (국민연금: 매년 7월 조정, 건강보험: 보수월액 기준)
+INSERT INTO insurance_contribution_limits (insurance_type, effective_date, lower_limit, upper_limit) VALUES
+('national_pension', '2023-07-01', 370000, 5900000),
+('national_pension', '2024-07-01', 390000, 6170000),
+('national_pension', '2025-07-01', 400000, 6370000),
+('health_insurance', '2024-01-01', 279266, 119625106)
+ON CONFLICT (insurance_type, effective_date) DO NOTHING;
+
 -- 관리자 계정 생성 (비밀번호: admin123)
 INSERT INTO users (username, password_hash, email, role) VALUES
 ('admin', '$2a$10$BGuuHyAsIfgXDObMqhNUwOnfY4oK56B50BVx1NoZWL0y9kRmsdYji', 'admin@company.com', 'admin')
@@ -29,11 +29,55 @@ CREATE TABLE IF NOT EXISTS employees (
     status VARCHAR(20) DEFAULT 'active', -- active, inactive, terminated
     salary_type VARCHAR(20) DEFAULT 'monthly', -- monthly, hourly, daily
     base_salary DECIMAL(10,2),
+    dependents INTEGER DEFAULT 1, -- 공제대상가족 수 (본인 포함)
+    withholding_rate INTEGER DEFAULT 100, -- 원천징수 비율 (80, 100, 120%)
+    resident_number_encrypted TEXT, -- 주민등록번호 (AES-256-GCM 암호화)
+    retirement_pension_type VARCHAR(20) DEFAULT 'severance', -- 퇴직급여 제도 (severance: 퇴직금, db: 확정급여형, dc: 확정기여형)
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
 
+-- 근무 스케줄
+CREATE TABLE IF NOT EXISTS work_schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    weekly_holiday INTEGER NOT NULL DEFAULT 0, -- 주휴일 요일 (0=일요일 ... 6=토요일)
+    late_grace_minutes INTEGER DEFAULT 0, -- 지각 유예 시간(분)
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 근무 스케줄 요일별 근무시간 (등록되지 않은 요일은 휴무일)
+CREATE TABLE IF NOT EXISTS work_schedule_days (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    schedule_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL, -- 0=일요일 ... 6=토요일
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL, -- 시작 시각보다 이르면 다음 날
+    breaks TEXT, -- JSON format, [{"start":"12:00","end":"13:00"}]
+    FOREIGN KEY (schedule_id) REFERENCES work_schedules(id),
+    UNIQUE(schedule_id, weekday)
+);
+
+-- 수당 항목 (식대, 자가운전보조금, 보육수당, 직책수당 등)
+CREATE TABLE IF NOT EXISTS allowance_types (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(30) UNIQUE NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    payment_type VARCHAR(20) DEFAULT 'fixed', -- fixed(고정), variable(변동)
+    taxable BOOLEAN DEFAULT TRUE, -- 과세 여부
+    non_taxable_limit DECIMAL(10,2) DEFAULT 0, -- 비과세 월 한도 (비과세 항목만)
+    ordinary_wage BOOLEAN DEFAULT FALSE, -- 통상임금 포함 여부
+    minimum_wage BOOLEAN DEFAULT TRUE, -- 최저임금 산입 여부 (매월 정기 지급하는 고정 수당만 산입)
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
 -- 근로계약서
 CREATE TABLE IF NOT EXISTS employment_contracts (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -45,47 +89,401 @@ CREATE TABLE IF NOT EXISTS employment_contracts (
     job_description TEXT,
     working_hours TEXT NOT NULL,
     work_days TEXT NOT NULL,
+    break_time TEXT, -- 휴게시간 (예: 12:00-13:00)
+    weekly_holiday TEXT, -- 주휴일 (예: 일요일)
+    work_schedule_id INTEGER, -- 근무 스케줄 (지정 시 근로시간 관련 항목은 스케줄에서 생성)
     base_salary DECIMAL(10,2) NOT NULL,
     allowances TEXT, -- JSON format
     benefits TEXT, -- JSON format
     contract_terms TEXT,
     signed_date DATE,
     is_active BOOLEAN DEFAULT TRUE,
+    locked_at DATETIME, -- 전자서명 완료 시 잠금
+    current_version INTEGER DEFAULT 1, -- 현재 적용 중인 계약 버전
+    previous_contract_id INTEGER, -- 갱신 전 계약
+    next_contract_id INTEGER, -- 갱신 후 계약
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (employee_id) REFERENCES employees(id)
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    FOREIGN KEY (work_schedule_id) REFERENCES work_schedules(id),
+    FOREIGN KEY (previous_contract_id) REFERENCES employment_contracts(id),
+    FOREIGN KEY (next_contract_id) REFERENCES employment_contracts(id)
+);
+
+-- 근로계약별 수당 항목
+CREATE TABLE IF NOT EXISTS contract_allowances (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contract_id INTEGER NOT NULL,
+    allowance_type_id INTEGER NOT NULL,
+    amount DECIMAL(10,2) NOT NULL, -- 월 지급액 (변동 수당은 기준 금액)
+    FOREIGN KEY (contract_id) REFERENCES employment_contracts(id),
+    FOREIGN KEY (allowance_type_id) REFERENCES allowance_types(id),
+    UNIQUE(contract_id, allowance_type_id)
+);
+
+-- 근로계약 버전 (최초 계약 및 변경계약)
+CREATE TABLE IF NOT EXISTS contract_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contract_id INTEGER NOT NULL,
+    version INTEGER NOT NULL, -- 1 = 최초 계약, 2 이상 = 변경계약
+    effective_date DATE NOT NULL, -- 변경계약 효력 발생일
+    terms TEXT NOT NULL, -- JSON format, 해당 버전의 전체 계약 조건
+    changes TEXT, -- JSON format, 직전 버전 대비 변경 항목
+    reason TEXT,
+    signed_date DATE,
+    created_by INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contract_id) REFERENCES employment_contracts(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    UNIQUE(contract_id, version)
+);
+
+-- 근로계약 전자서명 요청 (일회용 서명 링크)
+CREATE TABLE IF NOT EXISTS contract_signature_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contract_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- 서명 링크 토큰의 SHA-256
+    status VARCHAR(20) DEFAULT 'pending', -- pending, signed, cancelled, expired
+    expires_at DATETIME NOT NULL,
+    signer_name VARCHAR(50),
+    signature_type VARCHAR(20), -- drawn, typed
+    signature_data TEXT, -- 서명 이미지(data URL) 또는 입력한 성명
+    signer_ip VARCHAR(45),
+    signer_user_agent TEXT,
+    document_hash VARCHAR(64), -- 서명 대상 문서의 SHA-256
+    signed_at DATETIME,
+    signed_file_path VARCHAR(255),
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contract_id) REFERENCES employment_contracts(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+-- 급여 정산 (월별 급여 대장)
+CREATE TABLE IF NOT EXISTS payroll_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pay_period VARCHAR(7) NOT NULL UNIQUE, -- YYYY-MM
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    pay_date DATE,
+    status VARCHAR(20) DEFAULT 'draft', -- draft, review, approved, paid
+    created_by INTEGER NOT NULL,
+    approved_by INTEGER,
+    approved_at DATETIME,
+    paid_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (approved_by) REFERENCES users(id)
 );
 
 -- 급여 정보
 CREATE TABLE IF NOT EXISTS payroll_records (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     employee_id INTEGER NOT NULL,
+    payroll_run_id INTEGER, -- 급여 정산에서 생성된 경우
     pay_period_start DATE NOT NULL,
     pay_period_end DATE NOT NULL,
+    salary_type VARCHAR(20) DEFAULT 'monthly', -- 급여 형태 (monthly, hourly, daily)
+    wage_rate DECIMAL(12,0) DEFAULT 0, -- 월급, 시급 또는 일급
+    worked_hours DECIMAL(6,2) DEFAULT 0, -- 시급제 근로시간
+    worked_days DECIMAL(5,2) DEFAULT 0, -- 일급제 근무일수
     base_salary DECIMAL(10,2) NOT NULL,
     overtime_hours DECIMAL(5,2) DEFAULT 0,
     overtime_pay DECIMAL(10,2) DEFAULT 0,
     holiday_hours DECIMAL(5,2) DEFAULT 0,
     holiday_pay DECIMAL(10,2) DEFAULT 0,
+    holiday_overtime_hours DECIMAL(5,2) DEFAULT 0, -- 휴일근로 8시간 초과
+    night_hours DECIMAL(5,2) DEFAULT 0, -- 야간근로 (22:00~06:00)
+    night_pay DECIMAL(10,2) DEFAULT 0, -- 야간근로 가산수당
+    weekly_holiday_hours DECIMAL(5,2) DEFAULT 0, -- 주휴시간
+    weekly_holiday_pay DECIMAL(10,2) DEFAULT 0, -- 주휴수당
     allowances DECIMAL(10,2) DEFAULT 0, -- 각종 수당
     bonus DECIMAL(10,2) DEFAULT 0,
+    unpaid_leave_days DECIMAL(5,2) DEFAULT 0, -- 무급휴가 일수
+    unpaid_leave_deduction DECIMAL(10,2) DEFAULT 0, -- 무급휴가 공제
     gross_pay DECIMAL(10,2) NOT NULL, -- 총 지급액
+    taxable_pay DECIMAL(10,2) DEFAULT 0, -- 과세 급여
+    non_taxable_pay DECIMAL(10,2) DEFAULT 0, -- 비과세 급여
     income_tax DECIMAL(10,2) DEFAULT 0, -- 소득세
     local_tax DECIMAL(10,2) DEFAULT 0, -- 지방소득세
     national_pension DECIMAL(10,2) DEFAULT 0, -- 국민연금
     health_insurance DECIMAL(10,2) DEFAULT 0, -- 건강보험
+    pension_base DECIMAL(12,2) DEFAULT 0, -- 국민연금 기준소득월액
+    health_insurance_base DECIMAL(12,2) DEFAULT 0, -- 건강보험 보수월액
     employment_insurance DECIMAL(10,2) DEFAULT 0, -- 고용보험
     long_term_care DECIMAL(10,2) DEFAULT 0, -- 장기요양보험
     other_deductions DECIMAL(10,2) DEFAULT 0, -- 기타 공제
+    year_end_tax DECIMAL(12,0) DEFAULT 0, -- 연말정산 소득세 (환급은 음수)
+    year_end_local_tax DECIMAL(12,0) DEFAULT 0, -- 연말정산 지방소득세
+    adjustment_pay DECIMAL(12,0) DEFAULT 0, -- 소급·정정 지급액 (총 지급액에 포함, 과세 급여는 원 급여 기간 귀속)
+    adjustment_deductions DECIMAL(12,0) DEFAULT 0, -- 소급·정정 공제액 (세액·보험료 증감)
     total_deductions DECIMAL(10,2) NOT NULL, -- 총 공제액
     net_pay DECIMAL(10,2) NOT NULL, -- 실지급액
     pay_date DATE,
     is_paid BOOLEAN DEFAULT FALSE,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    FOREIGN KEY (payroll_run_id) REFERENCES payroll_runs(id)
+);
+
+-- 급여 수당 항목별 지급 내역
+CREATE TABLE IF NOT EXISTS payroll_allowance_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payroll_id INTEGER NOT NULL,
+    allowance_type_id INTEGER NOT NULL,
+    name VARCHAR(50) NOT NULL, -- 지급 당시 수당명
+    amount DECIMAL(10,2) NOT NULL,
+    taxable_amount DECIMAL(10,2) DEFAULT 0,
+    non_taxable_amount DECIMAL(10,2) DEFAULT 0,
+    FOREIGN KEY (payroll_id) REFERENCES payroll_records(id),
+    FOREIGN KEY (allowance_type_id) REFERENCES allowance_types(id)
+);
+
+-- 근로소득 간이세액표 (국세청, 시행일별 버전)
+CREATE TABLE IF NOT EXISTS withholding_tax_tables (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    effective_date DATE NOT NULL UNIQUE, -- 시행일
+    created_by INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+-- 간이세액표 구간별 세액 (월급여액 이상/미만, 공제대상가족 수)
+CREATE TABLE IF NOT EXISTS withholding_tax_brackets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_id INTEGER NOT NULL,
+    pay_from DECIMAL(12,0) NOT NULL, -- 이상 (원)
+    pay_to DECIMAL(12,0) NOT NULL, -- 미만 (원)
+    dependents INTEGER NOT NULL,
+    tax_amount DECIMAL(12,0) NOT NULL,
+    FOREIGN KEY (table_id) REFERENCES withholding_tax_tables(id)
+);
+
+-- 최저임금 (연도별 시급, 1월 1일 시행)
+CREATE TABLE IF NOT EXISTS minimum_wages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    year INTEGER UNIQUE NOT NULL,
+    hourly_wage DECIMAL(12,0) NOT NULL,
+    description TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 4대보험 요율 (시행일별)
+CREATE TABLE IF NOT EXISTS insurance_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    insurance_type VARCHAR(30) NOT NULL, -- national_pension, health_insurance, long_term_care, employment_insurance
+    effective_date DATE NOT NULL,
+    employee_rate DECIMAL(8,6) NOT NULL, -- 근로자 부담률 (장기요양보험은 건강보험료 대비 비율)
+    employer_rate DECIMAL(8,6) NOT NULL, -- 사업주 부담률
+    description TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(insurance_type, effective_date)
+);
+
+-- 4대보험 기준소득월액(보수월액) 상·하한 (시행일별)
+CREATE TABLE IF NOT EXISTS insurance_contribution_limits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    insurance_type VARCHAR(30) NOT NULL, -- national_pension, health_insurance
+    effective_date DATE NOT NULL,
+    lower_limit DECIMAL(12,0) NOT NULL, -- 하한액
+    upper_limit DECIMAL(12,0) NOT NULL, -- 상한액
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(insurance_type, effective_date)
+);
+
+-- 직원별 4대보험 신고 기준소득월액(보수월액)
+CREATE TABLE IF NOT EXISTS employee_insurance_bases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    insurance_type VARCHAR(30) NOT NULL, -- national_pension, health_insurance
+    effective_date DATE NOT NULL,
+    reported_amount DECIMAL(12,0) NOT NULL, -- 신고 금액
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    UNIQUE(employee_id, insurance_type, effective_date)
+);
+
+-- 연말정산 (근로자 신고 공제 항목과 확정 세액)
+CREATE TABLE IF NOT EXISTS year_end_settlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    tax_year INTEGER NOT NULL, -- 귀속연도
+    insurance_premiums DECIMAL(12,0) DEFAULT 0, -- 보장성 보험료
+    medical_expenses DECIMAL(12,0) DEFAULT 0, -- 의료비 (일반 부양가족)
+    medical_expenses_special DECIMAL(12,0) DEFAULT 0, -- 의료비 (본인·65세 이상·장애인, 한도 없음)
+    education_expenses DECIMAL(12,0) DEFAULT 0, -- 교육비
+    donations DECIMAL(12,0) DEFAULT 0, -- 기부금
+    credit_card_spending DECIMAL(12,0) DEFAULT 0, -- 신용카드 사용액
+    debit_card_spending DECIMAL(12,0) DEFAULT 0, -- 직불·체크카드, 현금영수증 사용액
+    dependents INTEGER DEFAULT 1, -- 기본공제 대상 인원 (본인 포함)
+    total_pay DECIMAL(14,0) DEFAULT 0, -- 총급여 (과세)
+    non_taxable_pay DECIMAL(14,0) DEFAULT 0, -- 비과세 소득
+    earned_income_deduction DECIMAL(14,0) DEFAULT 0, -- 근로소득공제
+    earned_income_amount DECIMAL(14,0) DEFAULT 0, -- 근로소득금액
+    personal_deduction DECIMAL(14,0) DEFAULT 0, -- 인적공제
+    pension_deduction DECIMAL(14,0) DEFAULT 0, -- 연금보험료공제
+    special_income_deduction DECIMAL(14,0) DEFAULT 0, -- 특별소득공제 (건강·고용보험료)
+    card_deduction DECIMAL(14,0) DEFAULT 0, -- 신용카드 등 소득공제
+    tax_base DECIMAL(14,0) DEFAULT 0, -- 과세표준
+    calculated_tax DECIMAL(14,0) DEFAULT 0, -- 산출세액
+    earned_income_tax_credit DECIMAL(14,0) DEFAULT 0, -- 근로소득세액공제
+    special_tax_credit DECIMAL(14,0) DEFAULT 0, -- 특별세액공제 (보험료·의료비·교육비·기부금)
+    standard_tax_credit DECIMAL(14,0) DEFAULT 0, -- 표준세액공제
+    determined_tax DECIMAL(14,0) DEFAULT 0, -- 결정세액
+    determined_local_tax DECIMAL(14,0) DEFAULT 0,
+    withheld_tax DECIMAL(14,0) DEFAULT 0, -- 기납부세액
+    withheld_local_tax DECIMAL(14,0) DEFAULT 0,
+    balance_tax DECIMAL(14,0) DEFAULT 0, -- 차감징수세액 (환급은 음수)
+    balance_local_tax DECIMAL(14,0) DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'draft', -- draft, confirmed, posted
+    payroll_id INTEGER, -- 정산액이 반영된 2월 급여
+    confirmed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    FOREIGN KEY (payroll_id) REFERENCES payroll_records(id),
+    UNIQUE(employee_id, tax_year)
+);
+
+-- 급여 소급·정정 (지급된 급여를 고치지 않고 항목별 증감액을 다음 급여 정산에 반영)
+CREATE TABLE IF NOT EXISTS payroll_adjustments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    original_payroll_id INTEGER NOT NULL, -- 정정 대상 급여
+    employee_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    wage_rate DECIMAL(12,0) NOT NULL, -- 정정 후 월급, 시급 또는 일급
+    worked_hours DECIMAL(6,2) DEFAULT 0, -- 정정 후 근로시간·일수
+    worked_days DECIMAL(5,2) DEFAULT 0,
+    overtime_hours DECIMAL(6,2) DEFAULT 0,
+    holiday_hours DECIMAL(6,2) DEFAULT 0,
+    holiday_overtime_hours DECIMAL(6,2) DEFAULT 0,
+    night_hours DECIMAL(6,2) DEFAULT 0,
+    unpaid_leave_days DECIMAL(5,2) DEFAULT 0,
+    base_salary DECIMAL(12,0) DEFAULT 0, -- 항목별 증감액 (감액은 음수)
+    overtime_pay DECIMAL(12,0) DEFAULT 0,
+    holiday_pay DECIMAL(12,0) DEFAULT 0,
+    night_pay DECIMAL(12,0) DEFAULT 0,
+    weekly_holiday_pay DECIMAL(12,0) DEFAULT 0,
+    allowances DECIMAL(12,0) DEFAULT 0,
+    bonus DECIMAL(12,0) DEFAULT 0,
+    unpaid_leave_deduction DECIMAL(12,0) DEFAULT 0,
+    gross_pay DECIMAL(12,0) DEFAULT 0,
+    taxable_pay DECIMAL(12,0) DEFAULT 0,
+    non_taxable_pay DECIMAL(12,0) DEFAULT 0,
+    income_tax DECIMAL(12,0) DEFAULT 0,
+    local_tax DECIMAL(12,0) DEFAULT 0,
+    national_pension DECIMAL(12,0) DEFAULT 0,
+    health_insurance DECIMAL(12,0) DEFAULT 0,
+    long_term_care DECIMAL(12,0) DEFAULT 0,
+    employment_insurance DECIMAL(12,0) DEFAULT 0,
+    other_deductions DECIMAL(12,0) DEFAULT 0,
+    total_deductions DECIMAL(12,0) DEFAULT 0,
+    net_pay DECIMAL(12,0) DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'pending', -- pending, applied
+    applied_payroll_id INTEGER, -- 증감액이 반영된 급여
+    created_by INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    applied_at DATETIME,
+    FOREIGN KEY (original_payroll_id) REFERENCES payroll_records(id),
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    FOREIGN KEY (applied_payroll_id) REFERENCES payroll_records(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+-- 퇴직금 정산 (퇴직일 기준 평균임금·근속기간과 퇴직소득세)
+CREATE TABLE IF NOT EXISTS severance_settlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    retirement_date DATE NOT NULL, -- 퇴직일 (마지막 근무일 다음 날)
+    pension_type VARCHAR(20) NOT NULL, -- severance, db, dc
+    service_start DATE NOT NULL,
+    service_end DATE NOT NULL, -- 마지막 근무일
+    excluded_days INTEGER DEFAULT 0, -- 근속기간에서 제외한 휴직 일수
+    service_days INTEGER NOT NULL, -- 제외 일수를 뺀 근속일수
+    average_daily_wage DECIMAL(12,2) DEFAULT 0, -- 1일 평균임금 (통상일급이 높으면 통상일급)
+    severance_pay DECIMAL(14,0) DEFAULT 0, -- 법정 퇴직금 (1일 평균임금 x 30일 x 근속일수/365)
+    dc_contributions DECIMAL(14,0) DEFAULT 0, -- 확정기여형 납입 부담금
+    dc_shortfall DECIMAL(14,0) DEFAULT 0, -- 확정기여형 미납 부담금
+    retirement_income DECIMAL(14,0) DEFAULT 0, -- 퇴직소득 (퇴직급여)
+    service_years INTEGER DEFAULT 0, -- 퇴직소득세 근속연수 (1년 미만은 1년)
+    service_years_deduction DECIMAL(14,0) DEFAULT 0, -- 근속연수공제
+    converted_pay DECIMAL(14,0) DEFAULT 0, -- 환산급여
+    converted_pay_deduction DECIMAL(14,0) DEFAULT 0, -- 환산급여공제
+    tax_base DECIMAL(14,0) DEFAULT 0, -- 퇴직소득 과세표준
+    converted_tax DECIMAL(14,0) DEFAULT 0, -- 환산산출세액
+    income_tax DECIMAL(14,0) DEFAULT 0, -- 퇴직소득세
+    local_tax DECIMAL(14,0) DEFAULT 0, -- 지방소득세
+    company_payment DECIMAL(14,0) DEFAULT 0, -- 회사 지급액 (퇴직금: 세후 퇴직금, 확정기여형: 미납 부담금)
+    status VARCHAR(20) DEFAULT 'draft', -- draft, confirmed, paid
+    pay_date DATE,
+    created_by INTEGER,
+    confirmed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    UNIQUE(employee_id, retirement_date)
+);
+
+-- 확정기여형 퇴직연금 연간 부담금 (연간 임금총액의 1/12 이상)
+CREATE TABLE IF NOT EXISTS retirement_pension_contributions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    plan_year INTEGER NOT NULL,
+    annual_wages DECIMAL(14,0) DEFAULT 0, -- 연간 임금총액
+    required_amount DECIMAL(14,0) DEFAULT 0, -- 부담금 (임금총액의 1/12)
+    paid_amount DECIMAL(14,0) DEFAULT 0, -- 납입액
+    paid_date DATE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id),
+    UNIQUE(employee_id, plan_year)
+);
+
+-- 급여 이체 계좌 (계좌번호는 암호화하여 저장)
+CREATE TABLE IF NOT EXISTS employee_bank_accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL UNIQUE,
+    bank_code VARCHAR(3) NOT NULL, -- 금융기관 코드 (004 국민, 088 신한 등)
+    account_number_encrypted TEXT NOT NULL,
+    account_holder VARCHAR(100) NOT NULL, -- 예금주
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (employee_id) REFERENCES employees(id)
 );
 
+-- 급여 대량이체 파일
+CREATE TABLE IF NOT EXISTS payroll_transfer_batches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payroll_run_id INTEGER NOT NULL,
+    bank_code VARCHAR(3) NOT NULL, -- 출금 은행
+    transfer_date DATE NOT NULL,
+    record_count INTEGER NOT NULL,
+    total_amount DECIMAL(14,0) NOT NULL,
+    file_format VARCHAR(10) DEFAULT 'csv', -- csv, fixed
+    file_content BLOB NOT NULL,
+    status VARCHAR(20) DEFAULT 'exported', -- exported, transferred
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    transferred_at DATETIME,
+    FOREIGN KEY (payroll_run_id) REFERENCES payroll_runs(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS payroll_transfer_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    batch_id INTEGER NOT NULL,
+    payroll_id INTEGER NOT NULL,
+    amount DECIMAL(12,0) NOT NULL,
+    FOREIGN KEY (batch_id) REFERENCES payroll_transfer_batches(id),
+    FOREIGN KEY (payroll_id) REFERENCES payroll_records(id)
+);
+
 -- 근태 기록
 CREATE TABLE IF NOT EXISTS attendance_logs (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -97,6 +495,7 @@ CREATE TABLE IF NOT EXISTS attendance_logs (
     break_end TIME,
     total_hours DECIMAL(4,2),
     overtime_hours DECIMAL(4,2) DEFAULT 0,
+    overtime_status VARCHAR(20) DEFAULT 'none', -- none, pending, approved, rejected
     status VARCHAR(20) DEFAULT 'present', -- present, absent, late, early_leave, holiday
     notes TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
@@ -150,6 +549,14 @@ CREATE TABLE IF NOT EXISTS document_templates (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- 근로계약 유형별 계약서 템플릿
+CREATE TABLE IF NOT EXISTS contract_type_templates (
+    contract_type VARCHAR(30) PRIMARY KEY, -- permanent, temporary, part_time, minor, construction_daily, ...
+    template_id INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (template_id) REFERENCES document_templates(id)
+);
+
 -- 생성된 문서 기록
 CREATE TABLE IF NOT EXISTS generated_documents (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -182,6 +589,12 @@ CREATE INDEX IF NOT EXISTS idx_payroll_employee_period ON payroll_records(employ
 CREATE INDEX IF NOT EXISTS idx_attendance_employee_date ON attendance_logs(employee_id, work_date);
 CREATE INDEX IF NOT EXISTS idx_leave_requests_employee ON leave_requests(employee_id);
 CREATE INDEX IF NOT EXISTS idx_leave_requests_status ON leave_requests(status);
+CREATE INDEX IF NOT EXISTS idx_contract_versions_contract ON contract_versions(contract_id);
+CREATE INDEX IF NOT EXISTS idx_contract_signature_requests_contract ON contract_signature_requests(contract_id);
+CREATE INDEX IF NOT EXISTS idx_contract_allowances_contract ON contract_allowances(contract_id);
+CREATE INDEX IF NOT EXISTS idx_payroll_allowance_items_payroll ON payroll_allowance_items(payroll_id);
+CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_employee ON payroll_adjustments(employee_id, status);
+CREATE INDEX IF NOT EXISTS idx_withholding_tax_brackets_table ON withholding_tax_brackets(table_id, pay_from);
 
 -- 기본 데이터 삽입
 INSERT OR IGNORE INTO system_settings (setting_key, setting_value, description) VALUES
@@ -189,10 +602,254 @@ INSERT OR IGNORE INTO system_settings (setting_key, setting_value, description)
 ('company_address', '서울특별시 강남구 테헤란로 123', '회사 주소'),
 ('company_phone', '02-1234-5678', '회사 전화번호'),
 ('company_registration_number', '123-45-67890', '사업자등록번호'),
-('min_wage', '9860', '최저임금 (시급)'),
+('company_representative', '', '대표자 성명'),
+('tax_office_code', '', '관할 세무서 코드'),
+('hometax_id', '', '홈택스 사용자 ID (지급명세서 제출자)'),
+('payroll_bank_code', '004', '급여 출금 은행 코드'),
 ('work_hours_per_day', '8', '1일 근무시간'),
 ('work_days_per_week', '5', '주 근무일수'),
-('annual_leave_base', '15', '기본 연차 일수');
+('annual_leave_base', '15', '기본 연차 일수'),
+('severance_excluded_leave_types', 'personal', '퇴직금 근속기간에서 제외하는 휴가 유형 (쉼표 구분)');
+
+-- 문서 생성 기록(generated_documents)이 참조하는 기본 템플릿
+INSERT OR IGNORE INTO document_templates (id, name, type, content) VALUES
+(1, '급여명세서', 'payslip', ''),
+(2, '재직증명서', 'employment_certificate', ''),
+(3, '근로계약서', 'employment_contract', '');
+
+-- 근로계약 유형별 표준근로계약서 (고용노동부 표준근로계약서 양식)
+INSERT OR IGNORE INTO document_templates (id, name, type, content, variables) VALUES
+(4, '표준근로계약서 (기간의 정함이 없는 경우)', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로개시일
+{{contract_start_date}}부터
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+## 5. 근무일/휴일
+매주 {{work_days}} 근무, 주휴일 매주 {{weekly_holiday}}
+## 6. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 9. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 10. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(5, '표준근로계약서 (기간의 정함이 있는 경우)', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+## 5. 근무일/휴일
+매주 {{work_days}} 근무, 주휴일 매주 {{weekly_holiday}}
+## 6. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 9. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 10. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "contract_end_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(6, '단시간근로자 표준근로계약서', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 근로일 및 근로일별 근로시간
+{{daily_schedule}}
+주 소정근로시간: {{weekly_hours}}시간
+주휴일: 매주 {{weekly_holiday}}
+## 5. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+- 초과근로에 대한 가산임금률: 단시간근로자와 사용자 사이에 근로하기로 정한 시간을 초과하여 근로하면 법정 근로시간 내라도 통상임금의 100분의 50 이상의 가산임금 지급 (기간제 및 단시간근로자 보호 등에 관한 법률 제6조)
+## 6. 연차유급휴가
+통상근로자의 근로시간에 비례하여 연차유급휴가 부여
+## 7. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 8. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 9. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "contract_end_date", "workplace", "job_description", "daily_schedule", "weekly_hours", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(7, '연소근로자(18세 미만인 자) 표준근로계약서', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+근로자 생년월일: {{employee_birth_date}}
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소
+{{workplace}}
+## 3. 업무의 내용
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+연소근로자의 근로시간은 1일 7시간, 1주 35시간을 초과할 수 없음 (근로기준법 제69조)
+## 5. 근무일/휴일
+매주 {{work_days}} 근무, 주휴일 매주 {{weekly_holiday}}
+## 6. 임금
+- {{salary_type}}: {{base_salary}}
+- 수당: {{allowances}}
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 가족관계증명서 및 동의서
+- 가족관계기록사항에 관한 증명서 제출 여부: 제출
+- 친권자 또는 후견인의 동의서 구비 여부: 구비
+13세 이상 15세 미만인 자에 대해서는 고용노동부장관으로부터 취직인허증을 교부받아야 함
+## 9. 사회보험 적용여부
+고용보험, 산재보험, 국민연금, 건강보험
+## 10. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 11. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 12. 기타 유의사항
+18세 미만인 자의 야간근로와 휴일근로는 본인의 동의와 고용노동부장관의 인가를 받아야 함 (근로기준법 제70조)
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "employee_birth_date", "contract_start_date", "contract_end_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "salary_type", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]'),
+(8, '건설일용근로자 표준근로계약서', 'employment_contract', '{{company_name}}(이하 "사업주"라 함)과(와) {{employee_name}}(이하 "근로자"라 함)은 다음과 같이 근로계약을 체결한다.
+## 1. 근로계약기간
+{{contract_start_date}}부터 {{contract_end_date}}까지
+## 2. 근무장소 (현장)
+{{workplace}}
+## 3. 업무의 내용 (직종)
+{{job_description}}
+## 4. 소정근로시간
+{{working_hours}} (휴게시간: {{break_time}})
+## 5. 근무일/휴일
+{{work_days}} 근무, 주휴일 매주 {{weekly_holiday}} (해당자에 한함)
+## 6. 임금
+- 일급: {{base_salary}}
+- 수당: {{allowances}}
+- 연장, 야간, 휴일근로에 대해서는 통상임금의 100분의 50을 가산하여 지급
+- 지급방법: 근로자에게 직접 지급 또는 근로자 명의 예금통장에 입금
+## 7. 연차유급휴가
+연차유급휴가는 근로기준법에서 정하는 바에 따라 부여함
+## 8. 사회보험 적용여부
+고용보험, 산재보험 (국민연금, 건강보험은 관계 법령에 따른 적용 대상인 경우)
+## 9. 근로계약서 교부
+사업주는 근로계약을 체결함과 동시에 본 계약서를 사본하여 근로자의 교부요구와 관계없이 근로자에게 교부함 (근로기준법 제17조 이행)
+## 10. 기타
+{{contract_terms}}
+이 계약에 정함이 없는 사항은 근로기준법령에 의함
+## 
+{{contract_date}}
+(사업주) 사업체명: {{company_name}} (전화: {{company_phone}})
+사업자등록번호: {{company_registration_number}}
+주소: {{company_address}}
+대표자: (서명)
+(근로자) 주소: {{employee_address}}
+연락처: {{employee_phone}}
+성명: {{employee_name}} (서명)', '["company_name", "employee_name", "contract_start_date", "contract_end_date", "workplace", "job_description", "working_hours", "break_time", "work_days", "weekly_holiday", "base_salary", "allowances", "contract_terms", "contract_date", "company_phone", "company_registration_number", "company_address", "employee_address", "employee_phone"]');
+
+INSERT OR IGNORE INTO contract_type_templates (contract_type, template_id) VALUES
+('permanent', 4),
+('temporary', 5),
+('contract', 5),
+('intern', 5),
+('part_time', 6),
+('minor', 7),
+('construction_daily', 8);
+
+-- 기본 근무 스케줄 (주 5일, 09:00-18:00, 일요일 주휴)
+INSERT OR IGNORE INTO work_schedules (id, name, description, weekly_holiday) VALUES
+(1, '주 5일 통상근무', '월-금 09:00-18:00, 휴게 12:00-13:00', 0);
+
+INSERT OR IGNORE INTO work_schedule_days (schedule_id, weekday, start_time, end_time, breaks) VALUES
+(1, 1, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 2, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 3, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 4, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]'),
+(1, 5, '09:00', '18:00', '[{"start":"12:00","end":"13:00"}]');
+
+-- 기본 수당 항목 (비과세 한도: 소득세법 시행령 제12조, 2024년 기준)
+INSERT OR IGNORE INTO allowance_types (code, name, payment_type, taxable, non_taxable_limit, ordinary_wage, minimum_wage, description) VALUES
+('meal', '식대', 'fixed', 0, 200000, 1, 1, '월 20만원 이하 비과세'),
+('car', '자가운전보조금', 'fixed', 0, 200000, 0, 0, '본인 차량으로 업무 수행 시 월 20만원 이하 비과세'),
+('childcare', '보육수당', 'fixed', 0, 200000, 0, 1, '6세 이하 자녀 보육 관련 월 20만원 이하 비과세'),
+('position', '직책수당', 'fixed', 1, 0, 1, 1, NULL),
+('family', '가족수당', 'fixed', 1, 0, 0, 1, NULL),
+('incentive', '성과수당', 'variable', 1, 0, 0, 0, NULL);
+
+-- 최저임금 (최저임금위원회 고시)
+INSERT OR IGNORE INTO minimum_wages (year, hourly_wage, description) VALUES
+(2020, 8590, '월 1,795,310원 (209시간 기준)'),
+(2021, 8720, '월 1,822,480원 (209시간 기준)'),
+(2022, 9160, '월 1,914,440원 (209시간 기준)'),
+(2023, 9620, '월 2,010,580원 (209시간 기준)'),
+(2024, 9860, '월 2,060,740원 (209시간 기준)'),
+(2025, 10030, '월 2,096,270원 (209시간 기준)'),
+(2026, 10320, '월 2,156,880원 (209시간 기준)');
+
+-- 4대보험 요율 (근로자/사업주 부담률)
+INSERT OR IGNORE INTO insurance_rates (insurance_type, effective_date, employee_rate, employer_rate, description) VALUES
+('national_pension', '2024-01-01', 0.045, 0.045, '국민연금 9%'),
+('health_insurance', '2024-01-01', 0.03545, 0.03545, '건강보험 7.09%'),
+('long_term_care', '2024-01-01', 0.1295, 0.1295, '장기요양보험 건강보험료의 12.95%'),
+('employment_insurance', '2024-01-01', 0.009, 0.0115, '고용보험 실업급여 1.8%, 고용안정·직업능력개발 0.25% (150인 미만)'),
+('national_pension', '2026-01-01', 0.0475, 0.0475, '국민연금 9.5%'),
+('health_insurance', '2026-01-01', 0.03595, 0.03595, '건강보험 7.19%'),
+('long_term_care', '2026-01-01', 0.1314, 0.1314, '장기요양보험 건강보험료의 13.14%');
+
+-- 기준소득월액 상·하한 (국민연금: 매년 7월 조정, 건강보험: 보수월액 기준)
+INSERT OR IGNORE INTO insurance_contribution_limits (insurance_type, effective_date, lower_limit, upper_limit) VALUES
+('national_pension', '2023-07-01', 370000, 5900000),
+('national_pension', '2024-07-01', 390000, 6170000),
+('national_pension', '2025-07-01', 400000, 6370000),
+('health_insurance', '2024-01-01', 279266, 119625106);
 
 -- 관리자 계정 생성 (비밀번호: admin123!)
 INSERT OR IGNORE INTO users (username, password_hash, email, role) VALUES
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AllowanceTypeRequest struct {
+	Code            string  `json:"code" binding:"required"`
+	Name            string  `json:"name" binding:"required"`
+	PaymentType     string  `json:"payment_type"`
+	Taxable         bool    `json:"taxable"`
+	NonTaxableLimit float64 `json:"non_taxable_limit"`
+	OrdinaryWage    bool    `json:"ordinary_wage"`
+	MinimumWage     *bool   `json:"minimum_wage"` // 최저임금 산입 여부 (생략 시 산입)
+	Description     string  `json:"description"`
+}
+
+// PayrollAllowanceInput is an allowance amount entered for one payroll
+type PayrollAllowanceInput struct {
+	AllowanceTypeID int     `json:"allowance_type_id" binding:"required"`
+	Amount          float64 `json:"amount"`
+}
+
+// allowanceInputError is an allowance item the client got wrong, as opposed to a database error
+type allowanceInputError struct {
+	message string
+}
+
+func (e *allowanceInputError) Error() string {
+	return e.message
+}
+
+// payrollAllowanceLine is an allowance amount together with its tax treatment
+type payrollAllowanceLine struct {
+	Type   models.AllowanceType
+	Amount models.Money
+}
+
+const allowanceTypeColumns = `
+	id, code, name, payment_type, taxable, non_taxable_limit, ordinary_wage, minimum_wage,
+	description, is_active, created_at, updated_at
+`
+
+func scanAllowanceType(row rowScanner) (*models.AllowanceType, error) {
+	var allowanceType models.AllowanceType
+	var description sql.NullString
+	err := row.Scan(
+		&allowanceType.ID, &allowanceType.Code, &allowanceType.Name, &allowanceType.PaymentType,
+		&allowanceType.Taxable, &allowanceType.NonTaxableLimit, &allowanceType.OrdinaryWage, &allowanceType.MinimumWage,
+		&description, &allowanceType.IsActive, &allowanceType.CreatedAt, &allowanceType.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	allowanceType.Description = description.String
+	return &allowanceType, nil
+}
+
+func loadAllowanceType(db dbtx, id int) (*models.AllowanceType, error) {
+	return scanAllowanceType(db.QueryRow("SELECT "+allowanceTypeColumns+" FROM allowance_types WHERE id = ?", id))
+}
+
+// resolveAllowanceType loads an active allowance type, reporting unknown ids as input errors
+func resolveAllowanceType(db dbtx, id int) (*models.AllowanceType, error) {
+	allowanceType, err := loadAllowanceType(db, id)
+	if err == sql.ErrNoRows || (err == nil && !allowanceType.IsActive) {
+		return nil, &allowanceInputError{fmt.Sprintf("allowance type %d not found", id)}
+	}
+	return allowanceType, err
+}
+
+// resolveContractAllowances checks the allowance items of a contract and fills in their code and name
+func resolveContractAllowances(db dbtx, items []models.ContractAllowance) ([]models.ContractAllowance, error) {
+	seen := make(map[int]bool)
+	resolved := make([]models.ContractAllowance, 0, len(items))
+	for _, item := range items {
+		if item.Amount < 0 {
+			return nil, &allowanceInputError{"allowance amounts cannot be negative"}
+		}
+		if seen[item.AllowanceTypeID] {
+			return nil, &allowanceInputError{fmt.Sprintf("allowance type %d is listed more than once", item.AllowanceTypeID)}
+		}
+		seen[item.AllowanceTypeID] = true
+
+		allowanceType, err := resolveAllowanceType(db, item.AllowanceTypeID)
+		if err != nil {
+			return nil, err
+		}
+		item.Code = allowanceType.Code
+		item.Name = allowanceType.Name
+		resolved = append(resolved, item)
+	}
+	return resolved, nil
+}
+
+// saveContractAllowances replaces the allowance items of a contract
+func saveContractAllowances(tx dbtx, contractID int64, items []models.ContractAllowance) error {
+	if _, err := tx.Exec("DELETE FROM contract_allowances WHERE contract_id = ?", contractID); err != nil {
+		return err
+	}
+	for _, item := range items {
+		_, err := tx.Exec(`
+			INSERT INTO contract_allowances (contract_id, allowance_type_id, amount)
+			VALUES (?, ?, ?)
+		`, contractID, item.AllowanceTypeID, item.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadContractAllowances(db dbtx, contractID int) ([]models.ContractAllowance, error) {
+	rows, err := db.Query(`
+		SELECT ca.allowance_type_id, t.code, t.name, ca.amount
+		FROM contract_allowances ca
+		JOIN allowance_types t ON ca.allowance_type_id = t.id
+		WHERE ca.contract_id = ?
+		ORDER BY t.id
+	`, contractID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ContractAllowance{}
+	for rows.Next() {
+		var item models.ContractAllowance
+		if err := rows.Scan(&item.AllowanceTypeID, &item.Code, &item.Name, &item.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// payrollAllowanceLines resolves the allowances entered for a payroll. Without entered items,
+// the fixed allowances of the employee's active contract are paid.
+func payrollAllowanceLines(db dbtx, employeeID int, inputs []PayrollAllowanceInput) ([]payrollAllowanceLine, error) {
+	var lines []payrollAllowanceLine
+
+	if inputs != nil {
+		for _, input := range inputs {
+			if input.Amount < 0 {
+				return nil, &allowanceInputError{"allowance amounts cannot be negative"}
+			}
+			allowanceType, err := resolveAllowanceType(db, input.AllowanceTypeID)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, payrollAllowanceLine{Type: *allowanceType, Amount: models.Won(input.Amount)})
+		}
+		return lines, nil
+	}
+
+	var contractID int64
+	err := db.QueryRow(`
+		SELECT id FROM employment_contracts
+		WHERE employee_id = ? AND is_active = 1
+		ORDER BY start_date DESC, id DESC LIMIT 1
+	`, employeeID).Scan(&contractID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return contractAllowanceLines(db, contractID)
+}
+
+// contractAllowanceLines returns the fixed allowances stored on the contract
+func contractAllowanceLines(db dbtx, contractID int64) ([]payrollAllowanceLine, error) {
+	var lines []payrollAllowanceLine
+	rows, err := db.Query(`
+		SELECT ca.allowance_type_id, ca.amount
+		FROM contract_allowances ca
+		JOIN allowance_types t ON ca.allowance_type_id = t.id
+		WHERE ca.contract_id = ? AND t.payment_type = 'fixed'
+		ORDER BY t.id
+	`, contractID)
+	if err != nil {
+		return nil, err
+	}
+	type contractItem struct {
+		typeID int
+		amount float64
+	}
+	var contractItems []contractItem
+	for rows.Next() {
+		var item contractItem
+		if err := rows.Scan(&item.typeID, &item.amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		contractItems = append(contractItems, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, item := range contractItems {
+		allowanceType, err := loadAllowanceType(db, item.typeID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, payrollAllowanceLine{Type: *allowanceType, Amount: models.Won(item.amount)})
+	}
+	return lines, nil
+}
+
+func savePayrollAllowanceItems(tx dbtx, payrollID int64, items []models.PayrollAllowanceItem) error {
+	if _, err := tx.Exec("DELETE FROM payroll_allowance_items WHERE payroll_id = ?", payrollID); err != nil {
+		return err
+	}
+	for _, item := range items {
+		_, err := tx.Exec(`
+			INSERT INTO payroll_allowance_items (payroll_id, allowance_type_id, name, amount,
+			                                     taxable_amount, non_taxable_amount)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, payrollID, item.AllowanceTypeID, item.Name, item.Amount, item.TaxableAmount, item.NonTaxableAmount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadPayrollAllowanceItems(db dbtx, payrollID int) ([]models.PayrollAllowanceItem, error) {
+	rows, err := db.Query(`
+		SELECT allowance_type_id, name, amount, taxable_amount, non_taxable_amount
+		FROM payroll_allowance_items
+		WHERE payroll_id = ?
+		ORDER BY id
+	`, payrollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PayrollAllowanceItem{}
+	for rows.Next() {
+		var item models.PayrollAllowanceItem
+		if err := rows.Scan(&item.AllowanceTypeID, &item.Name, &item.Amount, &item.TaxableAmount, &item.NonTaxableAmount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func respondAllowanceError(c *gin.Context, err error) {
+	if inputErr, ok := err.(*allowanceInputError); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": inputErr.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load allowance types"})
+}
+
+func validateAllowanceTypeRequest(req *AllowanceTypeRequest) error {
+	if req.PaymentType == "" {
+		req.PaymentType = "fixed"
+	}
+	if req.PaymentType != "fixed" && req.PaymentType != "variable" {
+		return fmt.Errorf("payment_type must be fixed or variable")
+	}
+	if req.NonTaxableLimit < 0 {
+		return fmt.Errorf("non_taxable_limit cannot be negative")
+	}
+	if req.Taxable {
+		req.NonTaxableLimit = 0
+	}
+	if req.MinimumWage == nil {
+		counted := true
+		req.MinimumWage = &counted
+	}
+	return nil
+}
+
+func GetAllowanceTypes(c *gin.Context) {
+	rows, err := database.DB.Query("SELECT " + allowanceTypeColumns + " FROM allowance_types WHERE is_active = 1 ORDER BY id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	allowanceTypes := []models.AllowanceType{}
+	for rows.Next() {
+		allowanceType, err := scanAllowanceType(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan allowance type"})
+			return
+		}
+		allowanceTypes = append(allowanceTypes, *allowanceType)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"allowance_types": allowanceTypes})
+}
+
+func CreateAllowanceType(c *gin.Context) {
+	var req AllowanceTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateAllowanceTypeRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		INSERT INTO allowance_types (code, name, payment_type, taxable, non_taxable_limit, ordinary_wage, minimum_wage, description)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, req.Code, req.Name, req.PaymentType, req.Taxable, req.NonTaxableLimit, req.OrdinaryWage, *req.MinimumWage, req.Description)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Allowance code already exists or database error"})
+		return
+	}
+
+	id, _ := result.LastInsertId()
+	allowanceType, err := loadAllowanceType(database.DB, int(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created allowance type"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"allowance_type": allowanceType})
+}
+
+// UpdateAllowanceType changes an allowance type. Payrolls already calculated keep their amounts.
+func UpdateAllowanceType(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid allowance type ID"})
+		return
+	}
+
+	var req AllowanceTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateAllowanceTypeRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE allowance_types SET code = ?, name = ?, payment_type = ?, taxable = ?,
+		                           non_taxable_limit = ?, ordinary_wage = ?, minimum_wage = ?, description = ?,
+		                           updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND is_active = 1
+	`, req.Code, req.Name, req.PaymentType, req.Taxable, req.NonTaxableLimit, req.OrdinaryWage, *req.MinimumWage, req.Description, id)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Allowance code already exists or database error"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Allowance type not found"})
+		return
+	}
+
+	allowanceType, err := loadAllowanceType(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve allowance type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"allowance_type": allowanceType})
+}
+
+// DeleteAllowanceType retires an allowance type; contracts and payrolls that used it keep their items
+func DeleteAllowanceType(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid allowance type ID"})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE allowance_types SET is_active = 0, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete allowance type"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Allowance type not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Allowance type deleted successfully"})
+}
@@ -29,7 +29,7 @@ func GetAttendanceLogs(c *gin.Context) {
 	query := `
 		SELECT a.id, a.employee_id, a.work_date, a.clock_in, a.clock_out, 
 		       a.break_start, a.break_end, a.total_hours, a.overtime_hours, 
-		       a.status, a.notes, a.created_at, a.updated_at,
+		       a.overtime_status, a.status, a.notes, a.created_at, a.updated_at,
 		       e.name as employee_name, e.employee_number
 		FROM attendance_logs a
 		JOIN employees e ON a.employee_id = e.id
@@ -71,7 +71,7 @@ func GetAttendanceLogs(c *gin.Context) {
 			&attendance.ID, &attendance.EmployeeID, &attendance.WorkDate,
 			&attendance.ClockIn, &attendance.ClockOut, &attendance.BreakStart,
 			&attendance.BreakEnd, &attendance.TotalHours, &attendance.OvertimeHours,
-			&attendance.Status, &attendance.Notes, &attendance.CreatedAt,
+			&attendance.OvertimeStatus, &attendance.Status, &attendance.Notes, &attendance.CreatedAt,
 			&attendance.UpdatedAt, &employeeName, &employeeNumber,
 		)
 		if err != nil {
@@ -104,7 +104,7 @@ func GetEmployeeAttendance(c *gin.Context) {
 	query := `
 		SELECT a.id, a.employee_id, a.work_date, a.clock_in, a.clock_out, 
 		       a.break_start, a.break_end, a.total_hours, a.overtime_hours, 
-		       a.status, a.notes, a.created_at, a.updated_at
+		       a.overtime_status, a.status, a.notes, a.created_at, a.updated_at
 		FROM attendance_logs a
 		WHERE a.employee_id = ?
 	`
@@ -138,7 +138,7 @@ func GetEmployeeAttendance(c *gin.Context) {
 			&attendance.ID, &attendance.EmployeeID, &attendance.WorkDate,
 			&attendance.ClockIn, &attendance.ClockOut, &attendance.BreakStart,
 			&attendance.BreakEnd, &attendance.TotalHours, &attendance.OvertimeHours,
-			&attendance.Status, &attendance.Notes, &attendance.CreatedAt,
+			&attendance.OvertimeStatus, &attendance.Status, &attendance.Notes, &attendance.CreatedAt,
 			&attendance.UpdatedAt,
 		)
 		if err != nil {
@@ -152,6 +152,141 @@ func GetEmployeeAttendance(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"attendances": attendances})
 }
 
+// clockMinutes converts an "HH:MM:SS" attendance time to minutes since midnight
+func clockMinutes(t time.Time) float64 {
+	return float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+}
+
+// scheduledAttendance is the employee's working time for a date from the work schedule
+// of their active contract. Day is nil on an off day.
+type scheduledAttendance struct {
+	Schedule *models.WorkSchedule
+	Day      *models.WorkScheduleDay
+}
+
+func loadScheduledAttendance(employeeID int, date time.Time) (*scheduledAttendance, error) {
+	schedule, err := employeeWorkSchedule(database.DB, employeeID)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+	return &scheduledAttendance{Schedule: schedule, Day: scheduleDay(schedule, date.Weekday())}, nil
+}
+
+// lateMinutes is how long after the scheduled start and grace period the employee clocked in
+func (s *scheduledAttendance) lateMinutes(clockIn time.Time) int {
+	if s == nil || s.Day == nil {
+		return 0
+	}
+	start, _ := parseClockMinutes(s.Day.StartTime)
+	late := int(clockMinutes(clockIn)) - start - s.Schedule.LateGraceMinutes
+	if late < 0 {
+		return 0
+	}
+	return late
+}
+
+// workedHours splits the time between clock-in and clock-out into worked hours and overtime.
+// Scheduled breaks taken inside the shift are not working time. Overtime is time beyond the
+// scheduled hours of the day (capped at the statutory 8 hours), so part-time workers get
+// overtime past their contractual hours; on an off day every hour is outside the contract.
+func (s *scheduledAttendance) workedHours(clockIn, clockOut time.Time) (total, overtime float64, earlyLeave bool) {
+	in := clockMinutes(clockIn)
+	out := clockMinutes(clockOut)
+	if out < in {
+		out += 24 * 60
+	}
+	worked := out - in
+
+	if s.Day == nil {
+		if worked > 4*60 {
+			worked -= float64(requiredBreakMinutes(int(worked)))
+		}
+		return worked / 60, worked / 60, false
+	}
+
+	start, _ := parseClockMinutes(s.Day.StartTime)
+	end, _ := parseClockMinutes(s.Day.EndTime)
+	end = start + clockSpan(start, end)
+
+	for _, b := range s.Day.Breaks {
+		bs, _ := parseClockMinutes(b.Start)
+		be, _ := parseClockMinutes(b.End)
+		if bs < start {
+			// Break after midnight on an overnight shift
+			bs += 24 * 60
+		}
+		be = bs + clockSpan(bs, be)
+
+		overlap := minFloat(out, float64(be)) - maxFloat(in, float64(bs))
+		if overlap > 0 {
+			worked -= overlap
+		}
+	}
+
+	span, breaks := scheduleDayMinutes(*s.Day)
+	scheduled := float64(span - breaks)
+	if scheduled > 8*60 {
+		scheduled = 8 * 60
+	}
+	if worked > scheduled {
+		overtime = (worked - scheduled) / 60
+	}
+
+	return worked / 60, overtime, out < float64(end)
+}
+
+// nightHours is the part of the shift between 22:00 and 06:00 (야간근로), scheduled breaks excluded
+func (s *scheduledAttendance) nightHours(clockIn, clockOut time.Time) float64 {
+	in := clockMinutes(clockIn)
+	out := clockMinutes(clockOut)
+	if out < in {
+		out += 24 * 60
+	}
+	night := nightMinutes(in, out)
+
+	if s != nil && s.Day != nil {
+		start, _ := parseClockMinutes(s.Day.StartTime)
+		for _, b := range s.Day.Breaks {
+			bs, _ := parseClockMinutes(b.Start)
+			be, _ := parseClockMinutes(b.End)
+			if bs < start {
+				bs += 24 * 60
+			}
+			be = bs + clockSpan(bs, be)
+			night -= nightMinutes(maxFloat(in, float64(bs)), minFloat(out, float64(be)))
+		}
+	}
+
+	return maxFloat(night, 0) / 60
+}
+
+// nightMinutes is how much of the span from and to (minutes since midnight of the work date,
+// past 24:00 for the next day) falls between 22:00 and 06:00
+func nightMinutes(from, to float64) float64 {
+	total := 0.0
+	for day := -1; day <= 1; day++ {
+		windowStart := float64(day*24*60 + 22*60)
+		if overlap := minFloat(to, windowStart+8*60) - maxFloat(from, windowStart); overlap > 0 {
+			total += overlap
+		}
+	}
+	return total
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func ClockIn(c *gin.Context) {
 	var req ClockInRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -159,12 +294,29 @@ func ClockIn(c *gin.Context) {
 		return
 	}
 
-	today := time.Now().Format("2006-01-02")
-	now := time.Now().Format("15:04:05")
+	clockIn := time.Now()
+	today := clockIn.Format("2006-01-02")
+	now := clockIn.Format("15:04:05")
+
+	scheduled, err := loadScheduledAttendance(req.EmployeeID, clockIn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work schedule"})
+		return
+	}
+
+	status := "present"
+	late := scheduled.lateMinutes(clockIn)
+	if late > 0 {
+		status = "late"
+	}
+	response := gin.H{"message": "Clocked in successfully", "time": now, "status": status}
+	if late > 0 {
+		response["late_minutes"] = late
+	}
 
 	// Check if employee already clocked in today
 	var existingID int
-	err := database.DB.QueryRow(
+	err = database.DB.QueryRow(
 		"SELECT id FROM attendance_logs WHERE employee_id = ? AND work_date = ?",
 		req.EmployeeID, today,
 	).Scan(&existingID)
@@ -189,15 +341,15 @@ func ClockIn(c *gin.Context) {
 
 		// Update clock_in time
 		_, err = database.DB.Exec(
-			"UPDATE attendance_logs SET clock_in = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
-			now, existingID,
+			"UPDATE attendance_logs SET clock_in = ?, status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			now, status, existingID,
 		)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update clock in"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Clocked in successfully", "time": now})
+		c.JSON(http.StatusOK, response)
 		return
 	}
 
@@ -209,15 +361,15 @@ func ClockIn(c *gin.Context) {
 	// Create new attendance record
 	_, err = database.DB.Exec(`
 		INSERT INTO attendance_logs (employee_id, work_date, clock_in, status)
-		VALUES (?, ?, ?, 'present')
-	`, req.EmployeeID, today, now)
+		VALUES (?, ?, ?, ?)
+	`, req.EmployeeID, today, now, status)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clock in"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Clocked in successfully", "time": now})
+	c.JSON(http.StatusOK, response)
 }
 
 func ClockOut(c *gin.Context) {
@@ -227,18 +379,29 @@ func ClockOut(c *gin.Context) {
 		return
 	}
 
-	today := time.Now().Format("2006-01-02")
-	now := time.Now().Format("15:04:05")
+	current := time.Now()
+	today := current.Format("2006-01-02")
+	now := current.Format("15:04:05")
+	workDate := current
 
 	// Find today's attendance record
 	var attendanceID int
 	var clockInStr sql.NullString
 	var clockOutStr sql.NullString
+	var status string
 
 	err := database.DB.QueryRow(
-		"SELECT id, clock_in, clock_out FROM attendance_logs WHERE employee_id = ? AND work_date = ?",
+		"SELECT id, clock_in, clock_out, status FROM attendance_logs WHERE employee_id = ? AND work_date = ?",
 		req.EmployeeID, today,
-	).Scan(&attendanceID, &clockInStr, &clockOutStr)
+	).Scan(&attendanceID, &clockInStr, &clockOutStr, &status)
+	if err == sql.ErrNoRows {
+		// A shift that crosses midnight is clocked out on the record of the day it started
+		workDate = current.AddDate(0, 0, -1)
+		err = database.DB.QueryRow(`
+			SELECT id, clock_in, clock_out, status FROM attendance_logs
+			WHERE employee_id = ? AND work_date = ? AND clock_in IS NOT NULL AND clock_out IS NULL
+		`, req.EmployeeID, workDate.Format("2006-01-02")).Scan(&attendanceID, &clockInStr, &clockOutStr, &status)
+	}
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -272,27 +435,51 @@ func ClockOut(c *gin.Context) {
 		return
 	}
 
-	// Calculate total hours (considering break time - assume 1 hour break if more than 6 hours)
-	duration := clockOutTime.Sub(clockInTime)
-	totalHours := duration.Hours()
+	scheduled, err := loadScheduledAttendance(req.EmployeeID, workDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work schedule"})
+		return
+	}
+
+	var totalHours, overtimeHours float64
+	if scheduled != nil {
+		// Breaks and overtime follow the work schedule of the employee's contract
+		var earlyLeave bool
+		totalHours, overtimeHours, earlyLeave = scheduled.workedHours(clockInTime, clockOutTime)
+		if earlyLeave && status == "present" {
+			status = "early_leave"
+		}
+	} else {
+		// Calculate total hours (considering break time - assume 1 hour break if more than 6 hours)
+		duration := clockOutTime.Sub(clockInTime)
+		if duration < 0 {
+			duration += 24 * time.Hour
+		}
+		totalHours = duration.Hours()
+
+		// Deduct break time if worked more than 6 hours
+		if totalHours > 6 {
+			totalHours -= 1 // 1 hour break
+		}
 
-	// Deduct break time if worked more than 6 hours
-	if totalHours > 6 {
-		totalHours -= 1 // 1 hour break
+		// Calculate overtime (over 8 hours)
+		if totalHours > 8 {
+			overtimeHours = totalHours - 8
+		}
 	}
 
-	// Calculate overtime (over 8 hours)
-	overtimeHours := 0.0
-	if totalHours > 8 {
-		overtimeHours = totalHours - 8
+	// Overtime is paid only after it has been approved
+	overtimeStatus := "none"
+	if overtimeHours > 0 {
+		overtimeStatus = "pending"
 	}
 
 	// Update attendance record
 	_, err = database.DB.Exec(`
 		UPDATE attendance_logs 
-		SET clock_out = ?, total_hours = ?, overtime_hours = ?, updated_at = CURRENT_TIMESTAMP
+		SET clock_out = ?, total_hours = ?, overtime_hours = ?, overtime_status = ?, status = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
-	`, now, totalHours, overtimeHours, attendanceID)
+	`, now, totalHours, overtimeHours, overtimeStatus, status, attendanceID)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clock out"})
@@ -300,9 +487,65 @@ func ClockOut(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":        "Clocked out successfully",
-		"time":           now,
-		"total_hours":    fmt.Sprintf("%.2f", totalHours),
-		"overtime_hours": fmt.Sprintf("%.2f", overtimeHours),
+		"message":         "Clocked out successfully",
+		"time":            now,
+		"total_hours":     fmt.Sprintf("%.2f", totalHours),
+		"overtime_hours":  fmt.Sprintf("%.2f", overtimeHours),
+		"overtime_status": overtimeStatus,
+		"status":          status,
+	})
+}
+
+type OvertimeApprovalRequest struct {
+	Approved bool `json:"approved"`
+}
+
+// ReviewOvertime approves or rejects the overtime of an attendance record.
+// Only approved overtime is included in payroll runs.
+func ReviewOvertime(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendance ID"})
+		return
+	}
+
+	var req OvertimeApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var overtimeHours sql.NullFloat64
+	err = database.DB.QueryRow("SELECT overtime_hours FROM attendance_logs WHERE id = ?", id).Scan(&overtimeHours)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attendance record not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if overtimeHours.Float64 <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Attendance record has no overtime"})
+		return
+	}
+
+	overtimeStatus := "rejected"
+	if req.Approved {
+		overtimeStatus = "approved"
+	}
+
+	_, err = database.DB.Exec(`
+		UPDATE attendance_logs SET overtime_status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, overtimeStatus, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update overtime status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Overtime reviewed successfully",
+		"overtime_hours":  overtimeHours.Float64,
+		"overtime_status": overtimeStatus,
 	})
 }
\ No newline at end of file
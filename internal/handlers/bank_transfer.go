@@ -0,0 +1,738 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/korean"
+)
+
+// bankNames are the 금융기관 코드 employees' pay can be transferred to
+var bankNames = map[string]string{
+	"002": "KDB산업은행",
+	"003": "IBK기업은행",
+	"004": "KB국민은행",
+	"007": "수협은행",
+	"011": "NH농협은행",
+	"020": "우리은행",
+	"023": "SC제일은행",
+	"027": "한국씨티은행",
+	"031": "대구은행",
+	"032": "부산은행",
+	"034": "광주은행",
+	"035": "제주은행",
+	"037": "전북은행",
+	"039": "경남은행",
+	"045": "새마을금고",
+	"048": "신협",
+	"071": "우체국",
+	"081": "하나은행",
+	"088": "신한은행",
+	"089": "케이뱅크",
+	"090": "카카오뱅크",
+	"092": "토스뱅크",
+}
+
+// transferColumn is a column of a bulk transfer CSV: its header and the value of a transfer
+type transferColumn struct {
+	Header string
+	Value  func(line transferLine, memo string) string
+}
+
+var (
+	columnBankCode = func(line transferLine, memo string) string { return line.BankCode }
+	columnBankName = func(line transferLine, memo string) string { return bankNames[line.BankCode] }
+	columnAccount  = func(line transferLine, memo string) string { return line.AccountNumber }
+	columnHolder   = func(line transferLine, memo string) string { return line.AccountHolder }
+	columnAmount   = func(line transferLine, memo string) string { return strconv.FormatInt(int64(line.Amount), 10) }
+	columnMemo     = func(line transferLine, memo string) string { return memo }
+)
+
+// transferLayout is the 대량이체 upload layout of a withdrawal bank: a CSV with the bank's column order,
+// or the fixed-width 전문 of header, data and trailer records
+type transferLayout struct {
+	Format  string // csv, fixed
+	Columns []transferColumn
+}
+
+// genericTransferLayout is used for banks without a layout of their own, to be mapped onto the bank's
+// upload template
+var genericTransferLayout = transferLayout{Format: "csv", Columns: []transferColumn{
+	{"입금은행코드", columnBankCode}, {"입금은행", columnBankName}, {"입금계좌번호", columnAccount},
+	{"예금주", columnHolder}, {"입금액", columnAmount}, {"받는분통장표시", columnMemo},
+}}
+
+// fixedTransferLayout writes the fixed-width 전문 (see buildFixedTransferFile)
+var fixedTransferLayout = transferLayout{Format: "fixed"}
+
+// transferLayouts are the upload layouts of the withdrawal banks, by 금융기관 코드
+var transferLayouts = map[string]transferLayout{
+	"004": {Format: "csv", Columns: []transferColumn{ // KB국민은행
+		{"입금은행", columnBankCode}, {"입금계좌번호", columnAccount}, {"입금액", columnAmount},
+		{"예금주", columnHolder}, {"받는분통장표시", columnMemo},
+	}},
+	"088": {Format: "csv", Columns: []transferColumn{ // 신한은행
+		{"입금은행코드", columnBankCode}, {"입금계좌번호", columnAccount}, {"이체금액", columnAmount},
+		{"받는분통장메모", columnMemo}, {"예금주명", columnHolder},
+	}},
+	"020": {Format: "csv", Columns: []transferColumn{ // 우리은행
+		{"입금은행코드", columnBankCode}, {"입금계좌번호", columnAccount}, {"예금주", columnHolder},
+		{"이체금액", columnAmount}, {"받는분통장표시", columnMemo},
+	}},
+	"081": {Format: "csv", Columns: []transferColumn{ // 하나은행
+		{"은행코드", columnBankCode}, {"계좌번호", columnAccount}, {"금액", columnAmount},
+		{"예금주", columnHolder}, {"입금통장표시", columnMemo},
+	}},
+	"003": fixedTransferLayout, // IBK기업은행
+	"011": fixedTransferLayout, // NH농협은행
+}
+
+// transferLayoutFor returns the withdrawal bank's layout; format (csv, fixed) overrides the bank's
+// own, a bank without a CSV layout getting the generic one
+func transferLayoutFor(bankCode, format string) (transferLayout, bool) {
+	layout, ok := transferLayouts[bankCode]
+	if !ok {
+		layout = genericTransferLayout
+	}
+	switch format {
+	case "", layout.Format:
+		return layout, true
+	case "fixed":
+		return fixedTransferLayout, true
+	case "csv":
+		return genericTransferLayout, true
+	}
+	return transferLayout{}, false
+}
+
+// eucKR returns an encoder for the EUC-KR files banks accept; encoders keep state, so each file gets its own
+func eucKR() *encoding.Encoder {
+	return encoding.ReplaceUnsupported(korean.EUCKR.NewEncoder())
+}
+
+// normalizeAccountNumber drops hyphens and spaces; account numbers are 10 to 16 digits
+func normalizeAccountNumber(accountNumber string) (string, bool) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(accountNumber)
+	if len(digits) < 10 || len(digits) > 16 {
+		return "", false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return digits, true
+}
+
+func maskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return strings.Repeat("*", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
+}
+
+type BankAccountRequest struct {
+	BankCode      string `json:"bank_code" binding:"required"`
+	AccountNumber string `json:"account_number" binding:"required"`
+	AccountHolder string `json:"account_holder" binding:"required"`
+}
+
+// GetEmployeeBankAccount returns the employee's payroll account with the account number masked
+func GetEmployeeBankAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var account models.EmployeeBankAccount
+	var encrypted string
+	err = database.DB.QueryRow(`
+		SELECT id, employee_id, bank_code, account_number_encrypted, account_holder, created_at, updated_at
+		FROM employee_bank_accounts WHERE employee_id = ?
+	`, id).Scan(&account.ID, &account.EmployeeID, &account.BankCode, &encrypted, &account.AccountHolder,
+		&account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee has no payroll account"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	accountNumber, err := decryptAccountNumber(encrypted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decrypt account number"})
+		return
+	}
+	account.BankName = bankNames[account.BankCode]
+	account.AccountNumber = maskAccountNumber(accountNumber)
+
+	c.JSON(http.StatusOK, account)
+}
+
+// UpdateEmployeeBankAccount sets the account the employee's net pay is transferred to
+func UpdateEmployeeBankAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req BankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := bankNames[req.BankCode]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown bank code"})
+		return
+	}
+	accountNumber, ok := normalizeAccountNumber(req.AccountNumber)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account number must be 10 to 16 digits"})
+		return
+	}
+	holder := strings.TrimSpace(req.AccountHolder)
+
+	var exists int
+	if err := database.DB.QueryRow("SELECT 1 FROM employees WHERE id = ?", id).Scan(&exists); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	encrypted, err := encryptAccountNumber(accountNumber)
+	if keyErr, ok := err.(*encryptionKeyError); ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": keyErr.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt account number"})
+		return
+	}
+
+	_, err = database.DB.Exec(`
+		INSERT INTO employee_bank_accounts (employee_id, bank_code, account_number_encrypted, account_holder)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(employee_id) DO UPDATE SET
+			bank_code = excluded.bank_code,
+			account_number_encrypted = excluded.account_number_encrypted,
+			account_holder = excluded.account_holder,
+			updated_at = CURRENT_TIMESTAMP
+	`, id, req.BankCode, encrypted, holder)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save payroll account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employee_id":    id,
+		"bank_code":      req.BankCode,
+		"bank_name":      bankNames[req.BankCode],
+		"account_number": maskAccountNumber(accountNumber),
+		"account_holder": holder,
+	})
+}
+
+// transferLine is one transfer of a batch, with the account number decrypted
+type transferLine struct {
+	PayrollID     int
+	BankCode      string
+	AccountNumber string
+	AccountHolder string
+	Amount        models.Money
+}
+
+// fixedField encodes the text in EUC-KR, cut to whole characters within width bytes and padded with spaces
+func fixedField(text string, width int) []byte {
+	runes := []rune(text)
+	for {
+		encoded, _ := eucKR().Bytes([]byte(string(runes)))
+		if len(encoded) <= width {
+			return append(encoded, bytes.Repeat([]byte(" "), width-len(encoded))...)
+		}
+		runes = runes[:len(runes)-1]
+	}
+}
+
+func fixedRecord(length int, fields ...[]byte) []byte {
+	record := bytes.Join(fields, nil)
+	if len(record) < length {
+		record = append(record, bytes.Repeat([]byte(" "), length-len(record))...)
+	}
+	return append(record[:length], '\r', '\n')
+}
+
+// transferHeader is what a transfer file says about the batch as a whole
+type transferHeader struct {
+	BankCode     string // 출금 은행
+	TransferDate time.Time
+	CompanyName  string
+	Memo         string // 받는분 통장 표시
+}
+
+// transferControl is the control total of a batch that the bank checks the upload against: the number
+// of transfers, their total and the hash total of the account numbers
+type transferControl struct {
+	Count       int
+	Total       models.Money
+	AccountHash int64
+}
+
+// accountHashModulus keeps the account number hash total within its 16 digits
+const accountHashModulus = 10_000_000_000_000_000
+
+func transferControlTotal(lines []transferLine) transferControl {
+	control := transferControl{Count: len(lines)}
+	for _, line := range lines {
+		control.Total += line.Amount
+		number, _ := strconv.ParseInt(line.AccountNumber, 10, 64)
+		control.AccountHash = (control.AccountHash + number%accountHashModulus) % accountHashModulus
+	}
+	return control
+}
+
+// buildTransferFile writes the batch in the layout, ending with the control total
+func buildTransferFile(layout transferLayout, header transferHeader, lines []transferLine) ([]byte, transferControl) {
+	control := transferControlTotal(lines)
+	if layout.Format == "fixed" {
+		return buildFixedTransferFile(header, lines, control), control
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(eucKR().Writer(&buf))
+	headers := make([]string, len(layout.Columns))
+	for i, column := range layout.Columns {
+		headers[i] = column.Header
+	}
+	writer.Write(headers)
+	for _, line := range lines {
+		row := make([]string, len(layout.Columns))
+		for i, column := range layout.Columns {
+			row[i] = column.Value(line, header.Memo)
+		}
+		writer.Write(row)
+	}
+	writer.Write([]string{"합계", strconv.Itoa(control.Count), strconv.FormatInt(int64(control.Total), 10),
+		fmt.Sprintf("%016d", control.AccountHash)})
+	writer.Flush()
+	return buf.Bytes(), control
+}
+
+// transferRecordLength is the record length of the fixed-width transfer 전문
+const transferRecordLength = 100
+
+// buildFixedTransferFile writes the fixed-width 전문 in EUC-KR: a header record (H) for the batch, a
+// data record (D) per transfer and a trailer record (T) with the control total. The positions in the
+// comments are 1-based byte offsets.
+func buildFixedTransferFile(header transferHeader, lines []transferLine, control transferControl) []byte {
+	var buf bytes.Buffer
+	buf.Write(fixedRecord(transferRecordLength,
+		[]byte("H"), fixedField(header.BankCode, 3), []byte(header.TransferDate.Format("20060102")), // 1, 2 출금은행, 5 이체일
+		fixedField(header.CompanyName, 20), fixedField(header.Memo, 16))) // 13 회사명, 33 받는분통장표시
+
+	for i, line := range lines {
+		buf.Write(fixedRecord(transferRecordLength,
+			[]byte("D"), []byte(fmt.Sprintf("%06d", i+1)), fixedField(line.BankCode, 3), // 1, 2 일련번호, 8 입금은행
+			fixedField(line.AccountNumber, 16), fixedAmount(line.Amount, 13), // 11 입금계좌번호, 27 입금액
+			fixedField(line.AccountHolder, 20), fixedField(header.Memo, 16))) // 40 예금주, 60 받는분통장표시
+	}
+
+	buf.Write(fixedRecord(transferRecordLength,
+		[]byte("T"), []byte(fmt.Sprintf("%06d", control.Count)), fixedAmount(control.Total, 15), // 1, 2 총건수, 8 총금액
+		[]byte(fmt.Sprintf("%016d", control.AccountHash)))) // 23 계좌번호 합계
+	return buf.Bytes()
+}
+
+const transferBatchSelectQuery = `
+	SELECT id, payroll_run_id, bank_code, transfer_date, record_count, total_amount,
+	       COALESCE(file_format, 'csv'), status, created_by, created_at, transferred_at
+	FROM payroll_transfer_batches`
+
+func scanTransferBatch(row rowScanner) (*models.PayrollTransferBatch, error) {
+	var batch models.PayrollTransferBatch
+	err := row.Scan(&batch.ID, &batch.PayrollRunID, &batch.BankCode, &batch.TransferDate,
+		&batch.RecordCount, &batch.TotalAmount, &batch.FileFormat, &batch.Status, &batch.CreatedBy,
+		&batch.CreatedAt, &batch.TransferredAt)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// loadTransferBatch loads a batch of the run in the URL (:id, :batchId)
+func loadTransferBatch(c *gin.Context, db dbtx) (*models.PayrollTransferBatch, bool) {
+	runID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return nil, false
+	}
+	batchID, err := strconv.Atoi(c.Param("batchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer batch ID"})
+		return nil, false
+	}
+
+	batch, err := scanTransferBatch(db.QueryRow(transferBatchSelectQuery+" WHERE id = ? AND payroll_run_id = ?", batchID, runID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transfer batch not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return nil, false
+	}
+	return batch, true
+}
+
+type CreateTransferBatchRequest struct {
+	BankCode     string `json:"bank_code"`     // 출금 은행, defaults to the payroll_bank_code setting
+	TransferDate string `json:"transfer_date"` // defaults to the run's pay date
+	Memo         string `json:"memo"`          // 받는분 통장 표시, defaults to "<company> 급여"
+	Format       string `json:"format"`        // csv, fixed; defaults to the withdrawal bank's layout
+}
+
+// CreatePayrollTransferBatch exports the net pay of an approved run's unpaid records to a bulk
+// transfer file. Records already in a batch are left out, as are employees without an account.
+func CreatePayrollTransferBatch(c *gin.Context) {
+	runID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	var req CreateTransferBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	run, err := loadPayrollRun(tx, runID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if run.Status != "approved" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll run must be approved to export transfers", "status": run.Status})
+		return
+	}
+
+	settings, err := loadSettings(tx, "company_name", "payroll_bank_code")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load company settings"})
+		return
+	}
+	bankCode := req.BankCode
+	if bankCode == "" {
+		bankCode = settings["payroll_bank_code"]
+	}
+	if _, ok := bankNames[bankCode]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown withdrawal bank code " + bankCode})
+		return
+	}
+	layout, ok := transferLayoutFor(bankCode, req.Format)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format (csv, fixed)"})
+		return
+	}
+	memo := strings.TrimSpace(req.Memo)
+	if memo == "" {
+		memo = strings.TrimSpace(settings["company_name"] + " 급여")
+	}
+
+	var transferDate time.Time
+	switch {
+	case req.TransferDate != "":
+		transferDate, err = time.Parse("2006-01-02", req.TransferDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer date format (YYYY-MM-DD)"})
+			return
+		}
+	case run.PayDate.Valid:
+		transferDate = run.PayDate.Time
+	default:
+		transferDate = time.Now().Truncate(24 * time.Hour)
+	}
+
+	rows, err := tx.Query(`
+		SELECT p.id, p.employee_id, e.name, p.net_pay, a.bank_code, a.account_number_encrypted, a.account_holder
+		FROM payroll_records p
+		JOIN employees e ON e.id = p.employee_id
+		LEFT JOIN employee_bank_accounts a ON a.employee_id = p.employee_id
+		WHERE p.payroll_run_id = ? AND p.is_paid = 0 AND p.net_pay > 0
+		  AND p.id NOT IN (SELECT payroll_id FROM payroll_transfer_items)
+		ORDER BY e.name
+	`, runID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load payroll records"})
+		return
+	}
+	lines := []transferLine{}
+	skipped := []gin.H{}
+	for rows.Next() {
+		var line transferLine
+		var employeeID int
+		var employeeName string
+		var bankCode, encrypted, holder sql.NullString
+		if err := rows.Scan(&line.PayrollID, &employeeID, &employeeName, &line.Amount, &bankCode, &encrypted, &holder); err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan payroll record"})
+			return
+		}
+		if !encrypted.Valid {
+			skipped = append(skipped, gin.H{"employee_id": employeeID, "employee_name": employeeName, "reason": "no payroll account"})
+			continue
+		}
+		line.AccountNumber, err = decryptAccountNumber(encrypted.String)
+		if err != nil {
+			skipped = append(skipped, gin.H{"employee_id": employeeID, "employee_name": employeeName, "reason": "account number cannot be decrypted"})
+			continue
+		}
+		line.BankCode = bankCode.String
+		line.AccountHolder = holder.String
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load payroll records"})
+		return
+	}
+	if len(lines) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No unpaid payroll records with a payroll account to transfer", "skipped": skipped})
+		return
+	}
+
+	content, control := buildTransferFile(layout, transferHeader{
+		BankCode: bankCode, TransferDate: transferDate, CompanyName: settings["company_name"], Memo: memo,
+	}, lines)
+
+	userID, _ := c.Get("user_id")
+	result, err := tx.Exec(`
+		INSERT INTO payroll_transfer_batches (payroll_run_id, bank_code, transfer_date, record_count,
+			total_amount, file_format, file_content, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, runID, bankCode, transferDate, control.Count, control.Total, layout.Format, content, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transfer batch"})
+		return
+	}
+	batchID, _ := result.LastInsertId()
+
+	for _, line := range lines {
+		_, err := tx.Exec("INSERT INTO payroll_transfer_items (batch_id, payroll_id, amount) VALUES (?, ?, ?)",
+			batchID, line.PayrollID, line.Amount)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transfer items"})
+			return
+		}
+	}
+
+	batch, err := scanTransferBatch(tx.QueryRow(transferBatchSelectQuery+" WHERE id = ?", batchID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load transfer batch"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"batch": batch, "skipped": skipped})
+}
+
+// GetPayrollTransferBatches lists the transfer batches exported for a run
+func GetPayrollTransferBatches(c *gin.Context) {
+	runID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	rows, err := database.DB.Query(transferBatchSelectQuery+" WHERE payroll_run_id = ? ORDER BY id", runID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	batches := []models.PayrollTransferBatch{}
+	for rows.Next() {
+		batch, err := scanTransferBatch(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan transfer batch"})
+			return
+		}
+		batches = append(batches, *batch)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batches": batches})
+}
+
+// DownloadPayrollTransferBatch returns the exported file as it was generated
+func DownloadPayrollTransferBatch(c *gin.Context) {
+	batch, ok := loadTransferBatch(c, database.DB)
+	if !ok {
+		return
+	}
+
+	var content []byte
+	var payPeriod string
+	err := database.DB.QueryRow(`
+		SELECT b.file_content, r.pay_period FROM payroll_transfer_batches b
+		JOIN payroll_runs r ON r.id = b.payroll_run_id
+		WHERE b.id = ?
+	`, batch.ID).Scan(&content, &payPeriod)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load transfer file"})
+		return
+	}
+
+	extension, contentType := "csv", "text/csv; charset=euc-kr"
+	if batch.FileFormat == "fixed" {
+		extension, contentType = "txt", "text/plain; charset=euc-kr"
+	}
+	filename := fmt.Sprintf("payroll_transfer_%s_%s_%d.%s", payPeriod, batch.BankCode, batch.ID, extension)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// DeletePayrollTransferBatch discards an exported batch that was not transferred, so its
+// records can be exported again
+func DeletePayrollTransferBatch(c *gin.Context) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	batch, ok := loadTransferBatch(c, tx)
+	if !ok {
+		return
+	}
+	if batch.Status != "exported" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Transferred batches cannot be deleted"})
+		return
+	}
+
+	if _, err := tx.Exec("DELETE FROM payroll_transfer_items WHERE batch_id = ?", batch.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete transfer batch"})
+		return
+	}
+	if _, err := tx.Exec("DELETE FROM payroll_transfer_batches WHERE id = ?", batch.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete transfer batch"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer batch deleted successfully"})
+}
+
+// MarkPayrollTransferBatchTransferred records that the bank executed the batch: its records are
+// paid on the transfer date, and the run becomes paid once no record with pay to transfer is left
+// unpaid. Records without net pay are settled with the run; records of employees without an account
+// are paid outside the batches and the run marked paid with MarkPayrollRunPaid.
+func MarkPayrollTransferBatchTransferred(c *gin.Context) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	batch, ok := loadTransferBatch(c, tx)
+	if !ok {
+		return
+	}
+	if batch.Status != "exported" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Transfer batch is already transferred"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		UPDATE payroll_transfer_batches SET status = 'transferred', transferred_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, batch.ID)
+	if err == nil {
+		_, err = tx.Exec(`
+			UPDATE payroll_records SET is_paid = 1, pay_date = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id IN (SELECT payroll_id FROM payroll_transfer_items WHERE batch_id = ?)
+		`, batch.TransferDate, batch.ID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark transfer batch as transferred"})
+		return
+	}
+
+	var unpaid int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM payroll_records WHERE payroll_run_id = ? AND is_paid = 0 AND net_pay > 0
+	`, batch.PayrollRunID).Scan(&unpaid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	runStatus := "approved"
+	if unpaid == 0 {
+		runStatus = "paid"
+		_, err = tx.Exec(`
+			UPDATE payroll_records SET is_paid = 1, pay_date = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE payroll_run_id = ? AND is_paid = 0
+		`, batch.TransferDate, batch.PayrollRunID)
+		if err == nil {
+			_, err = tx.Exec(`
+				UPDATE payroll_runs SET status = 'paid', pay_date = COALESCE(pay_date, ?), paid_at = CURRENT_TIMESTAMP,
+					updated_at = CURRENT_TIMESTAMP
+				WHERE id = ? AND status = 'approved'
+			`, batch.TransferDate, batch.PayrollRunID)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll run"})
+			return
+		}
+	}
+
+	batch, err = scanTransferBatch(tx.QueryRow(transferBatchSelectQuery+" WHERE id = ?", batch.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load transfer batch"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch": batch, "run_status": runStatus, "unpaid_records": unpaid})
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/korean"
+)
+
+var testTransferLines = []transferLine{
+	{PayrollID: 1, BankCode: "088", AccountNumber: "110123456789", AccountHolder: "홍길동", Amount: 2850000},
+	{PayrollID: 2, BankCode: "004", AccountNumber: "12345678901234", AccountHolder: "김영희", Amount: 3120500},
+	{PayrollID: 3, BankCode: "090", AccountNumber: "3333012345678", AccountHolder: "이철수", Amount: 1999990},
+}
+
+var testTransferHeader = transferHeader{
+	BankCode:     "011",
+	TransferDate: time.Date(2025, time.April, 25, 0, 0, 0, 0, time.UTC),
+	CompanyName:  "주식회사 한빛",
+	Memo:         "한빛 급여",
+}
+
+func TestTransferControlTotal(t *testing.T) {
+	control := transferControlTotal(testTransferLines)
+	if control.Count != 3 {
+		t.Errorf("count = %d, want 3", control.Count)
+	}
+	if control.Total != 7970490 {
+		t.Errorf("total = %d, want 7970490", control.Total)
+	}
+	// 110,123,456,789 + 12,345,678,901,234 + 3,333,012,345,678
+	if control.AccountHash != 15788814703701 {
+		t.Errorf("account hash = %d, want 15788814703701", control.AccountHash)
+	}
+}
+
+func TestBuildTransferFileCSVTrailer(t *testing.T) {
+	layout, _ := transferLayoutFor("004", "")
+	content, control := buildTransferFile(layout, testTransferHeader, testTransferLines)
+	decoded, err := korean.EUCKR.NewDecoder().Bytes(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := csv.NewReader(bytes.NewReader(decoded))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want header, 3 transfers and the trailer", len(rows))
+	}
+	if got := rows[1]; got[0] != "088" || got[1] != "110123456789" || got[2] != "2850000" || got[3] != "홍길동" || got[4] != "한빛 급여" {
+		t.Errorf("first transfer = %v", got)
+	}
+	trailer := rows[4]
+	want := []string{"합계", "3", "7970490", "0015788814703701"}
+	if len(trailer) != len(want) {
+		t.Fatalf("trailer = %v, want %v", trailer, want)
+	}
+	for i := range want {
+		if trailer[i] != want[i] {
+			t.Errorf("trailer[%d] = %q, want %q", i, trailer[i], want[i])
+		}
+	}
+	if control.Count != 3 || control.Total != 7970490 {
+		t.Errorf("control = %+v", control)
+	}
+}
+
+// The positions are the 1-based byte offsets of the fixed-width 전문
+func TestBuildFixedTransferFile(t *testing.T) {
+	layout, _ := transferLayoutFor("011", "")
+	if layout.Format != "fixed" {
+		t.Fatalf("NH농협은행 layout = %s, want fixed", layout.Format)
+	}
+	content, _ := buildTransferFile(layout, testTransferHeader, testTransferLines)
+	records := bytes.Split(bytes.TrimSuffix(content, []byte("\r\n")), []byte("\r\n"))
+	if len(records) != 5 {
+		t.Fatalf("got %d records, want header, 3 data records and the trailer", len(records))
+	}
+	for i, record := range records {
+		if len(record) != transferRecordLength {
+			t.Errorf("record %d is %d bytes, want %d", i+1, len(record), transferRecordLength)
+		}
+	}
+
+	field := func(text string, width int) string { return string(fixedField(text, width)) }
+	tests := []struct {
+		name     string
+		record   int
+		position int
+		want     string
+	}{
+		{"H 출금은행과 이체일", 0, 1, "H01120250425"},
+		{"H 회사명", 0, 13, field("주식회사 한빛", 20)},
+		{"D 일련번호와 입금은행", 1, 1, "D000001088"},
+		{"D 입금계좌번호", 1, 11, field("110123456789", 16)},
+		{"D 입금액", 1, 27, "0000002850000"},
+		{"D 예금주", 1, 40, field("홍길동", 20)},
+		{"D 받는분통장표시", 1, 60, field("한빛 급여", 16)},
+		{"D 세 번째 일련번호", 3, 2, "000003"},
+		{"T 총건수와 총금액", 4, 1, "T000003000000007970490"},
+		{"T 계좌번호 합계", 4, 23, "0015788814703701"},
+		{"T 계좌번호 합계 뒤는 공란", 4, 39, "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := records[tt.record]
+			start := tt.position - 1
+			if got := string(record[start : start+len(tt.want)]); got != tt.want {
+				t.Errorf("position %d = %q, want %q", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferLayoutFor(t *testing.T) {
+	tests := []struct {
+		bankCode, format string
+		want             string
+		wantOK           bool
+	}{
+		{"004", "", "csv", true},
+		{"011", "", "fixed", true},
+		{"004", "fixed", "fixed", true},
+		{"011", "csv", "csv", true},
+		{"045", "", "csv", true},
+		{"004", "xlsx", "", false},
+	}
+	for _, tt := range tests {
+		layout, ok := transferLayoutFor(tt.bankCode, tt.format)
+		if ok != tt.wantOK || layout.Format != tt.want {
+			t.Errorf("transferLayoutFor(%s, %q) = %s, %v, want %s, %v", tt.bankCode, tt.format, layout.Format, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"labor-management-system/database"
 	"labor-management-system/internal/models"
 	"net/http"
@@ -18,14 +19,25 @@ type CreateContractRequest struct {
 	EndDate        string  `json:"end_date"`
 	Workplace      string  `json:"workplace" binding:"required"`
 	JobDescription string  `json:"job_description"`
-	WorkingHours   string  `json:"working_hours" binding:"required"`
-	WorkDays       string  `json:"work_days" binding:"required"`
+	WorkingHours   string  `json:"working_hours"`
+	WorkDays       string  `json:"work_days"`
+	BreakTime      string  `json:"break_time"`
+	WeeklyHoliday  string  `json:"weekly_holiday"`
+	WorkScheduleID int     `json:"work_schedule_id"` // replaces the four fields above when given
 	BaseSalary     float64 `json:"base_salary" binding:"required"`
 	Allowances     string  `json:"allowances"`
+	AllowanceItems []models.ContractAllowance `json:"allowance_items"`
 	Benefits       string  `json:"benefits"`
 	ContractTerms  string  `json:"contract_terms"`
 }
 
+// AmendContractRequest carries the full amended terms of a contract
+type AmendContractRequest struct {
+	CreateContractRequest
+	EffectiveDate string `json:"effective_date"`
+	Reason        string `json:"reason"`
+}
+
 // Request struct for creating contract with new employee
 type CreateContractWithEmployeeRequest struct {
 	// Employee information
@@ -46,23 +58,111 @@ type CreateContractWithEmployeeRequest struct {
 	EndDate        string  `json:"end_date"`
 	Workplace      string  `json:"workplace" binding:"required"`
 	JobDescription string  `json:"job_description"`
-	WorkingHours   string  `json:"working_hours" binding:"required"`
-	WorkDays       string  `json:"work_days" binding:"required"`
+	WorkingHours   string  `json:"working_hours"`
+	WorkDays       string  `json:"work_days"`
+	BreakTime      string  `json:"break_time"`
+	WeeklyHoliday  string  `json:"weekly_holiday"`
+	WorkScheduleID int     `json:"work_schedule_id"` // replaces the four fields above when given
 	BaseSalary     float64 `json:"base_salary" binding:"required"`
 	Allowances     string  `json:"allowances"`
+	AllowanceItems []models.ContractAllowance `json:"allowance_items"`
 	Benefits       string  `json:"benefits"`
 	ContractTerms  string  `json:"contract_terms"`
 }
 
+// contractSelectQuery selects contracts joined with the employee's name and number
+const contractSelectQuery = `
+	SELECT c.id, c.employee_id, c.contract_type, c.start_date, c.end_date, 
+	       c.workplace, c.job_description, c.working_hours, c.work_days, 
+	       c.break_time, c.weekly_holiday, c.work_schedule_id,
+	       c.base_salary, c.allowances, c.benefits, c.contract_terms, 
+	       c.signed_date, c.is_active, c.current_version, c.locked_at,
+	       c.previous_contract_id, c.next_contract_id, c.created_at, c.updated_at,
+	       e.name as employee_name, e.employee_number
+	FROM employment_contracts c
+	JOIN employees e ON c.employee_id = e.id
+`
+
+// scanContractWithEmployee scans a contractSelectQuery row into the response shape used by the contract endpoints
+func scanContractWithEmployee(row rowScanner) (map[string]interface{}, error) {
+	var contract models.EmploymentContract
+	var employeeName, employeeNumber string
+
+	err := row.Scan(
+		&contract.ID, &contract.EmployeeID, &contract.ContractType,
+		&contract.StartDate, &contract.EndDate, &contract.Workplace,
+		&contract.JobDescription, &contract.WorkingHours, &contract.WorkDays,
+		&contract.BreakTime, &contract.WeeklyHoliday, &contract.WorkScheduleID,
+		&contract.BaseSalary, &contract.Allowances, &contract.Benefits,
+		&contract.ContractTerms, &contract.SignedDate, &contract.IsActive,
+		&contract.CurrentVersion, &contract.LockedAt,
+		&contract.PreviousContractID, &contract.NextContractID,
+		&contract.CreatedAt, &contract.UpdatedAt, &employeeName, &employeeNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"contract":        contract,
+		"employee_name":   employeeName,
+		"employee_number": employeeNumber,
+	}, nil
+}
+
+// insertContract saves a new contract for the employee with the given terms
+func insertContract(tx dbtx, employeeID int, terms models.ContractTerms) (int64, error) {
+	startDate, err := time.Parse("2006-01-02", terms.StartDate)
+	if err != nil {
+		return 0, err
+	}
+
+	var endDate sql.NullTime
+	if terms.EndDate != "" {
+		ed, err := time.Parse("2006-01-02", terms.EndDate)
+		if err != nil {
+			return 0, err
+		}
+		endDate = sql.NullTime{Time: ed, Valid: true}
+	}
+
+	var scheduleID sql.NullInt64
+	if terms.WorkScheduleID != 0 {
+		scheduleID = sql.NullInt64{Int64: int64(terms.WorkScheduleID), Valid: true}
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO employment_contracts (employee_id, contract_type, start_date, end_date, 
+		                                 workplace, job_description, working_hours, work_days, 
+		                                 break_time, weekly_holiday, work_schedule_id, base_salary,
+		                                 allowances, benefits, contract_terms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, employeeID, terms.ContractType, startDate, endDate, terms.Workplace,
+		terms.JobDescription, terms.WorkingHours, terms.WorkDays, terms.BreakTime,
+		terms.WeeklyHoliday, scheduleID, terms.BaseSalary, terms.Allowances, terms.Benefits,
+		terms.ContractTerms)
+	if err != nil {
+		return 0, err
+	}
+
+	contractID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := saveContractAllowances(tx, contractID, terms.AllowanceItems); err != nil {
+		return 0, err
+	}
+
+	if err := insertInitialContractVersion(tx, contractID, terms, startDate, sql.NullTime{}); err != nil {
+		return 0, err
+	}
+
+	return contractID, nil
+}
+
 func GetContracts(c *gin.Context) {
-	rows, err := database.DB.Query(`
-		SELECT c.id, c.employee_id, c.contract_type, c.start_date, c.end_date, 
-		       c.workplace, c.job_description, c.working_hours, c.work_days, 
-		       c.base_salary, c.allowances, c.benefits, c.contract_terms, 
-		       c.signed_date, c.is_active, c.created_at, c.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM employment_contracts c
-		JOIN employees e ON c.employee_id = e.id
+	rows, err := database.DB.Query(contractSelectQuery + `
 		WHERE c.is_active = 1
 		ORDER BY c.created_at DESC
 	`)
@@ -74,27 +174,11 @@ func GetContracts(c *gin.Context) {
 
 	var contracts []map[string]interface{}
 	for rows.Next() {
-		var contract models.EmploymentContract
-		var employeeName, employeeNumber string
-		
-		err := rows.Scan(
-			&contract.ID, &contract.EmployeeID, &contract.ContractType, 
-			&contract.StartDate, &contract.EndDate, &contract.Workplace,
-			&contract.JobDescription, &contract.WorkingHours, &contract.WorkDays,
-			&contract.BaseSalary, &contract.Allowances, &contract.Benefits,
-			&contract.ContractTerms, &contract.SignedDate, &contract.IsActive,
-			&contract.CreatedAt, &contract.UpdatedAt, &employeeName, &employeeNumber,
-		)
+		contractData, err := scanContractWithEmployee(rows)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan contract"})
 			return
 		}
-
-		contractData := map[string]interface{}{
-			"contract":        contract,
-			"employee_name":   employeeName,
-			"employee_number": employeeNumber,
-		}
 		contracts = append(contracts, contractData)
 	}
 
@@ -108,26 +192,9 @@ func GetContract(c *gin.Context) {
 		return
 	}
 
-	var contract models.EmploymentContract
-	var employeeName, employeeNumber string
-
-	err = database.DB.QueryRow(`
-		SELECT c.id, c.employee_id, c.contract_type, c.start_date, c.end_date, 
-		       c.workplace, c.job_description, c.working_hours, c.work_days, 
-		       c.base_salary, c.allowances, c.benefits, c.contract_terms, 
-		       c.signed_date, c.is_active, c.created_at, c.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM employment_contracts c
-		JOIN employees e ON c.employee_id = e.id
+	contractData, err := scanContractWithEmployee(database.DB.QueryRow(contractSelectQuery+`
 		WHERE c.id = ?
-	`, id).Scan(
-		&contract.ID, &contract.EmployeeID, &contract.ContractType,
-		&contract.StartDate, &contract.EndDate, &contract.Workplace,
-		&contract.JobDescription, &contract.WorkingHours, &contract.WorkDays,
-		&contract.BaseSalary, &contract.Allowances, &contract.Benefits,
-		&contract.ContractTerms, &contract.SignedDate, &contract.IsActive,
-		&contract.CreatedAt, &contract.UpdatedAt, &employeeName, &employeeNumber,
-	)
+	`, id))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -138,11 +205,12 @@ func GetContract(c *gin.Context) {
 		return
 	}
 
-	contractData := map[string]interface{}{
-		"contract":        contract,
-		"employee_name":   employeeName,
-		"employee_number": employeeNumber,
+	allowanceItems, err := loadContractAllowances(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load allowance items"})
+		return
 	}
+	contractData["allowance_items"] = allowanceItems
 
 	c.JSON(http.StatusOK, contractData)
 }
@@ -154,26 +222,84 @@ func CreateContract(c *gin.Context) {
 		return
 	}
 
-	// Parse start date
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
+	// Validate dates
+	if _, err := time.Parse("2006-01-02", req.StartDate); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format (YYYY-MM-DD)"})
 		return
 	}
-
-	// Parse end date if provided
-	var endDate sql.NullTime
 	if req.EndDate != "" {
-		ed, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
+		if _, err := time.Parse("2006-01-02", req.EndDate); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format (YYYY-MM-DD)"})
 			return
 		}
-		endDate = sql.NullTime{Time: ed, Valid: true}
+	}
+
+	terms := contractTermsFromRequest(req)
+	schedule, err := applyWorkSchedule(database.DB, &terms)
+	if err != nil {
+		respondWorkScheduleError(c, err)
+		return
+	}
+
+	terms.AllowanceItems, err = resolveContractAllowances(database.DB, terms.AllowanceItems)
+	if err != nil {
+		respondAllowanceError(c, err)
+		return
+	}
+
+	salaryType, err := employeeSalaryType(database.DB, req.EmployeeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	issues, err := validateContractCompliance(database.DB, terms, salaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if hasBlockingIssue(issues) {
+		respondComplianceViolation(c, issues)
+		return
+	}
+
+	fixedTerm, err := fixedTermIssue(database.DB, req.EmployeeID, terms)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check fixed-term period"})
+		return
+	}
+	if fixedTerm != nil {
+		issues = append(issues, *fixedTerm)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// An e-signed active contract is only replaced through a renewal
+	var lockedContracts int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM employment_contracts
+		WHERE employee_id = ? AND is_active = 1 AND locked_at IS NOT NULL
+	`, req.EmployeeID).Scan(&lockedContracts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if lockedContracts > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "The active contract is locked by an electronic signature; renew it to change the terms"})
+		return
 	}
 
 	// Deactivate existing contracts for the employee
-	_, err = database.DB.Exec(`
+	_, err = tx.Exec(`
 		UPDATE employment_contracts SET is_active = 0, updated_at = CURRENT_TIMESTAMP 
 		WHERE employee_id = ? AND is_active = 1
 	`, req.EmployeeID)
@@ -184,58 +310,35 @@ func CreateContract(c *gin.Context) {
 	}
 
 	// Insert new contract
-	result, err := database.DB.Exec(`
-		INSERT INTO employment_contracts (employee_id, contract_type, start_date, end_date, 
-		                                 workplace, job_description, working_hours, work_days, 
-		                                 base_salary, allowances, benefits, contract_terms)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, req.EmployeeID, req.ContractType, startDate, endDate, req.Workplace,
-		req.JobDescription, req.WorkingHours, req.WorkDays, req.BaseSalary,
-		req.Allowances, req.Benefits, req.ContractTerms)
-
+	contractID, err := insertContract(tx, req.EmployeeID, terms)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract"})
 		return
 	}
 
-	contractID, _ := result.LastInsertId()
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
 
 	// Retrieve created contract with employee info
-	var contract models.EmploymentContract
-	var employeeName, employeeNumber string
-
-	err = database.DB.QueryRow(`
-		SELECT c.id, c.employee_id, c.contract_type, c.start_date, c.end_date, 
-		       c.workplace, c.job_description, c.working_hours, c.work_days, 
-		       c.base_salary, c.allowances, c.benefits, c.contract_terms, 
-		       c.signed_date, c.is_active, c.created_at, c.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM employment_contracts c
-		JOIN employees e ON c.employee_id = e.id
+	contractData, err := scanContractWithEmployee(database.DB.QueryRow(contractSelectQuery+`
 		WHERE c.id = ?
-	`, contractID).Scan(
-		&contract.ID, &contract.EmployeeID, &contract.ContractType,
-		&contract.StartDate, &contract.EndDate, &contract.Workplace,
-		&contract.JobDescription, &contract.WorkingHours, &contract.WorkDays,
-		&contract.BaseSalary, &contract.Allowances, &contract.Benefits,
-		&contract.ContractTerms, &contract.SignedDate, &contract.IsActive,
-		&contract.CreatedAt, &contract.UpdatedAt, &employeeName, &employeeNumber,
-	)
+	`, contractID))
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created contract"})
 		return
 	}
-
-	contractData := map[string]interface{}{
-		"contract":        contract,
-		"employee_name":   employeeName,
-		"employee_number": employeeNumber,
-	}
+	contractData["compliance_issues"] = issues
 
 	c.JSON(http.StatusCreated, contractData)
 }
 
+// UpdateContract records a change to a contract as an amendment (변경계약).
+// The signed terms of earlier versions are kept in contract_versions. The contract
+// row is moved to the amended terms on the effective date; amendments effective in
+// the future are applied by ApplyDueContractAmendments.
 func UpdateContract(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -243,81 +346,167 @@ func UpdateContract(c *gin.Context) {
 		return
 	}
 
-	var req CreateContractRequest
+	var req AmendContractRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Parse start date
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
+	// Validate dates
+	if _, err := time.Parse("2006-01-02", req.StartDate); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format (YYYY-MM-DD)"})
 		return
 	}
-
-	// Parse end date if provided
-	var endDate sql.NullTime
 	if req.EndDate != "" {
-		ed, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
+		if _, err := time.Parse("2006-01-02", req.EndDate); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format (YYYY-MM-DD)"})
 			return
 		}
-		endDate = sql.NullTime{Time: ed, Valid: true}
 	}
 
-	// Update contract
-	_, err = database.DB.Exec(`
-		UPDATE employment_contracts SET contract_type = ?, start_date = ?, end_date = ?, 
-		                               workplace = ?, job_description = ?, working_hours = ?, 
-		                               work_days = ?, base_salary = ?, allowances = ?, 
-		                               benefits = ?, contract_terms = ?, 
-		                               updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`, req.ContractType, startDate, endDate, req.Workplace, req.JobDescription,
-		req.WorkingHours, req.WorkDays, req.BaseSalary, req.Allowances,
-		req.Benefits, req.ContractTerms, id)
+	today := time.Now().Truncate(24 * time.Hour)
+	effectiveDate := today
+	if req.EffectiveDate != "" {
+		effectiveDate, err = time.Parse("2006-01-02", req.EffectiveDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective date format (YYYY-MM-DD)"})
+			return
+		}
+	}
 
+	tx, err := database.DB.Begin()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contract"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
 		return
 	}
+	defer tx.Rollback()
 
-	// Retrieve updated contract with employee info
-	var contract models.EmploymentContract
-	var employeeName, employeeNumber string
+	current, err := ensureInitialContractVersion(tx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contract versions"})
+		}
+		return
+	}
 
-	err = database.DB.QueryRow(`
-		SELECT c.id, c.employee_id, c.contract_type, c.start_date, c.end_date, 
-		       c.workplace, c.job_description, c.working_hours, c.work_days, 
-		       c.base_salary, c.allowances, c.benefits, c.contract_terms, 
-		       c.signed_date, c.is_active, c.created_at, c.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM employment_contracts c
-		JOIN employees e ON c.employee_id = e.id
-		WHERE c.id = ?
-	`, id).Scan(
-		&contract.ID, &contract.EmployeeID, &contract.ContractType,
-		&contract.StartDate, &contract.EndDate, &contract.Workplace,
-		&contract.JobDescription, &contract.WorkingHours, &contract.WorkDays,
-		&contract.BaseSalary, &contract.Allowances, &contract.Benefits,
-		&contract.ContractTerms, &contract.SignedDate, &contract.IsActive,
-		&contract.CreatedAt, &contract.UpdatedAt, &employeeName, &employeeNumber,
-	)
+	// E-signed terms cannot change; a new contract has to be signed instead
+	if current.Locked {
+		c.JSON(http.StatusConflict, gin.H{"error": "Contract is locked by an electronic signature; renew it to change the terms"})
+		return
+	}
 
+	// The employee must sign the terms they were sent, so no amendments while a link is out
+	var pendingSignatures int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM contract_signature_requests
+		WHERE contract_id = ? AND status = 'pending'
+	`, id).Scan(&pendingSignatures)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated contract"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if pendingSignatures > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Contract has a pending signature request; cancel it before amending"})
 		return
 	}
 
-	contractData := map[string]interface{}{
-		"contract":        contract,
-		"employee_name":   employeeName,
-		"employee_number": employeeNumber,
+	if req.EmployeeID != current.EmployeeID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An amendment cannot move a contract to another employee"})
+		return
 	}
 
-	c.JSON(http.StatusOK, contractData)
+	if effectiveDate.Before(current.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Effective date cannot be before the contract start date"})
+		return
+	}
+	if effectiveDate.Before(current.EffectiveDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Effective date cannot be before the previous amendment takes effect"})
+		return
+	}
+
+	newTerms := contractTermsFromRequest(req.CreateContractRequest)
+	schedule, err := applyWorkSchedule(tx, &newTerms)
+	if err != nil {
+		respondWorkScheduleError(c, err)
+		return
+	}
+
+	newTerms.AllowanceItems, err = resolveContractAllowances(tx, newTerms.AllowanceItems)
+	if err != nil {
+		respondAllowanceError(c, err)
+		return
+	}
+
+	changes := diffContractTerms(current.Terms, newTerms)
+	if len(changes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No changes to amend"})
+		return
+	}
+
+	salaryType, err := employeeSalaryType(tx, current.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	issues, err := validateContractCompliance(tx, newTerms, salaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if hasBlockingIssue(issues) {
+		respondComplianceViolation(c, issues)
+		return
+	}
+
+	termsJSON, _ := json.Marshal(newTerms)
+	changesJSON, _ := json.Marshal(changes)
+	newVersion := current.Version + 1
+
+	var createdBy sql.NullInt64
+	if userID, exists := c.Get("user_id"); exists {
+		createdBy = sql.NullInt64{Int64: int64(userID.(int)), Valid: true}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO contract_versions (contract_id, version, effective_date, terms, changes, reason, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, id, newVersion, effectiveDate, string(termsJSON), string(changesJSON), req.Reason, createdBy)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract amendment"})
+		return
+	}
+
+	// Amendments effective in the future wait for ApplyDueContractAmendments
+	status := "scheduled"
+	if !effectiveDate.After(today) {
+		if err := applyContractVersion(tx, id, newVersion, newTerms); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contract"})
+			return
+		}
+		status = "active"
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	amendment, err := loadContractVersion(id, newVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve contract amendment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "변경계약이 생성되었습니다",
+		"amendment":         amendment,
+		"status":            status,
+		"compliance_issues": issues,
+	})
 }
 
 func DeleteContract(c *gin.Context) {
@@ -327,10 +516,10 @@ func DeleteContract(c *gin.Context) {
 		return
 	}
 
-	// Soft delete by deactivating
-	_, err = database.DB.Exec(`
+	// Soft delete by deactivating - e-signed contracts are locked
+	result, err := database.DB.Exec(`
 		UPDATE employment_contracts SET is_active = 0, updated_at = CURRENT_TIMESTAMP 
-		WHERE id = ?
+		WHERE id = ? AND locked_at IS NULL
 	`, id)
 
 	if err != nil {
@@ -338,6 +527,11 @@ func DeleteContract(c *gin.Context) {
 		return
 	}
 
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Contract not found or locked by an electronic signature"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Contract deleted successfully"})
 }
 
@@ -349,20 +543,18 @@ func CreateContractWithEmployee(c *gin.Context) {
 		return
 	}
 
-	// Start transaction
-	tx, err := database.DB.Begin()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-	defer tx.Rollback()
-
 	// Parse dates
 	hireDate, err := time.Parse("2006-01-02", req.StartDate) // Use contract start date as hire date
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format (YYYY-MM-DD)"})
 		return
 	}
+	if req.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format (YYYY-MM-DD)"})
+			return
+		}
+	}
 
 	var birthDate sql.NullTime
 	if req.BirthDate != "" {
@@ -382,6 +574,54 @@ func CreateContractWithEmployee(c *gin.Context) {
 		req.SalaryType = "monthly"
 	}
 
+	// The contract is checked as CreateContract checks one for an existing employee
+	terms := models.ContractTerms{
+		ContractType:   req.ContractType,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Workplace:      req.Workplace,
+		JobDescription: req.JobDescription,
+		WorkingHours:   req.WorkingHours,
+		WorkDays:       req.WorkDays,
+		BreakTime:      req.BreakTime,
+		WeeklyHoliday:  req.WeeklyHoliday,
+		WorkScheduleID: req.WorkScheduleID,
+		BaseSalary:     req.BaseSalary,
+		Allowances:     req.Allowances,
+		AllowanceItems: req.AllowanceItems,
+		Benefits:       req.Benefits,
+		ContractTerms:  req.ContractTerms,
+	}
+	schedule, err := applyWorkSchedule(database.DB, &terms)
+	if err != nil {
+		respondWorkScheduleError(c, err)
+		return
+	}
+
+	terms.AllowanceItems, err = resolveContractAllowances(database.DB, terms.AllowanceItems)
+	if err != nil {
+		respondAllowanceError(c, err)
+		return
+	}
+
+	issues, err := validateContractCompliance(database.DB, terms, req.SalaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if hasBlockingIssue(issues) {
+		respondComplianceViolation(c, issues)
+		return
+	}
+
+	// Start transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
 	// 1. Create Employee
 	employeeResult, err := tx.Exec(`
 		INSERT INTO employees (employee_number, name, phone, email, address, 
@@ -416,42 +656,21 @@ func CreateContractWithEmployee(c *gin.Context) {
 	}
 
 	// 3. Create Contract
-	contractStartDate, err := time.Parse("2006-01-02", req.StartDate)
+	fixedTerm, err := fixedTermIssue(tx, int(employeeID), terms)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract start date format"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check fixed-term period"})
 		return
 	}
-
-	var contractEndDate sql.NullTime
-	if req.EndDate != "" {
-		ed, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract end date format"})
-			return
-		}
-		contractEndDate = sql.NullTime{Time: ed, Valid: true}
+	if fixedTerm != nil {
+		issues = append(issues, *fixedTerm)
 	}
 
-	contractResult, err := tx.Exec(`
-		INSERT INTO employment_contracts (employee_id, contract_type, start_date, end_date, 
-		                                 workplace, job_description, working_hours, work_days, 
-		                                 base_salary, allowances, benefits, contract_terms)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, employeeID, req.ContractType, contractStartDate, contractEndDate, req.Workplace,
-		req.JobDescription, req.WorkingHours, req.WorkDays, req.BaseSalary,
-		req.Allowances, req.Benefits, req.ContractTerms)
-
+	contractID, err := insertContract(tx, int(employeeID), terms)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract"})
 		return
 	}
 
-	contractID, err := contractResult.LastInsertId()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get contract ID"})
-		return
-	}
-
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
@@ -477,5 +696,6 @@ func CreateContractWithEmployee(c *gin.Context) {
 			"end_date": req.EndDate,
 			"base_salary": req.BaseSalary,
 		},
+		"compliance_issues": issues,
 	})
 }
\ No newline at end of file
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComplianceIssue is one finding of the Labor Standards Act (근로기준법) check of a contract.
+// Issues with severity "error" block saving the contract, "warning" issues are returned with it.
+type ComplianceIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+}
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// ValidateContractRequest checks contract terms without saving them
+type ValidateContractRequest struct {
+	models.ContractTerms
+	EmployeeID int    `json:"employee_id"`
+	SalaryType string `json:"salary_type"`
+}
+
+var clockRangePattern = regexp.MustCompile(`(\d{1,2}):(\d{2})\s*[-~]\s*(\d{1,2}):(\d{2})`)
+
+// parseClockRanges returns the minutes covered by each "HH:MM-HH:MM" range in s.
+// A range ending before it starts runs past midnight.
+func parseClockRanges(s string) []int {
+	var spans []int
+	for _, m := range clockRangePattern.FindAllStringSubmatch(s, -1) {
+		sh, _ := strconv.Atoi(m[1])
+		sm, _ := strconv.Atoi(m[2])
+		eh, _ := strconv.Atoi(m[3])
+		em, _ := strconv.Atoi(m[4])
+		if sh > 24 || eh > 24 || sm > 59 || em > 59 {
+			continue
+		}
+
+		start, end := sh*60+sm, eh*60+em
+		if end <= start {
+			end += 24 * 60
+		}
+		spans = append(spans, end-start)
+	}
+	return spans
+}
+
+var weekdayNames = []rune("월화수목금토일")
+
+var workDaysPerWeekPattern = regexp.MustCompile(`주\s*(\d)\s*일`)
+
+// parseWorkDays counts the working days per week in "월-금", "월~토", "월,수,금", "월화수" or "주5일"
+func parseWorkDays(s string) int {
+	if m := workDaysPerWeekPattern.FindStringSubmatch(s); m != nil {
+		days, _ := strconv.Atoi(m[1])
+		return days
+	}
+
+	index := func(r rune) int {
+		for i, name := range weekdayNames {
+			if name == r {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// "월요일" would otherwise also count 일(Sunday)
+	runes := []rune(strings.ReplaceAll(strings.ReplaceAll(s, "요일", ""), " ", ""))
+	days := make(map[int]bool)
+	for i := 0; i < len(runes); i++ {
+		from := index(runes[i])
+		if from < 0 {
+			continue
+		}
+		days[from] = true
+
+		// "월-금" / "월~금" is a range
+		if i+2 < len(runes) && (runes[i+1] == '-' || runes[i+1] == '~') {
+			if to := index(runes[i+2]); to >= from {
+				for d := from; d <= to; d++ {
+					days[d] = true
+				}
+				i += 2
+			}
+		}
+	}
+	return len(days)
+}
+
+// requiredBreakMinutes is the minimum break for a working day (제54조):
+// 30 minutes for 4 hours of work, 1 hour for 8 hours
+func requiredBreakMinutes(workMinutes int) int {
+	switch {
+	case workMinutes >= 8*60:
+		return 60
+	case workMinutes >= 4*60:
+		return 30
+	}
+	return 0
+}
+
+// validateContractCompliance checks contract terms against the mandatory items of
+// 근로기준법 제17조, the working hour limits and the minimum wage for the contract year.
+// Working hours come from the schedule when the contract references one. The minimum wage is read
+// through db, so a caller inside a transaction checks against the same snapshot it saves in.
+func validateContractCompliance(db dbtx, terms models.ContractTerms, salaryType string, schedule *models.WorkSchedule) ([]ComplianceIssue, error) {
+	issues := []ComplianceIssue{}
+	add := func(severity, code, field, message string) {
+		issues = append(issues, ComplianceIssue{Code: code, Severity: severity, Field: field, Message: message})
+	}
+
+	// Mandatory items (제17조)
+	if strings.TrimSpace(terms.Workplace) == "" {
+		add(severityError, "MISSING_WORKPLACE", "workplace", "취업 장소를 명시해야 합니다")
+	}
+	if strings.TrimSpace(terms.JobDescription) == "" {
+		add(severityError, "MISSING_JOB_DESCRIPTION", "job_description", "종사하여야 할 업무를 명시해야 합니다")
+	}
+	if strings.TrimSpace(terms.WeeklyHoliday) == "" {
+		add(severityError, "MISSING_WEEKLY_HOLIDAY", "weekly_holiday", "주휴일을 명시해야 합니다")
+	}
+	if !strings.Contains(terms.ContractTerms, "연차") && !strings.Contains(terms.Benefits, "연차") {
+		add(severityWarning, "MISSING_ANNUAL_LEAVE", "contract_terms", "연차유급휴가에 관한 사항이 명시되어 있지 않습니다")
+	}
+
+	startDate, startErr := time.Parse("2006-01-02", terms.StartDate)
+	if terms.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", terms.EndDate)
+		if err == nil && startErr == nil && endDate.Before(startDate) {
+			add(severityError, "END_BEFORE_START", "end_date", "계약 종료일이 시작일보다 빠릅니다")
+		}
+	}
+
+	// Working hours and breaks, per working day
+	type workDay struct {
+		workMinutes  int
+		breakMinutes int
+		breakGiven   bool
+	}
+	var days []workDay
+
+	if schedule != nil {
+		for _, day := range schedule.Days {
+			span, breaks := scheduleDayMinutes(day)
+			days = append(days, workDay{span - breaks, breaks, true})
+		}
+	} else {
+		workSpans := parseClockRanges(terms.WorkingHours)
+		if len(workSpans) == 0 {
+			add(severityWarning, "UNPARSEABLE_WORKING_HOURS", "working_hours", "근로시간을 해석할 수 없어 근로시간 검증을 건너뜁니다 (예: 09:00-18:00)")
+			return issues, nil
+		}
+
+		breakMinutes := 0
+		for _, span := range parseClockRanges(terms.BreakTime) {
+			breakMinutes += span
+		}
+		if strings.TrimSpace(terms.BreakTime) == "" {
+			add(severityWarning, "MISSING_BREAK_TIME", "break_time", "휴게시간이 명시되어 있지 않습니다")
+		}
+
+		workDays := parseWorkDays(terms.WorkDays)
+		if workDays == 0 {
+			add(severityWarning, "UNPARSEABLE_WORK_DAYS", "work_days", "근무요일을 해석할 수 없어 주 근로시간 검증을 건너뜁니다 (예: 월-금)")
+			return issues, nil
+		}
+		for i := 0; i < workDays; i++ {
+			days = append(days, workDay{workSpans[0] - breakMinutes, breakMinutes, terms.BreakTime != ""})
+		}
+	}
+
+	if len(days) == 0 {
+		add(severityError, "NO_WORK_DAYS", "work_days", "근무일이 없습니다")
+		return issues, nil
+	}
+	if len(days) == 7 {
+		add(severityError, "NO_WEEKLY_REST_DAY", "work_days", "1주에 1일 이상의 휴일이 있어야 합니다")
+	}
+
+	weeklyMinutes, longestDay := 0, 0
+	for _, day := range days {
+		if day.workMinutes <= 0 {
+			add(severityError, "INVALID_BREAK_TIME", "break_time", "휴게시간이 근로시간보다 깁니다")
+			return issues, nil
+		}
+		if day.breakGiven && day.breakMinutes < requiredBreakMinutes(day.workMinutes) {
+			add(severityError, "INSUFFICIENT_BREAK", "break_time",
+				fmt.Sprintf("1일 %.1f시간 근로에는 %d분 이상의 휴게시간이 필요합니다", float64(day.workMinutes)/60, requiredBreakMinutes(day.workMinutes)))
+			break
+		}
+	}
+	for _, day := range days {
+		weeklyMinutes += day.workMinutes
+		if day.workMinutes > longestDay {
+			longestDay = day.workMinutes
+		}
+	}
+
+	weeklyHours := float64(weeklyMinutes) / 60
+	dailyHours := weeklyHours / float64(len(days))
+
+	if longestDay > 8*60 {
+		add(severityWarning, "DAILY_HOURS_OVER_8", "working_hours",
+			fmt.Sprintf("1일 소정근로시간 %.1f시간이 법정근로시간 8시간을 초과합니다 (연장근로 합의 필요)", float64(longestDay)/60))
+	}
+	if weeklyHours > 52 {
+		add(severityError, "WEEKLY_HOURS_OVER_52", "working_hours",
+			fmt.Sprintf("주 %.1f시간은 연장근로 포함 주 52시간 한도를 초과합니다", weeklyHours))
+	} else if weeklyHours > 40 {
+		add(severityWarning, "WEEKLY_HOURS_OVER_40", "working_hours",
+			fmt.Sprintf("주 %.1f시간이 법정근로시간 40시간을 초과합니다 (연장근로 합의 필요)", weeklyHours))
+	}
+
+	// Minimum wage for the contract year
+	if startErr != nil || terms.BaseSalary <= 0 {
+		return issues, nil
+	}
+	minimumWage, err := loadMinimumWage(db, startDate.Year())
+	if err != nil {
+		return nil, err
+	}
+	if minimumWage == 0 {
+		add(severityError, "MINIMUM_WAGE_NOT_SET", "start_date",
+			fmt.Sprintf("%d년 최저임금이 등록되어 있지 않아 최저임금 준수 여부를 확인할 수 없습니다", startDate.Year()))
+		return issues, nil
+	}
+	minWage := minimumWage.Float64()
+
+	var hourlyWage float64
+	switch salaryType {
+	case "hourly":
+		hourlyWage = terms.BaseSalary
+	case "daily":
+		hourlyWage = terms.BaseSalary / dailyHours
+	default:
+		// Monthly pay covers the contractual hours up to 40 a week plus the paid weekly holiday
+		hourlyWage = terms.BaseSalary / monthlyScheduledHours(weeklyHours)
+	}
+
+	if hourlyWage < minWage {
+		add(severityError, "BELOW_MINIMUM_WAGE", "base_salary",
+			fmt.Sprintf("시간당 임금 %s이 %d년 최저임금 %s에 미달합니다", formatWon(hourlyWage), startDate.Year(), formatWon(minWage)))
+	}
+
+	return issues, nil
+}
+
+func hasBlockingIssue(issues []ComplianceIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == severityError {
+			return true
+		}
+	}
+	return false
+}
+
+// employeeSalaryType returns how the employee is paid (monthly, hourly or daily)
+func employeeSalaryType(db dbtx, employeeID int) (string, error) {
+	var salaryType sql.NullString
+	err := db.QueryRow("SELECT salary_type FROM employees WHERE id = ?", employeeID).Scan(&salaryType)
+	if err != nil {
+		return "", err
+	}
+	if !salaryType.Valid || salaryType.String == "" {
+		return "monthly", nil
+	}
+	return salaryType.String, nil
+}
+
+func respondComplianceViolation(c *gin.Context, issues []ComplianceIssue) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":  "근로기준법 위반 항목이 있어 계약을 저장할 수 없습니다",
+		"issues": issues,
+	})
+}
+
+// ValidateContract checks contract terms against the Labor Standards Act without saving them
+func ValidateContract(c *gin.Context) {
+	var req ValidateContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.SalaryType == "" && req.EmployeeID != 0 {
+		salaryType, err := employeeSalaryType(database.DB, req.EmployeeID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			}
+			return
+		}
+		req.SalaryType = salaryType
+	}
+
+	schedule, err := applyWorkSchedule(database.DB, &req.ContractTerms)
+	if err != nil {
+		respondWorkScheduleError(c, err)
+		return
+	}
+
+	issues, err := validateContractCompliance(database.DB, req.ContractTerms, req.SalaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"valid":  !hasBlockingIssue(issues),
+		"issues": issues,
+	})
+}
+
+// GetContractCompliance checks the current terms of a saved contract
+func GetContractCompliance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	contractData, err := scanContractWithEmployee(database.DB.QueryRow(contractSelectQuery+`
+		WHERE c.id = ?
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	contract := contractData["contract"].(models.EmploymentContract)
+
+	salaryType, err := employeeSalaryType(database.DB, contract.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Saved contracts keep the working time text even if their schedule was retired since
+	terms := contractTermsFromRow(contract)
+	var schedule *models.WorkSchedule
+	if terms.WorkScheduleID != 0 {
+		schedule, err = loadWorkSchedule(database.DB, terms.WorkScheduleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work schedule"})
+			return
+		}
+	}
+
+	issues, err := validateContractCompliance(database.DB, terms, salaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"contract_id": id,
+		"valid":       !hasBlockingIssue(issues),
+		"issues":      issues,
+	})
+}
@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"database/sql"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 기간제법 제4조: a fixed-term worker employed for more than two years in total
+// is deemed to be on a contract without a fixed term
+const (
+	fixedTermLimitYears = 2
+	// Renewals with a break of up to this many days still count as one continuous employment
+	fixedTermGapDays = 30
+	// The limit is reported as approaching this many days before it is reached
+	fixedTermWarningDays = 90
+	// 제4조 제1항 제4호: contracts with workers aged 55 or older are exempt
+	fixedTermExemptAge = 55
+)
+
+// FixedTermStatus is the position of an employee against the two-year limit
+type FixedTermStatus struct {
+	EmployeeID     int    `json:"employee_id"`
+	EmployeeName   string `json:"employee_name"`
+	EmployeeNumber string `json:"employee_number"`
+	// none, permanent, ended, ok, approaching, exceeded or exempt
+	Status         string `json:"status"`
+	ContractIDs    []int  `json:"contract_ids"`
+	ChainStart     string `json:"chain_start,omitempty"`
+	ChainEnd       string `json:"chain_end,omitempty"`
+	LimitDate      string `json:"limit_date,omitempty"`
+	DaysUntilLimit int    `json:"days_until_limit"`
+	ExemptReason   string `json:"exempt_reason,omitempty"`
+	Message        string `json:"message"`
+}
+
+type ConvertToPermanentRequest struct {
+	StartDate string `json:"start_date"`
+}
+
+// fixedTermContract is the part of a contract row needed to chain fixed-term contracts
+type fixedTermContract struct {
+	ID        int
+	StartDate time.Time
+	EndDate   sql.NullTime
+}
+
+func loadFixedTermContracts(db dbtx, employeeID int) ([]fixedTermContract, error) {
+	rows, err := db.Query(`
+		SELECT id, start_date, end_date FROM employment_contracts
+		WHERE employee_id = ?
+		ORDER BY start_date, id
+	`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contracts []fixedTermContract
+	for rows.Next() {
+		var contract fixedTermContract
+		if err := rows.Scan(&contract.ID, &contract.StartDate, &contract.EndDate); err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, contract)
+	}
+	return contracts, rows.Err()
+}
+
+// computeFixedTermStatus chains the consecutive fixed-term contracts ending with the
+// employee's latest contract and compares the chain with the two-year limit as of asOf.
+// Contracts must be ordered by start date.
+func computeFixedTermStatus(contracts []fixedTermContract, birthDate sql.NullTime, asOf time.Time) FixedTermStatus {
+	status := FixedTermStatus{ContractIDs: []int{}}
+
+	if len(contracts) == 0 {
+		status.Status = "none"
+		status.Message = "근로계약이 없습니다"
+		return status
+	}
+
+	latest := contracts[len(contracts)-1]
+	if !latest.EndDate.Valid {
+		status.Status = "permanent"
+		status.Message = "기간의 정함이 없는 근로계약입니다"
+		return status
+	}
+
+	// Walk back while each contract follows the previous fixed-term contract closely enough
+	first := len(contracts) - 1
+	chainEnd := latest.EndDate.Time
+	for first > 0 {
+		prev := contracts[first-1]
+		if !prev.EndDate.Valid {
+			break
+		}
+		gap := contracts[first].StartDate.Sub(prev.EndDate.Time).Hours() / 24
+		if gap > fixedTermGapDays+1 {
+			break
+		}
+		first--
+	}
+	for _, contract := range contracts[first:] {
+		status.ContractIDs = append(status.ContractIDs, contract.ID)
+		if contract.EndDate.Time.After(chainEnd) {
+			chainEnd = contract.EndDate.Time
+		}
+	}
+
+	chainStart := contracts[first].StartDate
+	limitDate := chainStart.AddDate(fixedTermLimitYears, 0, 0)
+	status.ChainStart = chainStart.Format("2006-01-02")
+	status.ChainEnd = chainEnd.Format("2006-01-02")
+	status.LimitDate = limitDate.Format("2006-01-02")
+	status.DaysUntilLimit = int(limitDate.Sub(asOf).Hours() / 24)
+
+	if birthDate.Valid && ageOn(birthDate.Time, latest.StartDate) >= fixedTermExemptAge {
+		status.Status = "exempt"
+		status.ExemptReason = "만 55세 이상 고령자와의 근로계약 (기간제법 제4조 제1항 제4호)"
+		status.Message = "2년 사용기간 제한의 예외에 해당합니다"
+		return status
+	}
+
+	switch {
+	case !chainEnd.Before(limitDate) || !asOf.Before(limitDate):
+		status.Status = "exceeded"
+		status.Message = "기간제 근로 2년을 초과하여 기간의 정함이 없는 근로자로 간주됩니다. 무기계약 전환이 필요합니다"
+	case asOf.After(chainEnd.AddDate(0, 0, fixedTermGapDays)):
+		status.Status = "ended"
+		status.Message = "기간제 근로계약이 종료되었습니다"
+	case status.DaysUntilLimit <= fixedTermWarningDays:
+		status.Status = "approaching"
+		status.Message = "2년 사용기간 만료가 다가옵니다. 계약 갱신 시 무기계약으로 간주됩니다"
+	default:
+		status.Status = "ok"
+		status.Message = "2년 사용기간 이내입니다"
+	}
+
+	return status
+}
+
+// ageOn returns the full age (만 나이) on the given date
+func ageOn(birthDate, date time.Time) int {
+	age := date.Year() - birthDate.Year()
+	if date.Month() < birthDate.Month() || (date.Month() == birthDate.Month() && date.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+func employeeFixedTermStatus(db dbtx, employeeID int, asOf time.Time) (*FixedTermStatus, error) {
+	var name, number string
+	var birthDate sql.NullTime
+	err := db.QueryRow(`
+		SELECT name, employee_number, birth_date FROM employees WHERE id = ?
+	`, employeeID).Scan(&name, &number, &birthDate)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := loadFixedTermContracts(db, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := computeFixedTermStatus(contracts, birthDate, asOf)
+	status.EmployeeID = employeeID
+	status.EmployeeName = name
+	status.EmployeeNumber = number
+	return &status, nil
+}
+
+// fixedTermIssue warns when saving the contract would take the fixed-term chain past two years
+func fixedTermIssue(db dbtx, employeeID int, terms models.ContractTerms) (*ComplianceIssue, error) {
+	if terms.EndDate == "" {
+		return nil, nil
+	}
+	startDate, err := time.Parse("2006-01-02", terms.StartDate)
+	if err != nil {
+		return nil, nil
+	}
+	endDate, err := time.Parse("2006-01-02", terms.EndDate)
+	if err != nil {
+		return nil, nil
+	}
+
+	var birthDate sql.NullTime
+	err = db.QueryRow("SELECT birth_date FROM employees WHERE id = ?", employeeID).Scan(&birthDate)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := loadFixedTermContracts(db, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new contract replaces any contract starting on or after it
+	var chain []fixedTermContract
+	for _, contract := range contracts {
+		if contract.StartDate.Before(startDate) {
+			chain = append(chain, contract)
+		}
+	}
+	chain = append(chain, fixedTermContract{
+		StartDate: startDate,
+		EndDate:   sql.NullTime{Time: endDate, Valid: true},
+	})
+
+	status := computeFixedTermStatus(chain, birthDate, startDate)
+	if status.Status != "exceeded" {
+		return nil, nil
+	}
+	return &ComplianceIssue{
+		Code:     "FIXED_TERM_OVER_2_YEARS",
+		Severity: severityWarning,
+		Field:    "end_date",
+		Message:  "연속된 기간제 근로가 2년(" + status.ChainStart + " ~ " + status.ChainEnd + ")을 초과하여 기간의 정함이 없는 근로계약으로 간주됩니다",
+	}, nil
+}
+
+// GetFixedTermStatuses lists employees on fixed-term contracts and how close they are
+// to the two-year limit. ?status=approaching filters by status.
+func GetFixedTermStatuses(c *gin.Context) {
+	rows, err := database.DB.Query(`
+		SELECT DISTINCT e.id FROM employees e
+		JOIN employment_contracts c ON c.employee_id = e.id
+		WHERE e.status = 'active' AND c.is_active = 1 AND c.end_date IS NOT NULL
+		ORDER BY e.id
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var employeeIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan employee"})
+			return
+		}
+		employeeIDs = append(employeeIDs, id)
+	}
+	rows.Close()
+
+	filter := c.Query("status")
+	asOf := time.Now().Truncate(24 * time.Hour)
+
+	statuses := []FixedTermStatus{}
+	for _, id := range employeeIDs {
+		status, err := employeeFixedTermStatus(database.DB, id, asOf)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute fixed-term status"})
+			return
+		}
+		if filter != "" && status.Status != filter {
+			continue
+		}
+		statuses = append(statuses, *status)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statuses": statuses})
+}
+
+// GetEmployeeFixedTermStatus returns one employee's position against the two-year limit
+func GetEmployeeFixedTermStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	status, err := employeeFixedTermStatus(database.DB, id, time.Now().Truncate(24*time.Hour))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute fixed-term status"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ConvertToPermanent replaces the employee's fixed-term contract with a contract
+// without a fixed term (무기계약 전환) on the same terms and makes the employee regular.
+func ConvertToPermanent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req ConvertToPermanentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	startDate := time.Now().Truncate(24 * time.Hour)
+	if req.StartDate != "" {
+		startDate, err = time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format (YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	contractData, err := scanContractWithEmployee(tx.QueryRow(contractSelectQuery+`
+		WHERE c.employee_id = ? AND c.is_active = 1
+		ORDER BY c.start_date DESC LIMIT 1
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee has no active contract"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	current := contractData["contract"].(models.EmploymentContract)
+
+	if !current.EndDate.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Employee is already on a contract without a fixed term"})
+		return
+	}
+	if startDate.Before(current.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start date cannot be before the current contract start date"})
+		return
+	}
+
+	terms := contractTermsFromRow(current)
+	terms.ContractType = "permanent"
+	terms.StartDate = startDate.Format("2006-01-02")
+	terms.EndDate = ""
+	terms.AllowanceItems, err = loadContractAllowances(tx, current.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load allowance items"})
+		return
+	}
+
+	schedule, err := applyWorkSchedule(tx, &terms)
+	if err != nil {
+		respondWorkScheduleError(c, err)
+		return
+	}
+
+	salaryType, err := employeeSalaryType(tx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	issues, err := validateContractCompliance(tx, terms, salaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if hasBlockingIssue(issues) {
+		respondComplianceViolation(c, issues)
+		return
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employment_contracts SET is_active = 0, updated_at = CURRENT_TIMESTAMP
+		WHERE employee_id = ? AND is_active = 1
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate existing contracts"})
+		return
+	}
+
+	contractID, err := insertContract(tx, id, terms)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employees SET employment_type = 'regular', updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update employment type"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":              "무기계약으로 전환되었습니다",
+		"contract_id":          contractID,
+		"previous_contract_id": current.ID,
+		"compliance_issues":    issues,
+	})
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contractActivationInterval is how often renewed contracts starting today are put into effect
+const contractActivationInterval = time.Hour
+
+// renewalPeriod returns the default start and end date of a renewal: the day after the
+// current contract ends, for the same length. Contracts without a fixed term renew from today.
+func renewalPeriod(current models.EmploymentContract, today time.Time) (time.Time, sql.NullTime) {
+	if !current.EndDate.Valid {
+		return today, sql.NullTime{}
+	}
+
+	start := current.EndDate.Time.AddDate(0, 0, 1)
+	return start, renewalEndDate(current, start)
+}
+
+// renewalEndDate keeps the length of the current contract, counted in whole months when it has one
+func renewalEndDate(current models.EmploymentContract, start time.Time) sql.NullTime {
+	if !current.EndDate.Valid {
+		return sql.NullTime{}
+	}
+
+	oldStart := current.StartDate
+	dayAfterEnd := current.EndDate.Time.AddDate(0, 0, 1)
+	if dayAfterEnd.Day() == oldStart.Day() {
+		months := (dayAfterEnd.Year()-oldStart.Year())*12 + int(dayAfterEnd.Month()-oldStart.Month())
+		return sql.NullTime{Time: start.AddDate(0, months, -1), Valid: true}
+	}
+
+	days := int(current.EndDate.Time.Sub(oldStart).Hours() / 24)
+	return sql.NullTime{Time: start.AddDate(0, 0, days), Valid: true}
+}
+
+// activateContract puts a contract into effect: earlier contracts of the employee end
+// and the employee's base salary follows the contract
+func activateContract(tx dbtx, contractID, employeeID int, baseSalary float64) error {
+	_, err := tx.Exec(`
+		UPDATE employment_contracts SET is_active = 0, updated_at = CURRENT_TIMESTAMP
+		WHERE employee_id = ? AND is_active = 1 AND id <> ?
+	`, employeeID, contractID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employment_contracts SET is_active = 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, contractID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employees SET base_salary = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, baseSalary, employeeID)
+	return err
+}
+
+// ActivateDueContracts puts renewed contracts into effect once their start date is reached.
+// A renewal is pending while its predecessor is still the active contract.
+func ActivateDueContracts(asOf time.Time) (int, error) {
+	rows, err := database.DB.Query(`
+		SELECT c.id, c.employee_id, c.start_date, c.base_salary
+		FROM employment_contracts c
+		JOIN employment_contracts p ON c.previous_contract_id = p.id
+		WHERE c.is_active = 0 AND p.is_active = 1 AND p.next_contract_id = c.id
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	type pendingContract struct {
+		id, employeeID int
+		startDate      time.Time
+		baseSalary     float64
+	}
+	var due []pendingContract
+	for rows.Next() {
+		var p pendingContract
+		if err := rows.Scan(&p.id, &p.employeeID, &p.startDate, &p.baseSalary); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if !p.startDate.After(asOf) {
+			due = append(due, p)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, p := range due {
+		tx, err := database.DB.Begin()
+		if err != nil {
+			return 0, err
+		}
+		if err := activateContract(tx, p.id, p.employeeID, p.baseSalary); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(due), nil
+}
+
+// StartContractActivation runs ActivateDueContracts and ApplyDueContractAmendments now
+// and then every contractActivationInterval
+func StartContractActivation() {
+	activate := func() {
+		count, err := ActivateDueContracts(time.Now())
+		if err != nil {
+			log.Printf("Failed to activate renewed contracts: %v", err)
+		} else if count > 0 {
+			log.Printf("Activated %d renewed contract(s)", count)
+		}
+
+		count, err = ApplyDueContractAmendments(time.Now())
+		if err != nil {
+			log.Printf("Failed to apply contract amendments: %v", err)
+		} else if count > 0 {
+			log.Printf("Applied %d contract amendment(s)", count)
+		}
+	}
+
+	activate()
+	go func() {
+		ticker := time.NewTicker(contractActivationInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			activate()
+		}
+	}()
+}
+
+// RenewContract creates the successor of a contract in one transaction. The terms are carried
+// forward from the current contract; any contract field in the request body overrides them.
+// The successor takes effect on its start date, which defaults to the day after the current
+// contract ends.
+func RenewContract(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+	overrides := map[string]json.RawMessage{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &overrides); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	contractData, err := scanContractWithEmployee(tx.QueryRow(contractSelectQuery+`
+		WHERE c.id = ?
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	current := contractData["contract"].(models.EmploymentContract)
+
+	if !current.IsActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only the active contract can be renewed"})
+		return
+	}
+	if current.NextContractID.Valid {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":            "Contract has already been renewed",
+			"next_contract_id": current.NextContractID.Int64,
+		})
+		return
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	startDate, endDate := renewalPeriod(current, today)
+
+	terms := contractTermsFromRow(current)
+	terms.StartDate = startDate.Format("2006-01-02")
+	terms.EndDate = ""
+	if endDate.Valid {
+		terms.EndDate = endDate.Time.Format("2006-01-02")
+	}
+	terms.AllowanceItems, err = loadContractAllowances(tx, current.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load allowance items"})
+		return
+	}
+
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &terms); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	startDate, err = time.Parse("2006-01-02", terms.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format (YYYY-MM-DD)"})
+		return
+	}
+	// A new start date without an end date keeps the length of the current contract
+	if _, ok := overrides["start_date"]; ok {
+		if _, ok := overrides["end_date"]; !ok {
+			terms.EndDate = ""
+			if end := renewalEndDate(current, startDate); end.Valid {
+				terms.EndDate = end.Time.Format("2006-01-02")
+			}
+		}
+	}
+	if terms.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", terms.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format (YYYY-MM-DD)"})
+			return
+		}
+		if endDate.Before(startDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "End date cannot be before the start date"})
+			return
+		}
+	}
+	if !startDate.After(current.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A renewal must start after the current contract starts"})
+		return
+	}
+
+	schedule, err := applyWorkSchedule(tx, &terms)
+	if err != nil {
+		respondWorkScheduleError(c, err)
+		return
+	}
+
+	terms.AllowanceItems, err = resolveContractAllowances(tx, terms.AllowanceItems)
+	if err != nil {
+		respondAllowanceError(c, err)
+		return
+	}
+
+	salaryType, err := employeeSalaryType(tx, current.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	issues, err := validateContractCompliance(tx, terms, salaryType, schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if hasBlockingIssue(issues) {
+		respondComplianceViolation(c, issues)
+		return
+	}
+
+	fixedTerm, err := fixedTermIssue(tx, current.EmployeeID, terms)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check fixed-term period"})
+		return
+	}
+	if fixedTerm != nil {
+		issues = append(issues, *fixedTerm)
+	}
+
+	successorID, err := insertContract(tx, current.EmployeeID, terms)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employment_contracts SET previous_contract_id = ?, is_active = 0 WHERE id = ?
+	`, current.ID, successorID)
+	if err == nil {
+		_, err = tx.Exec(`
+			UPDATE employment_contracts SET next_contract_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+		`, successorID, current.ID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link renewed contract"})
+		return
+	}
+
+	status := "scheduled"
+	if !startDate.After(today) {
+		if err := activateContract(tx, int(successorID), current.EmployeeID, terms.BaseSalary); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to activate renewed contract"})
+			return
+		}
+		status = "active"
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	successor, err := scanContractWithEmployee(database.DB.QueryRow(contractSelectQuery+`
+		WHERE c.id = ?
+	`, successorID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve renewed contract"})
+		return
+	}
+	successor["allowance_items"] = terms.AllowanceItems
+	successor["previous_contract_id"] = current.ID
+	successor["status"] = status
+	successor["compliance_issues"] = issues
+
+	c.JSON(http.StatusCreated, successor)
+}
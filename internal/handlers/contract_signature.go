@@ -0,0 +1,618 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// defaultSignatureLinkHours is how long a signing link stays valid unless the request says otherwise
+const defaultSignatureLinkHours = 72
+
+// maxSignatureImageBytes limits the size of a drawn signature image
+const maxSignatureImageBytes = 512 * 1024
+
+type CreateSignatureRequestBody struct {
+	Version        int `json:"version"`
+	ExpiresInHours int `json:"expires_in_hours"`
+}
+
+type SubmitSignatureBody struct {
+	SignerName     string `json:"signer_name" binding:"required"`
+	SignatureType  string `json:"signature_type" binding:"required,oneof=drawn typed"`
+	SignatureImage string `json:"signature_image"` // data:image/png;base64,... for drawn signatures
+	DocumentHash   string `json:"document_hash" binding:"required"`
+	Agree          bool   `json:"agree"`
+}
+
+// signingDocument is the rendered, unsigned contract version the employee is asked to sign
+type signingDocument struct {
+	EmployeeID int
+	Contract   *contractDocument
+	Version    *models.ContractVersion
+	PDF        []byte
+	Hash       string
+}
+
+func hashSignatureToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSignatureToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func loadSigningDocument(contractID, versionNumber int) (*signingDocument, error) {
+	db := database.GetDB()
+
+	doc := &signingDocument{}
+	err := db.QueryRow("SELECT employee_id FROM employment_contracts WHERE id = ?", contractID).Scan(&doc.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Version, err = loadContractVersion(contractID, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Contract, err = loadContractDocument(db, doc.EmployeeID, doc.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Render as the unsigned document the employee reviews
+	pdf, err := renderContractPDF(doc.Contract.unsigned())
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	doc.PDF = buf.Bytes()
+	sum := sha256.Sum256(doc.PDF)
+	doc.Hash = hex.EncodeToString(sum[:])
+
+	return doc, nil
+}
+
+func scanSignatureRequest(row rowScanner) (*models.ContractSignatureRequest, error) {
+	var req models.ContractSignatureRequest
+	err := row.Scan(
+		&req.ID, &req.ContractID, &req.Version, &req.Status, &req.ExpiresAt,
+		&req.SignerName, &req.SignatureType, &req.SignerIP, &req.SignerUserAgent,
+		&req.DocumentHash, &req.SignedAt, &req.SignedFilePath, &req.CreatedBy,
+		&req.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+const signatureRequestColumns = `
+	id, contract_id, version, status, expires_at, signer_name, signature_type,
+	signer_ip, signer_user_agent, document_hash, signed_at, signed_file_path,
+	created_by, created_at
+`
+
+// CreateSignatureRequest issues a one-time signing link for a contract version
+func CreateSignatureRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	var req CreateSignatureRequestBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.ExpiresInHours <= 0 {
+		req.ExpiresInHours = defaultSignatureLinkHours
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	head, err := ensureInitialContractVersion(tx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contract versions"})
+		}
+		return
+	}
+
+	if req.Version == 0 {
+		req.Version = head.Version
+	}
+
+	var signedDate sql.NullTime
+	err = tx.QueryRow(`
+		SELECT signed_date FROM contract_versions WHERE contract_id = ? AND version = ?
+	`, id, req.Version).Scan(&signedDate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract version not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if signedDate.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Contract version is already signed"})
+		return
+	}
+
+	// Only one live link per contract - earlier pending links stop working
+	_, err = tx.Exec(`
+		UPDATE contract_signature_requests SET status = 'cancelled'
+		WHERE contract_id = ? AND status = 'pending'
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel previous signature requests"})
+		return
+	}
+
+	token, err := newSignatureToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate signing token"})
+		return
+	}
+
+	expiresAt := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+	result, err := tx.Exec(`
+		INSERT INTO contract_signature_requests (contract_id, version, token_hash, expires_at, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, id, req.Version, hashSignatureToken(token), expiresAt, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create signature request"})
+		return
+	}
+
+	requestID, _ := result.LastInsertId()
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	// The token itself is only returned here; the database keeps its hash
+	c.JSON(http.StatusCreated, gin.H{
+		"message":              "전자서명 요청이 생성되었습니다",
+		"signature_request_id": requestID,
+		"version":              req.Version,
+		"token":                token,
+		"sign_url":             "/api/sign/" + token,
+		"expires_at":           expiresAt,
+	})
+}
+
+// GetSignatureRequests lists the signing links issued for a contract
+func GetSignatureRequests(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT `+signatureRequestColumns+`
+		FROM contract_signature_requests
+		WHERE contract_id = ?
+		ORDER BY created_at DESC
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	var requests []models.ContractSignatureRequest
+	for rows.Next() {
+		req, err := scanSignatureRequest(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan signature request"})
+			return
+		}
+		requests = append(requests, *req)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signature_requests": requests})
+}
+
+// CancelSignatureRequest invalidates a pending signing link
+func CancelSignatureRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	requestID, err := strconv.Atoi(c.Param("requestId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signature request ID"})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE contract_signature_requests SET status = 'cancelled'
+		WHERE id = ? AND contract_id = ? AND status = 'pending'
+	`, requestID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel signature request"})
+		return
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Signature request not found or not pending"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Signature request cancelled successfully"})
+}
+
+// loadPendingSignatureRequest resolves a signing token, writing the error response if it cannot be used
+func loadPendingSignatureRequest(c *gin.Context) (*models.ContractSignatureRequest, bool) {
+	req, err := scanSignatureRequest(database.DB.QueryRow(`
+		SELECT `+signatureRequestColumns+`
+		FROM contract_signature_requests
+		WHERE token_hash = ?
+	`, hashSignatureToken(c.Param("token"))))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Signing link not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return nil, false
+	}
+
+	if req.Status == "pending" && time.Now().After(req.ExpiresAt) {
+		database.DB.Exec("UPDATE contract_signature_requests SET status = 'expired' WHERE id = ?", req.ID)
+		req.Status = "expired"
+	}
+
+	if req.Status != "pending" {
+		c.JSON(http.StatusGone, gin.H{"error": "Signing link is no longer valid", "status": req.Status})
+		return nil, false
+	}
+
+	return req, true
+}
+
+// GetSigningDocument shows the employee what they are asked to sign
+func GetSigningDocument(c *gin.Context) {
+	req, ok := loadPendingSignatureRequest(c)
+	if !ok {
+		return
+	}
+
+	doc, err := loadSigningDocument(req.ContractID, req.Version)
+	if err != nil {
+		if _, ok := err.(*pdfFontError); ok {
+			respondPDFError(c, err)
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render contract"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"contract_id":    req.ContractID,
+		"version":        doc.Version.Version,
+		"employee_name":  doc.Contract.Party.EmployeeName,
+		"effective_date": doc.Version.EffectiveDate,
+		"terms":          doc.Version.Terms,
+		"changes":        doc.Version.Changes,
+		"document_hash":  doc.Hash,
+		"document_url":   "/api/sign/" + c.Param("token") + "/document",
+		"expires_at":     req.ExpiresAt,
+	})
+}
+
+// GetSigningDocumentPDF streams the rendered contract for review before signing
+func GetSigningDocumentPDF(c *gin.Context) {
+	req, ok := loadPendingSignatureRequest(c)
+	if !ok {
+		return
+	}
+
+	doc, err := loadSigningDocument(req.ContractID, req.Version)
+	if err != nil {
+		if _, ok := err.(*pdfFontError); ok {
+			respondPDFError(c, err)
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render contract"})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=contract_%d_v%d.pdf", req.ContractID, req.Version))
+	c.Data(http.StatusOK, "application/pdf", doc.PDF)
+}
+
+// decodeSignatureImage accepts a data URL (data:image/png;base64,...) or bare base64 PNG/JPEG
+func decodeSignatureImage(data string) ([]byte, string, error) {
+	imageType := "PNG"
+	if strings.HasPrefix(data, "data:") {
+		comma := strings.Index(data, ",")
+		if comma < 0 {
+			return nil, "", fmt.Errorf("invalid data URL")
+		}
+		header := data[:comma]
+		if strings.Contains(header, "image/jpeg") {
+			imageType = "JPG"
+		} else if !strings.Contains(header, "image/png") {
+			return nil, "", fmt.Errorf("unsupported image type")
+		}
+		data = data[comma+1:]
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(raw) > maxSignatureImageBytes {
+		return nil, "", fmt.Errorf("signature image is too large")
+	}
+
+	return raw, imageType, nil
+}
+
+// SubmitSignature signs the contract version behind a signing link and locks the contract
+func SubmitSignature(c *gin.Context) {
+	sigReq, ok := loadPendingSignatureRequest(c)
+	if !ok {
+		return
+	}
+
+	var body SubmitSignatureBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !body.Agree {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "계약 내용에 동의해야 서명할 수 있습니다"})
+		return
+	}
+
+	var signatureImage []byte
+	var imageType string
+	signatureData := body.SignerName
+	if body.SignatureType == "drawn" {
+		var err error
+		signatureImage, imageType, err = decodeSignatureImage(body.SignatureImage)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signature image: " + err.Error()})
+			return
+		}
+		signatureData = body.SignatureImage
+	}
+
+	doc, err := loadSigningDocument(sigReq.ContractID, sigReq.Version)
+	if err != nil {
+		if _, ok := err.(*pdfFontError); ok {
+			respondPDFError(c, err)
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render contract"})
+		}
+		return
+	}
+
+	// The employee must sign exactly the document they reviewed
+	if !strings.EqualFold(body.DocumentHash, doc.Hash) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The contract changed after it was reviewed", "document_hash": doc.Hash})
+		return
+	}
+
+	signedAt := time.Now()
+	audit := signatureAudit{
+		RequestID:     sigReq.ID,
+		ContractID:    sigReq.ContractID,
+		Version:       sigReq.Version,
+		DocumentHash:  doc.Hash,
+		SignerName:    body.SignerName,
+		SignatureType: body.SignatureType,
+		SignerIP:      c.ClientIP(),
+		UserAgent:     c.Request.UserAgent(),
+		RequestedAt:   sigReq.CreatedAt,
+		SignedAt:      signedAt,
+	}
+
+	pdf, err := renderSignedContractPDF(doc, audit, signatureImage, imageType)
+	if err != nil {
+		respondPDFError(c, err)
+		return
+	}
+	fileName := fmt.Sprintf("contract_%d_v%d_signed_%s.pdf", sigReq.ContractID, sigReq.Version, signedAt.Format("20060102150405"))
+	filePath := filepath.Join("documents", fileName)
+
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to generate signed PDF"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Guarded by status so a link can only be used once
+	result, err := tx.Exec(`
+		UPDATE contract_signature_requests
+		SET status = 'signed', signer_name = ?, signature_type = ?, signature_data = ?,
+		    signer_ip = ?, signer_user_agent = ?, document_hash = ?, signed_at = ?,
+		    signed_file_path = ?
+		WHERE id = ? AND status = 'pending'
+	`, body.SignerName, body.SignatureType, signatureData, audit.SignerIP, audit.UserAgent,
+		doc.Hash, signedAt, filePath, sigReq.ID)
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record signature"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		os.Remove(filePath)
+		c.JSON(http.StatusGone, gin.H{"error": "Signing link is no longer valid"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		UPDATE contract_versions SET signed_date = ? WHERE contract_id = ? AND version = ?
+	`, signedAt, sigReq.ContractID, sigReq.Version)
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign contract version"})
+		return
+	}
+
+	if sigReq.Version == 1 {
+		_, err = tx.Exec(`
+			UPDATE employment_contracts SET signed_date = ?, locked_at = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, signedAt, signedAt, sigReq.ContractID)
+	} else {
+		_, err = tx.Exec(`
+			UPDATE employment_contracts SET locked_at = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, signedAt, sigReq.ContractID)
+	}
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lock contract"})
+		return
+	}
+
+	// Recorded against the template the reviewed contract was rendered from
+	_, err = tx.Exec(`
+		INSERT INTO generated_documents (employee_id, template_id, document_type, file_path, generated_by)
+		VALUES (?, ?, 'contract_signed', ?, ?)
+	`, doc.EmployeeID, doc.Contract.Template.ID, filePath, sigReq.CreatedBy)
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record document generation"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "전자서명이 완료되었습니다",
+		"document_hash": doc.Hash,
+		"signed_at":     signedAt,
+		"file_path":     filePath,
+	})
+}
+
+// signatureAudit is the evidence printed on the audit-trail page of a signed contract
+type signatureAudit struct {
+	RequestID     int
+	ContractID    int
+	Version       int
+	DocumentHash  string
+	SignerName    string
+	SignatureType string
+	SignerIP      string
+	UserAgent     string
+	RequestedAt   time.Time
+	SignedAt      time.Time
+}
+
+// renderSignedContractPDF appends the signature and an audit-trail page to the reviewed contract
+func renderSignedContractPDF(doc *signingDocument, audit signatureAudit, signatureImage []byte, imageType string) (*gofpdf.Fpdf, error) {
+	pdf, err := renderContractPDF(doc.Contract.unsigned())
+	if err != nil {
+		return nil, err
+	}
+
+	pdf.Ln(20)
+	pdf.SetFont(pdfFont, "B", 12)
+	pdf.Cell(40, 10, "근로자 서명")
+	pdf.Ln(12)
+	pdf.SetFont(pdfFont, "", 12)
+	if signatureImage != nil {
+		pdf.RegisterImageOptionsReader("signature", gofpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(signatureImage))
+		pdf.ImageOptions("signature", pdf.GetX(), pdf.GetY(), 50, 0, true, gofpdf.ImageOptions{ImageType: imageType}, 0, "")
+	} else {
+		pdf.SetFont(pdfFont, "", 16)
+		pdf.Cell(40, 10, audit.SignerName)
+		pdf.Ln(12)
+		pdf.SetFont(pdfFont, "", 12)
+	}
+	pdf.Cell(40, 10, fmt.Sprintf("서명일시: %s", audit.SignedAt.Format("2006-01-02 15:04:05")))
+
+	// Audit trail
+	pdf.AddPage()
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.Cell(40, 10, "전자서명 감사 추적 (Audit Trail)")
+	pdf.Ln(16)
+	pdf.SetFont(pdfFont, "", 10)
+
+	lines := []string{
+		fmt.Sprintf("계약 ID: %d", audit.ContractID),
+		fmt.Sprintf("계약 버전: %d", audit.Version),
+		fmt.Sprintf("서명 요청 ID: %d", audit.RequestID),
+		fmt.Sprintf("서명 요청일시: %s", audit.RequestedAt.Format(time.RFC3339)),
+		fmt.Sprintf("서명자: %s", audit.SignerName),
+		fmt.Sprintf("서명 방식: %s", audit.SignatureType),
+		fmt.Sprintf("서명일시: %s", audit.SignedAt.Format(time.RFC3339)),
+		fmt.Sprintf("서명자 IP: %s", audit.SignerIP),
+		fmt.Sprintf("User-Agent: %s", audit.UserAgent),
+		"문서 해시 (SHA-256, 서명 전 문서):",
+		audit.DocumentHash,
+	}
+	for _, line := range lines {
+		pdf.MultiCell(0, 6, line, "", "L", false)
+	}
+
+	return pdf, nil
+}
@@ -0,0 +1,531 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dbtx is implemented by both *sql.DB and *sql.Tx
+type dbtx interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+type SignContractVersionRequest struct {
+	SignedDate string `json:"signed_date" binding:"required"`
+}
+
+// contractVersionHead is the latest version of a contract, which may not be in effect yet
+type contractVersionHead struct {
+	EmployeeID    int
+	StartDate     time.Time
+	Version       int
+	EffectiveDate time.Time
+	Locked        bool
+	Terms         models.ContractTerms
+}
+
+func contractTermsFromRequest(req CreateContractRequest) models.ContractTerms {
+	return models.ContractTerms{
+		ContractType:   req.ContractType,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Workplace:      req.Workplace,
+		JobDescription: req.JobDescription,
+		WorkingHours:   req.WorkingHours,
+		WorkDays:       req.WorkDays,
+		BreakTime:      req.BreakTime,
+		WeeklyHoliday:  req.WeeklyHoliday,
+		WorkScheduleID: req.WorkScheduleID,
+		BaseSalary:     req.BaseSalary,
+		Allowances:     req.Allowances,
+		AllowanceItems: req.AllowanceItems,
+		Benefits:       req.Benefits,
+		ContractTerms:  req.ContractTerms,
+	}
+}
+
+// diffContractTerms lists the fields that differ between two versions, keyed by JSON field name
+func diffContractTerms(old, new models.ContractTerms) map[string]models.FieldChange {
+	changes := make(map[string]models.FieldChange)
+
+	compare := func(field string, o, n interface{}) {
+		if o != n {
+			changes[field] = models.FieldChange{Old: o, New: n}
+		}
+	}
+
+	compare("contract_type", old.ContractType, new.ContractType)
+	compare("start_date", old.StartDate, new.StartDate)
+	compare("end_date", old.EndDate, new.EndDate)
+	compare("workplace", old.Workplace, new.Workplace)
+	compare("job_description", old.JobDescription, new.JobDescription)
+	compare("working_hours", old.WorkingHours, new.WorkingHours)
+	compare("work_days", old.WorkDays, new.WorkDays)
+	compare("break_time", old.BreakTime, new.BreakTime)
+	compare("weekly_holiday", old.WeeklyHoliday, new.WeeklyHoliday)
+	compare("work_schedule_id", old.WorkScheduleID, new.WorkScheduleID)
+	compare("base_salary", old.BaseSalary, new.BaseSalary)
+	compare("allowances", old.Allowances, new.Allowances)
+	if (len(old.AllowanceItems) > 0 || len(new.AllowanceItems) > 0) &&
+		!reflect.DeepEqual(old.AllowanceItems, new.AllowanceItems) {
+		changes["allowance_items"] = models.FieldChange{Old: old.AllowanceItems, New: new.AllowanceItems}
+	}
+	compare("benefits", old.Benefits, new.Benefits)
+	compare("contract_terms", old.ContractTerms, new.ContractTerms)
+
+	return changes
+}
+
+// ensureInitialContractVersion snapshots the contract row as version 1 if the contract
+// has no versions yet (contracts created before versioning) and returns the latest version.
+func ensureInitialContractVersion(tx dbtx, contractID int) (*contractVersionHead, error) {
+	var contract models.EmploymentContract
+	err := tx.QueryRow(`
+		SELECT id, employee_id, contract_type, start_date, end_date, workplace,
+		       job_description, working_hours, work_days, break_time, weekly_holiday,
+		       work_schedule_id, base_salary, allowances, benefits, contract_terms, signed_date,
+		       locked_at
+		FROM employment_contracts WHERE id = ?
+	`, contractID).Scan(
+		&contract.ID, &contract.EmployeeID, &contract.ContractType, &contract.StartDate,
+		&contract.EndDate, &contract.Workplace, &contract.JobDescription,
+		&contract.WorkingHours, &contract.WorkDays, &contract.BreakTime,
+		&contract.WeeklyHoliday, &contract.WorkScheduleID, &contract.BaseSalary,
+		&contract.Allowances, &contract.Benefits, &contract.ContractTerms,
+		&contract.SignedDate, &contract.LockedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	head := &contractVersionHead{
+		EmployeeID: contract.EmployeeID,
+		StartDate:  contract.StartDate,
+		Locked:     contract.LockedAt.Valid,
+	}
+
+	var termsJSON string
+	err = tx.QueryRow(`
+		SELECT version, effective_date, terms FROM contract_versions
+		WHERE contract_id = ? ORDER BY version DESC LIMIT 1
+	`, contractID).Scan(&head.Version, &head.EffectiveDate, &termsJSON)
+
+	if err == nil {
+		if err := json.Unmarshal([]byte(termsJSON), &head.Terms); err != nil {
+			return nil, err
+		}
+		return head, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// No versions yet - the contract row still holds the original terms
+	head.Version = 1
+	head.EffectiveDate = contract.StartDate
+	head.Terms = contractTermsFromRow(contract)
+	head.Terms.AllowanceItems, err = loadContractAllowances(tx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertInitialContractVersion(tx, int64(contractID), head.Terms, contract.StartDate, contract.SignedDate); err != nil {
+		return nil, err
+	}
+
+	return head, nil
+}
+
+// BackfillContractVersions records version 1 of the contracts created before amendments were
+// versioned, so that reading the versions of a contract never has to write them
+func BackfillContractVersions() (int, error) {
+	rows, err := database.DB.Query(`
+		SELECT c.id FROM employment_contracts c
+		WHERE NOT EXISTS (SELECT 1 FROM contract_versions v WHERE v.contract_id = c.id)
+		ORDER BY c.id
+	`)
+	if err != nil {
+		return 0, err
+	}
+	var contractIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		contractIDs = append(contractIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range contractIDs {
+		tx, err := database.DB.Begin()
+		if err != nil {
+			return 0, err
+		}
+		if _, err := ensureInitialContractVersion(tx, id); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(contractIDs), nil
+}
+
+// insertInitialContractVersion records the original terms of a contract as version 1
+func insertInitialContractVersion(tx dbtx, contractID int64, terms models.ContractTerms, startDate time.Time, signedDate sql.NullTime) error {
+	termsJSON, _ := json.Marshal(terms)
+	_, err := tx.Exec(`
+		INSERT INTO contract_versions (contract_id, version, effective_date, terms, signed_date)
+		VALUES (?, 1, ?, ?, ?)
+	`, contractID, startDate, string(termsJSON), signedDate)
+	return err
+}
+
+// applyContractVersion moves the contract row to the terms of a version that has taken effect.
+// While the contract is the employee's active one, the employee's base salary follows it.
+func applyContractVersion(tx dbtx, contractID, version int, terms models.ContractTerms) error {
+	startDate, err := time.Parse("2006-01-02", terms.StartDate)
+	if err != nil {
+		return err
+	}
+
+	var endDate sql.NullTime
+	if terms.EndDate != "" {
+		ed, err := time.Parse("2006-01-02", terms.EndDate)
+		if err != nil {
+			return err
+		}
+		endDate = sql.NullTime{Time: ed, Valid: true}
+	}
+
+	var scheduleID sql.NullInt64
+	if terms.WorkScheduleID != 0 {
+		scheduleID = sql.NullInt64{Int64: int64(terms.WorkScheduleID), Valid: true}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employment_contracts SET contract_type = ?, start_date = ?, end_date = ?, 
+		                               workplace = ?, job_description = ?, working_hours = ?, 
+		                               work_days = ?, break_time = ?, weekly_holiday = ?,
+		                               work_schedule_id = ?, base_salary = ?, allowances = ?, 
+		                               benefits = ?, contract_terms = ?, current_version = ?,
+		                               updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, terms.ContractType, startDate, endDate, terms.Workplace, terms.JobDescription,
+		terms.WorkingHours, terms.WorkDays, terms.BreakTime, terms.WeeklyHoliday,
+		scheduleID, terms.BaseSalary, terms.Allowances, terms.Benefits,
+		terms.ContractTerms, version, contractID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employees SET base_salary = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = (SELECT employee_id FROM employment_contracts WHERE id = ? AND is_active = 1)
+	`, terms.BaseSalary, contractID)
+	if err != nil {
+		return err
+	}
+
+	return saveContractAllowances(tx, int64(contractID), terms.AllowanceItems)
+}
+
+// ApplyDueContractAmendments puts amendments into effect once their effective date is reached.
+// An amendment is pending while its version is above the contract's current_version. Amendments
+// of a contract that has since been locked by an e-signature, deactivated or replaced by a
+// renewal are left unapplied.
+func ApplyDueContractAmendments(asOf time.Time) (int, error) {
+	rows, err := database.DB.Query(`
+		SELECT v.contract_id, v.version, v.effective_date, v.terms
+		FROM contract_versions v
+		JOIN employment_contracts c ON v.contract_id = c.id
+		WHERE v.version > c.current_version AND c.is_active = 1 AND c.locked_at IS NULL
+		ORDER BY v.contract_id, v.version
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	type pendingVersion struct {
+		contractID, version int
+		terms               models.ContractTerms
+	}
+	// Each version holds the full terms, so only the latest due version of a contract is applied
+	var due []pendingVersion
+	for rows.Next() {
+		var p pendingVersion
+		var effectiveDate time.Time
+		var termsJSON string
+		if err := rows.Scan(&p.contractID, &p.version, &effectiveDate, &termsJSON); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if effectiveDate.After(asOf) {
+			continue
+		}
+		if err := json.Unmarshal([]byte(termsJSON), &p.terms); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if n := len(due); n > 0 && due[n-1].contractID == p.contractID {
+			due[n-1] = p
+		} else {
+			due = append(due, p)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, p := range due {
+		tx, err := database.DB.Begin()
+		if err != nil {
+			return 0, err
+		}
+		if err := applyContractVersion(tx, p.contractID, p.version, p.terms); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(due), nil
+}
+
+func contractTermsFromRow(contract models.EmploymentContract) models.ContractTerms {
+	terms := models.ContractTerms{
+		ContractType:   contract.ContractType,
+		StartDate:      contract.StartDate.Format("2006-01-02"),
+		Workplace:      contract.Workplace,
+		JobDescription: contract.JobDescription.String,
+		WorkingHours:   contract.WorkingHours,
+		WorkDays:       contract.WorkDays,
+		BreakTime:      contract.BreakTime.String,
+		WeeklyHoliday:  contract.WeeklyHoliday.String,
+		WorkScheduleID: int(contract.WorkScheduleID.Int64),
+		BaseSalary:     contract.BaseSalary,
+		Allowances:     contract.Allowances.String,
+		Benefits:       contract.Benefits.String,
+		ContractTerms:  contract.ContractTerms.String,
+	}
+	if contract.EndDate.Valid {
+		terms.EndDate = contract.EndDate.Time.Format("2006-01-02")
+	}
+	return terms
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanContractVersion(row rowScanner) (*models.ContractVersion, error) {
+	var version models.ContractVersion
+	var termsJSON string
+	var changesJSON sql.NullString
+
+	err := row.Scan(
+		&version.ID, &version.ContractID, &version.Version, &version.EffectiveDate,
+		&termsJSON, &changesJSON, &version.Reason, &version.SignedDate,
+		&version.CreatedBy, &version.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(termsJSON), &version.Terms); err != nil {
+		return nil, err
+	}
+	if changesJSON.Valid && changesJSON.String != "" {
+		if err := json.Unmarshal([]byte(changesJSON.String), &version.Changes); err != nil {
+			return nil, err
+		}
+	}
+
+	return &version, nil
+}
+
+func loadContractVersion(contractID, version int) (*models.ContractVersion, error) {
+	return scanContractVersion(database.DB.QueryRow(`
+		SELECT id, contract_id, version, effective_date, terms, changes, reason,
+		       signed_date, created_by, created_at
+		FROM contract_versions
+		WHERE contract_id = ? AND version = ?
+	`, contractID, version))
+}
+
+// GetContractVersions lists the original contract and all of its amendments
+func GetContractVersions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	var exists int
+	if err := database.DB.QueryRow("SELECT 1 FROM employment_contracts WHERE id = ?", id).Scan(&exists); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT id, contract_id, version, effective_date, terms, changes, reason,
+		       signed_date, created_by, created_at
+		FROM contract_versions
+		WHERE contract_id = ?
+		ORDER BY version
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	var versions []models.ContractVersion
+	for rows.Next() {
+		version, err := scanContractVersion(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan contract version"})
+			return
+		}
+		versions = append(versions, *version)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// GetContractVersion returns the terms of one version of a contract
+func GetContractVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	versionNumber, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract version"})
+		return
+	}
+
+	var exists int
+	if err := database.DB.QueryRow("SELECT 1 FROM employment_contracts WHERE id = ?", id).Scan(&exists); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	version, err := loadContractVersion(id, versionNumber)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract version not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": version})
+}
+
+// SignContractVersion records the signature date of the original contract or an amendment
+func SignContractVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	versionNumber, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract version"})
+		return
+	}
+
+	var req SignContractVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	signedDate, err := time.Parse("2006-01-02", req.SignedDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signed date format (YYYY-MM-DD)"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := ensureInitialContractVersion(tx, id); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contract versions"})
+		}
+		return
+	}
+
+	result, err := tx.Exec(`
+		UPDATE contract_versions SET signed_date = ?
+		WHERE contract_id = ? AND version = ? AND signed_date IS NULL
+	`, signedDate, id, versionNumber)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign contract version"})
+		return
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Contract version not found or already signed"})
+		return
+	}
+
+	// The original contract's signature is also kept on the contract row
+	if versionNumber == 1 {
+		_, err = tx.Exec(`
+			UPDATE employment_contracts SET signed_date = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, signedDate, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign contract"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contract version signed successfully"})
+}
@@ -121,42 +121,17 @@ func generatePayslip(c *gin.Context, employeeID *int, userID int) {
 		return
 	}
 
-	// Get latest payroll record
-	var payroll struct {
-		PayPeriod       string
-		BaseSalary      float64
-		Allowances      float64
-		Bonus           float64
-		GrossPay        float64
-		IncomeTax       float64
-		LocalTax        float64
-		NationalPension float64
-		HealthInsurance float64
-		EmploymentIns   float64
-		LongTermCare    float64
-		OtherDeductions float64
-		TotalDeductions float64
-		NetPay          float64
-	}
-	
-	err = db.QueryRow(`
-		SELECT pay_period, base_salary, allowances, bonus, gross_pay,
-		       income_tax, local_tax, national_pension, health_insurance,
-		       employment_insurance, long_term_care, other_deductions,
-		       total_deductions, net_pay
-		FROM payroll_records 
-		WHERE employee_id = ?
-		ORDER BY created_at DESC LIMIT 1
-	`, *employeeID).Scan(&payroll.PayPeriod, &payroll.BaseSalary, &payroll.Allowances,
-		&payroll.Bonus, &payroll.GrossPay, &payroll.IncomeTax, &payroll.LocalTax,
-		&payroll.NationalPension, &payroll.HealthInsurance, &payroll.EmploymentIns,
-		&payroll.LongTermCare, &payroll.OtherDeductions, &payroll.TotalDeductions,
-		&payroll.NetPay)
+	// Get latest payroll record; the payslip shows the stored amounts so it matches the record
+	payroll, _, _, err := scanPayrollWithEmployee(db.QueryRow(payrollSelectQuery+`
+		WHERE p.employee_id = ?
+		ORDER BY p.pay_period_start DESC, p.created_at DESC LIMIT 1
+	`, *employeeID))
 	
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "No payroll record found"})
 		return
 	}
+	payPeriod := payroll.PayPeriodStart.Format("2006-01-02") + " ~ " + payroll.PayPeriodEnd.Format("2006-01-02")
 
 	// Generate PDF
 	pdf := gofpdf.New("P", "mm", "A4", "")
@@ -175,7 +150,7 @@ func generatePayslip(c *gin.Context, employeeID *int, userID int) {
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("직급: %s", employee.Position))
 	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("급여기간: %s", payroll.PayPeriod))
+	pdf.Cell(40, 10, fmt.Sprintf("급여기간: %s", payPeriod))
 	pdf.Ln(15)
 	
 	// Payroll details
@@ -183,14 +158,24 @@ func generatePayslip(c *gin.Context, employeeID *int, userID int) {
 	pdf.Cell(40, 10, "지급내역")
 	pdf.Ln(10)
 	pdf.SetFont("Arial", "", 11)
-	pdf.Cell(40, 8, fmt.Sprintf("기본급: %,.0f원", payroll.BaseSalary))
+	pdf.Cell(40, 8, fmt.Sprintf("기본급: %s", formatWon(payroll.BaseSalary.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("수당: %,.0f원", payroll.Allowances))
+	pdf.Cell(40, 8, fmt.Sprintf("연장근로수당 (%.1f시간): %s", payroll.OvertimeHours, formatWon(payroll.OvertimePay.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("상여금: %,.0f원", payroll.Bonus))
+	pdf.Cell(40, 8, fmt.Sprintf("휴일근로수당 (%.1f시간): %s", payroll.HolidayHours+payroll.HolidayOvertimeHours, formatWon(payroll.HolidayPay.Float64())))
+	pdf.Ln(6)
+	pdf.Cell(40, 8, fmt.Sprintf("야간근로수당 (%.1f시간): %s", payroll.NightHours, formatWon(payroll.NightPay.Float64())))
+	pdf.Ln(6)
+	if payroll.WeeklyHolidayPay != 0 {
+		pdf.Cell(40, 8, fmt.Sprintf("주휴수당 (%.1f시간): %s", payroll.WeeklyHolidayHours, formatWon(payroll.WeeklyHolidayPay.Float64())))
+		pdf.Ln(6)
+	}
+	pdf.Cell(40, 8, fmt.Sprintf("수당: %s", formatWon(payroll.Allowances.Float64())))
+	pdf.Ln(6)
+	pdf.Cell(40, 8, fmt.Sprintf("상여금: %s", formatWon(payroll.Bonus.Float64())))
 	pdf.Ln(6)
 	pdf.SetFont("Arial", "B", 11)
-	pdf.Cell(40, 8, fmt.Sprintf("총 지급액: %,.0f원", payroll.GrossPay))
+	pdf.Cell(40, 8, fmt.Sprintf("총 지급액: %s", formatWon(payroll.GrossPay.Float64())))
 	pdf.Ln(15)
 	
 	// Deductions
@@ -198,27 +183,42 @@ func generatePayslip(c *gin.Context, employeeID *int, userID int) {
 	pdf.Cell(40, 10, "공제내역")
 	pdf.Ln(10)
 	pdf.SetFont("Arial", "", 11)
-	pdf.Cell(40, 8, fmt.Sprintf("소득세: %,.0f원", payroll.IncomeTax))
+	pdf.Cell(40, 8, fmt.Sprintf("소득세: %s", formatWon(payroll.IncomeTax.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("지방소득세: %,.0f원", payroll.LocalTax))
+	pdf.Cell(40, 8, fmt.Sprintf("지방소득세: %s", formatWon(payroll.LocalTax.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("국민연금: %,.0f원", payroll.NationalPension))
+	pdf.Cell(40, 8, fmt.Sprintf("국민연금: %s", formatWon(payroll.NationalPension.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("건강보험: %,.0f원", payroll.HealthInsurance))
+	pdf.Cell(40, 8, fmt.Sprintf("건강보험: %s", formatWon(payroll.HealthInsurance.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("고용보험: %,.0f원", payroll.EmploymentIns))
+	pdf.Cell(40, 8, fmt.Sprintf("고용보험: %s", formatWon(payroll.EmploymentInsurance.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("장기요양보험: %,.0f원", payroll.LongTermCare))
+	pdf.Cell(40, 8, fmt.Sprintf("장기요양보험: %s", formatWon(payroll.LongTermCare.Float64())))
 	pdf.Ln(6)
-	pdf.Cell(40, 8, fmt.Sprintf("기타공제: %,.0f원", payroll.OtherDeductions))
+	if payroll.YearEndTax != 0 || payroll.YearEndLocalTax != 0 {
+		pdf.Cell(40, 8, fmt.Sprintf("연말정산 소득세: %s", formatWon(payroll.YearEndTax.Float64())))
+		pdf.Ln(6)
+		pdf.Cell(40, 8, fmt.Sprintf("연말정산 지방소득세: %s", formatWon(payroll.YearEndLocalTax.Float64())))
+		pdf.Ln(6)
+	}
+	if payroll.AdjustmentDeductions != 0 {
+		pdf.Cell(40, 8, fmt.Sprintf("소급·정정 공제: %s", formatWon(payroll.AdjustmentDeductions.Float64())))
+		pdf.Ln(6)
+	}
+	pdf.Cell(40, 8, fmt.Sprintf("기타공제: %s", formatWon(payroll.OtherDeductions.Float64())))
 	pdf.Ln(6)
 	pdf.SetFont("Arial", "B", 11)
-	pdf.Cell(40, 8, fmt.Sprintf("총 공제액: %,.0f원", payroll.TotalDeductions))
+	pdf.Cell(40, 8, fmt.Sprintf("총 공제액: %s", formatWon(payroll.TotalDeductions.Float64())))
 	pdf.Ln(15)
+	if payroll.AdjustmentPay != 0 {
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(40, 8, fmt.Sprintf("소급·정정 지급: %s", formatWon(payroll.AdjustmentPay.Float64())))
+		pdf.Ln(10)
+	}
 	
 	// Net pay
 	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(40, 10, fmt.Sprintf("실지급액: %,.0f원", payroll.NetPay))
+	pdf.Cell(40, 10, fmt.Sprintf("실지급액: %s", formatWon(payroll.NetPay.Float64())))
 	
 	// Save PDF
 	fileName := fmt.Sprintf("payslip_%d_%s.pdf", *employeeID, time.Now().Format("20060102"))
@@ -333,63 +333,70 @@ func generateContract(c *gin.Context, employeeID *int, userID int) {
 	}
 
 	db := database.GetDB()
-	
-	var contract struct {
-		EmployeeName string
-		Position     string
-		Department   string
-		StartDate    string
-		EndDate      sql.NullString
-		Salary       float64
-		WorkingHours int
-		WorkDays     string
+
+	// Default to the employee's active contract at its current version
+	var contractID, currentVersion int
+	var err error
+	if contractIDStr := c.Query("contract_id"); contractIDStr != "" {
+		id, convErr := strconv.Atoi(contractIDStr)
+		if convErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+			return
+		}
+		err = db.QueryRow(`
+			SELECT id, current_version FROM employment_contracts
+			WHERE id = ? AND employee_id = ?
+		`, id, *employeeID).Scan(&contractID, &currentVersion)
+	} else {
+		err = db.QueryRow(`
+			SELECT id, current_version FROM employment_contracts
+			WHERE employee_id = ? AND is_active = 1
+			ORDER BY created_at DESC LIMIT 1
+		`, *employeeID).Scan(&contractID, &currentVersion)
 	}
-	
-	err := db.QueryRow(`
-		SELECT e.name, ec.position, e.department, ec.start_date, ec.end_date,
-		       ec.salary, ec.working_hours, ec.work_days
-		FROM employment_contracts ec
-		JOIN employees e ON ec.employee_id = e.id
-		WHERE ec.employee_id = ? AND ec.status = 'active'
-		ORDER BY ec.created_at DESC LIMIT 1
-	`, *employeeID).Scan(&contract.EmployeeName, &contract.Position, &contract.Department,
-		&contract.StartDate, &contract.EndDate, &contract.Salary, &contract.WorkingHours,
-		&contract.WorkDays)
-	
+
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "No active contract found"})
 		return
 	}
 
-	// Generate PDF
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-	
-	pdf.Cell(40, 20, "근로계약서")
-	pdf.Ln(30)
-	
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(40, 10, fmt.Sprintf("성    명: %s", contract.EmployeeName))
-	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("부    서: %s", contract.Department))
-	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("직    급: %s", contract.Position))
-	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("계약기간: %s", contract.StartDate))
-	if contract.EndDate.Valid {
-		pdf.Cell(40, 10, fmt.Sprintf(" ~ %s", contract.EndDate.String))
-	} else {
-		pdf.Cell(40, 10, " ~ 무기한")
+	versionNumber := currentVersion
+	if versionStr := c.Query("version"); versionStr != "" {
+		versionNumber, err = strconv.Atoi(versionStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract version"})
+			return
+		}
 	}
-	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("급    여: %,.0f원", contract.Salary))
-	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("근무시간: %d시간", contract.WorkingHours))
-	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("근무요일: %s", contract.WorkDays))
-	
-	fileName := fmt.Sprintf("contract_%d_%s.pdf", *employeeID, time.Now().Format("20060102"))
+
+	if _, err := ensureInitialContractVersion(db, contractID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contract versions"})
+		return
+	}
+
+	version, err := loadContractVersion(contractID, versionNumber)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract version not found"})
+		return
+	}
+
+	doc, err := loadContractDocument(db, *employeeID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee or contract template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contract template"})
+		}
+		return
+	}
+
+	pdf, err := renderContractPDF(doc)
+	if err != nil {
+		respondPDFError(c, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("contract_%d_v%d_%s.pdf", contractID, version.Version, time.Now().Format("20060102"))
 	filePath := filepath.Join("documents", fileName)
 	
 	err = pdf.OutputFileAndClose(filePath)
@@ -401,8 +408,8 @@ func generateContract(c *gin.Context, employeeID *int, userID int) {
 	// Record generation
 	_, err = db.Exec(`
 		INSERT INTO generated_documents (employee_id, template_id, document_type, file_path, generated_by)
-		VALUES (?, 3, 'contract', ?, ?)
-	`, *employeeID, filePath, userID)
+		VALUES (?, ?, 'contract', ?, ?)
+	`, *employeeID, doc.Template.ID, filePath, userID)
 	
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record document generation"})
@@ -412,9 +419,36 @@ func generateContract(c *gin.Context, employeeID *int, userID int) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Contract generated successfully",
 		"file_path": filePath,
+		"contract_id": contractID,
+		"version": version.Version,
 	})
 }
 
+// formatWon formats an amount as 1,234,567원
+func formatWon(amount float64) string {
+	return groupThousands(int64(amount)) + "원"
+}
+
+// groupThousands formats a number with thousands separators, e.g. 1,234,567
+func groupThousands(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	digits := strconv.FormatInt(n, 10)
+	var grouped []byte
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped = append(grouped, ',')
+		}
+		grouped = append(grouped, digits[i])
+	}
+
+	return sign + string(grouped)
+}
+
 func GetEmployeeDocuments(c *gin.Context) {
 	employeeIDStr := c.Param("id")
 	employeeID, err := strconv.Atoi(employeeIDStr)
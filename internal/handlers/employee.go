@@ -52,6 +52,8 @@ type CreateEmployeeWithContractRequest struct {
 	JobDescription   string  `json:"job_description"`
 	WorkingHours     string  `json:"working_hours"`     // "09:00-18:00"
 	WorkDays         string  `json:"work_days"`         // "월-금"
+	BreakTime        string  `json:"break_time"`        // "12:00-13:00"
+	WeeklyHoliday    string  `json:"weekly_holiday"`    // "일요일"
 	Allowances       string  `json:"allowances"`
 	Benefits         string  `json:"benefits"`
 	ContractTerms    string  `json:"contract_terms"`
@@ -353,6 +355,12 @@ func CreateEmployeeWithContract(c *gin.Context) {
 		if req.WorkDays == "" {
 			req.WorkDays = "월-금"
 		}
+		if req.BreakTime == "" {
+			req.BreakTime = "12:00-13:00"
+		}
+		if req.WeeklyHoliday == "" {
+			req.WeeklyHoliday = "일요일"
+		}
 		if req.JobDescription == "" {
 			req.JobDescription = fmt.Sprintf("%s 업무 전반", req.Position)
 		}
@@ -364,6 +372,33 @@ func CreateEmployeeWithContract(c *gin.Context) {
 		}
 	}
 
+	var issues []ComplianceIssue
+	if req.GenerateContract {
+		issues, err = validateContractCompliance(tx, models.ContractTerms{
+			ContractType:   req.ContractType,
+			StartDate:      req.HireDate,
+			EndDate:        req.ContractEndDate,
+			Workplace:      req.Workplace,
+			JobDescription: req.JobDescription,
+			WorkingHours:   req.WorkingHours,
+			WorkDays:       req.WorkDays,
+			BreakTime:      req.BreakTime,
+			WeeklyHoliday:  req.WeeklyHoliday,
+			BaseSalary:     req.BaseSalary,
+			Allowances:     req.Allowances,
+			Benefits:       req.Benefits,
+			ContractTerms:  req.ContractTerms,
+		}, req.SalaryType, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if hasBlockingIssue(issues) {
+			respondComplianceViolation(c, issues)
+			return
+		}
+	}
+
 	// 1. Create Employee
 	result, err := tx.Exec(`
 		INSERT INTO employees (employee_number, name, name_en, phone, email, address, 
@@ -411,11 +446,12 @@ func CreateEmployeeWithContract(c *gin.Context) {
 		contractResult, err := tx.Exec(`
 			INSERT INTO employment_contracts (employee_id, contract_type, start_date, end_date, 
 			                                 workplace, job_description, working_hours, work_days, 
-			                                 base_salary, allowances, benefits, contract_terms)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			                                 break_time, weekly_holiday, base_salary, allowances, benefits,
+			                                 contract_terms)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, empID, req.ContractType, hireDate, contractEndDate, req.Workplace,
-			req.JobDescription, req.WorkingHours, req.WorkDays, req.BaseSalary,
-			req.Allowances, req.Benefits, req.ContractTerms)
+			req.JobDescription, req.WorkingHours, req.WorkDays, req.BreakTime,
+			req.WeeklyHoliday, req.BaseSalary, req.Allowances, req.Benefits, req.ContractTerms)
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract"})
@@ -464,6 +500,7 @@ func CreateEmployeeWithContract(c *gin.Context) {
 	if req.GenerateContract {
 		response["contract_generated"] = true
 		response["contract_id"] = contractID
+		response["compliance_issues"] = issues
 		response["message"] = "직원과 근로계약서가 성공적으로 생성되었습니다"
 	}
 
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"database/sql"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// baseReportedInsurances are charged on a reported monthly income (기준소득월액, 보수월액) within
+// yearly limits. Employment insurance is charged on the actual pay.
+var baseReportedInsurances = map[string]bool{"national_pension": true, "health_insurance": true}
+
+type contributionLimit struct {
+	Lower models.Money
+	Upper models.Money
+}
+
+type ContributionLimitRequest struct {
+	InsuranceType string  `json:"insurance_type" binding:"required"`
+	EffectiveDate string  `json:"effective_date" binding:"required"`
+	LowerLimit    float64 `json:"lower_limit"`
+	UpperLimit    float64 `json:"upper_limit" binding:"required"`
+}
+
+type InsuranceBaseRequest struct {
+	InsuranceType  string  `json:"insurance_type" binding:"required"`
+	EffectiveDate  string  `json:"effective_date" binding:"required"`
+	ReportedAmount float64 `json:"reported_amount" binding:"required"`
+	Notes          string  `json:"notes"`
+}
+
+// contributionBase returns the monthly income an insurance is charged on: the employee's reported
+// base, or the actual pay when none was reported, kept within the limits of the period
+func (pc *PayrollCalculator) contributionBase(insuranceType string, actualPay models.Money) models.Money {
+	base := actualPay
+	if reported, ok := pc.ContributionBases[insuranceType]; ok {
+		base = reported
+	}
+	if limit, ok := pc.ContributionLimits[insuranceType]; ok {
+		base = clampContributionBase(base, limit)
+	}
+	return base
+}
+
+func clampContributionBase(base models.Money, limit contributionLimit) models.Money {
+	if base < limit.Lower {
+		base = limit.Lower
+	}
+	if limit.Upper > 0 && base > limit.Upper {
+		base = limit.Upper
+	}
+	return base
+}
+
+// loadContributionLimits loads the limits of each insurance in force on the date
+func loadContributionLimits(db dbtx, asOf time.Time) (map[string]contributionLimit, error) {
+	limits := make(map[string]contributionLimit)
+	for insuranceType := range baseReportedInsurances {
+		var limit contributionLimit
+		err := db.QueryRow(`
+			SELECT lower_limit, upper_limit FROM insurance_contribution_limits
+			WHERE insurance_type = ? AND effective_date <= ?
+			ORDER BY effective_date DESC LIMIT 1
+		`, insuranceType, asOf.Format("2006-01-02")).Scan(&limit.Lower, &limit.Upper)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		limits[insuranceType] = limit
+	}
+	return limits, nil
+}
+
+// loadContributionBases loads the monthly income last reported for each of the employee's insurances on the date
+func loadContributionBases(db dbtx, employeeID int, asOf time.Time) (map[string]models.Money, error) {
+	bases := make(map[string]models.Money)
+	for insuranceType := range baseReportedInsurances {
+		var amount models.Money
+		err := db.QueryRow(`
+			SELECT reported_amount FROM employee_insurance_bases
+			WHERE employee_id = ? AND insurance_type = ? AND effective_date <= ?
+			ORDER BY effective_date DESC LIMIT 1
+		`, employeeID, insuranceType, asOf.Format("2006-01-02")).Scan(&amount)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		bases[insuranceType] = amount
+	}
+	return bases, nil
+}
+
+func GetContributionLimits(c *gin.Context) {
+	rows, err := database.DB.Query(`
+		SELECT id, insurance_type, effective_date, lower_limit, upper_limit, created_at
+		FROM insurance_contribution_limits
+		ORDER BY insurance_type, effective_date DESC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	limits := []models.ContributionLimit{}
+	for rows.Next() {
+		var limit models.ContributionLimit
+		err := rows.Scan(&limit.ID, &limit.InsuranceType, &limit.EffectiveDate, &limit.LowerLimit, &limit.UpperLimit, &limit.CreatedAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan contribution limit"})
+			return
+		}
+		limits = append(limits, limit)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"limits": limits})
+}
+
+// CreateContributionLimit adds the yearly floor and ceiling of an insurance from an effective date
+func CreateContributionLimit(c *gin.Context) {
+	var req ContributionLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !baseReportedInsurances[req.InsuranceType] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Limits apply to national_pension and health_insurance only"})
+		return
+	}
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective date format (YYYY-MM-DD)"})
+		return
+	}
+	if req.LowerLimit < 0 || req.UpperLimit <= req.LowerLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upper limit must be greater than the lower limit"})
+		return
+	}
+
+	var existingID int
+	err = database.DB.QueryRow(`
+		SELECT id FROM insurance_contribution_limits WHERE insurance_type = ? AND effective_date = ?
+	`, req.InsuranceType, effectiveDate.Format("2006-01-02")).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Limits already exist for this insurance and effective date", "limit_id": existingID})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		INSERT INTO insurance_contribution_limits (insurance_type, effective_date, lower_limit, upper_limit)
+		VALUES (?, ?, ?, ?)
+	`, req.InsuranceType, effectiveDate.Format("2006-01-02"), req.LowerLimit, req.UpperLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contribution limit"})
+		return
+	}
+	id, _ := result.LastInsertId()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":             id,
+		"insurance_type": req.InsuranceType,
+		"effective_date": effectiveDate.Format("2006-01-02"),
+		"lower_limit":    req.LowerLimit,
+		"upper_limit":    req.UpperLimit,
+	})
+}
+
+func GetEmployeeInsuranceBases(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT id, employee_id, insurance_type, effective_date, reported_amount, notes, created_at
+		FROM employee_insurance_bases
+		WHERE employee_id = ?
+		ORDER BY insurance_type, effective_date DESC
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	bases := []models.EmployeeInsuranceBase{}
+	for rows.Next() {
+		var base models.EmployeeInsuranceBase
+		err := rows.Scan(
+			&base.ID, &base.EmployeeID, &base.InsuranceType, &base.EffectiveDate,
+			&base.ReportedAmount, &base.Notes, &base.CreatedAt,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan insurance base"})
+			return
+		}
+		bases = append(bases, base)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"employee_id": id, "bases": bases})
+}
+
+// CreateEmployeeInsuranceBase records the monthly income reported for the employee's insurance
+// (취득·보수월액 변경 신고). Contributions from the effective date are charged on it.
+func CreateEmployeeInsuranceBase(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req InsuranceBaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !baseReportedInsurances[req.InsuranceType] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reported bases apply to national_pension and health_insurance only"})
+		return
+	}
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective date format (YYYY-MM-DD)"})
+		return
+	}
+	if req.ReportedAmount <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reported amount must be positive"})
+		return
+	}
+
+	var exists int
+	err = database.DB.QueryRow("SELECT COUNT(*) FROM employees WHERE id = ?", id).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if exists == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	var notes sql.NullString
+	if req.Notes != "" {
+		notes = sql.NullString{String: req.Notes, Valid: true}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// A second report for the same day replaces the first
+	_, err = tx.Exec(`
+		DELETE FROM employee_insurance_bases WHERE employee_id = ? AND insurance_type = ? AND effective_date = ?
+	`, id, req.InsuranceType, effectiveDate.Format("2006-01-02"))
+	if err == nil {
+		_, err = tx.Exec(`
+			INSERT INTO employee_insurance_bases (employee_id, insurance_type, effective_date, reported_amount, notes)
+			VALUES (?, ?, ?, ?, ?)
+		`, id, req.InsuranceType, effectiveDate.Format("2006-01-02"), req.ReportedAmount, notes)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save insurance base"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	limits, err := loadContributionLimits(database.DB, effectiveDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contribution limits"})
+		return
+	}
+	appliedBase := models.Won(req.ReportedAmount)
+	if limit, ok := limits[req.InsuranceType]; ok {
+		appliedBase = clampContributionBase(appliedBase, limit)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"employee_id":     id,
+		"insurance_type":  req.InsuranceType,
+		"effective_date":  effectiveDate.Format("2006-01-02"),
+		"reported_amount": req.ReportedAmount,
+		"applied_base":    appliedBase,
+	})
+}
+
+// insuranceReconciliation accumulates one employee's year for GetInsuranceReconciliation
+type insuranceReconciliation struct {
+	EmployeeID          int          `json:"employee_id"`
+	EmployeeName        string       `json:"employee_name"`
+	EmployeeNumber      string       `json:"employee_number"`
+	Months              int          `json:"months"`
+	TotalPay            models.Money `json:"total_pay"`
+	AverageMonthlyPay   models.Money `json:"average_monthly_pay"`
+	HealthPaid          models.Money `json:"health_insurance_paid"`
+	HealthDue           models.Money `json:"health_insurance_due"`
+	HealthDifference    models.Money `json:"health_insurance_difference"`
+	LongTermCarePaid    models.Money `json:"long_term_care_paid"`
+	LongTermCareDue     models.Money `json:"long_term_care_due"`
+	LongTermCareDiff    models.Money `json:"long_term_care_difference"`
+	PensionReportedBase models.Money `json:"pension_reported_base"`
+	PensionActualBase   models.Money `json:"pension_actual_base"`
+}
+
+// GetInsuranceReconciliation is the year-end report of contributions charged on reported bases
+// against the actual pay of the year (건강보험 보수총액 정산). A positive difference is owed by the
+// employee; a negative one is refunded. For the national pension, which is not settled yearly,
+// the report compares the reported base with the actual average for the next base report.
+func GetInsuranceReconciliation(c *gin.Context) {
+	year, err := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(time.Now().Year()-1)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return
+	}
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	rows, err := database.DB.Query(payrollSelectQuery+`
+		WHERE p.pay_period_start >= ? AND p.pay_period_start <= ?
+		ORDER BY p.employee_id, p.pay_period_start
+	`, yearStart, yearEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	type yearRecord struct {
+		payroll        *models.PayrollRecord
+		employeeName   string
+		employeeNumber string
+	}
+	var records []yearRecord
+	for rows.Next() {
+		payroll, employeeName, employeeNumber, err := scanPayrollWithEmployee(rows)
+		if err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan payroll"})
+			return
+		}
+		records = append(records, yearRecord{payroll, employeeName, employeeNumber})
+	}
+	rows.Close()
+
+	// Each month is recalculated with the rates and limits of its own period
+	type periodRates struct {
+		rates  *insuranceRates
+		limits map[string]contributionLimit
+	}
+	periods := make(map[string]periodRates)
+	ratesFor := func(periodEnd time.Time) (periodRates, error) {
+		key := periodEnd.Format("2006-01-02")
+		if p, ok := periods[key]; ok {
+			return p, nil
+		}
+		rates, err := loadInsuranceRates(database.DB, periodEnd)
+		if err != nil {
+			return periodRates{}, err
+		}
+		limits, err := loadContributionLimits(database.DB, periodEnd)
+		if err != nil {
+			return periodRates{}, err
+		}
+		periods[key] = periodRates{rates, limits}
+		return periods[key], nil
+	}
+
+	report := []*insuranceReconciliation{}
+	var current *insuranceReconciliation
+	for _, r := range records {
+		if current == nil || current.EmployeeID != r.payroll.EmployeeID {
+			current = &insuranceReconciliation{
+				EmployeeID:     r.payroll.EmployeeID,
+				EmployeeName:   r.employeeName,
+				EmployeeNumber: r.employeeNumber,
+			}
+			report = append(report, current)
+		}
+
+		p, err := ratesFor(r.payroll.PayPeriodEnd)
+		if err != nil {
+			respondPayrollCalculatorError(c, err)
+			return
+		}
+
+		healthBase := r.payroll.TaxablePay
+		if limit, ok := p.limits["health_insurance"]; ok {
+			healthBase = clampContributionBase(healthBase, limit)
+		}
+		healthDue := healthBase.MulRate(p.rates.HealthInsurance).Truncate(10)
+
+		current.Months++
+		current.TotalPay += r.payroll.TaxablePay
+		current.HealthPaid += r.payroll.HealthInsurance
+		current.HealthDue += healthDue
+		current.LongTermCarePaid += r.payroll.LongTermCare
+		current.LongTermCareDue += healthDue.MulRate(p.rates.LongTermCare).Truncate(10)
+	}
+
+	yearEndLimits, err := loadContributionLimits(database.DB, yearEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contribution limits"})
+		return
+	}
+
+	totals := map[string]models.Money{}
+	for _, e := range report {
+		e.AverageMonthlyPay = e.TotalPay / models.Money(e.Months)
+		e.HealthDifference = e.HealthDue - e.HealthPaid
+		e.LongTermCareDiff = e.LongTermCareDue - e.LongTermCarePaid
+
+		bases, err := loadContributionBases(database.DB, e.EmployeeID, yearEnd)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load insurance bases"})
+			return
+		}
+		e.PensionReportedBase = bases["national_pension"]
+		e.PensionActualBase = e.AverageMonthlyPay
+		if limit, ok := yearEndLimits["national_pension"]; ok {
+			e.PensionActualBase = clampContributionBase(e.PensionActualBase, limit)
+		}
+
+		totals["health_insurance_difference"] += e.HealthDifference
+		totals["long_term_care_difference"] += e.LongTermCareDiff
+	}
+
+	c.JSON(http.StatusOK, gin.H{"year": year, "employees": report, "totals": totals})
+}
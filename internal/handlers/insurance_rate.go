@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// insuranceTypes are the social insurances withheld from pay
+var insuranceTypes = []string{"national_pension", "health_insurance", "long_term_care", "employment_insurance"}
+
+// errNoInsuranceRates is returned when a social insurance has no rate in force for a pay period
+var errNoInsuranceRates = errors.New("No social insurance rates are in force for the pay period")
+
+// insuranceRates are the employee contribution rates in force for a pay period
+type insuranceRates struct {
+	NationalPension     float64
+	HealthInsurance     float64
+	LongTermCare        float64 // 건강보험료 대비 비율
+	EmploymentInsurance float64
+}
+
+type InsuranceRateRequest struct {
+	InsuranceType string   `json:"insurance_type" binding:"required"`
+	EffectiveDate string   `json:"effective_date" binding:"required"`
+	EmployeeRate  *float64 `json:"employee_rate" binding:"required"`
+	EmployerRate  *float64 `json:"employer_rate" binding:"required"`
+	Description   string   `json:"description"`
+}
+
+func isInsuranceType(insuranceType string) bool {
+	for _, t := range insuranceTypes {
+		if t == insuranceType {
+			return true
+		}
+	}
+	return false
+}
+
+// loadInsuranceRates loads the latest rate of each social insurance effective on or before the date,
+// so a past period is always calculated with the rates of its time
+func loadInsuranceRates(db dbtx, asOf time.Time) (*insuranceRates, error) {
+	rates := &insuranceRates{}
+	targets := map[string]*float64{
+		"national_pension":     &rates.NationalPension,
+		"health_insurance":     &rates.HealthInsurance,
+		"long_term_care":       &rates.LongTermCare,
+		"employment_insurance": &rates.EmploymentInsurance,
+	}
+
+	for _, insuranceType := range insuranceTypes {
+		err := db.QueryRow(`
+			SELECT employee_rate FROM insurance_rates
+			WHERE insurance_type = ? AND effective_date <= ?
+			ORDER BY effective_date DESC LIMIT 1
+		`, insuranceType, asOf.Format("2006-01-02")).Scan(targets[insuranceType])
+		if err == sql.ErrNoRows {
+			return nil, errNoInsuranceRates
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
+const insuranceRateSelectQuery = `
+	SELECT id, insurance_type, effective_date, employee_rate, employer_rate, description, created_at
+	FROM insurance_rates
+`
+
+func scanInsuranceRate(row rowScanner) (*models.InsuranceRate, error) {
+	var rate models.InsuranceRate
+	err := row.Scan(
+		&rate.ID, &rate.InsuranceType, &rate.EffectiveDate, &rate.EmployeeRate,
+		&rate.EmployerRate, &rate.Description, &rate.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// GetInsuranceRates lists the rate history, optionally of one insurance type
+func GetInsuranceRates(c *gin.Context) {
+	query := insuranceRateSelectQuery
+	args := []interface{}{}
+	if insuranceType := c.Query("insurance_type"); insuranceType != "" {
+		query += " WHERE insurance_type = ?"
+		args = append(args, insuranceType)
+	}
+	query += " ORDER BY insurance_type, effective_date DESC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	rates := []*models.InsuranceRate{}
+	for rows.Next() {
+		rate, err := scanInsuranceRate(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan insurance rate"})
+			return
+		}
+		rates = append(rates, rate)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": rates})
+}
+
+// GetCurrentInsuranceRates returns the rates in force on the date (default today)
+func GetCurrentInsuranceRates(c *gin.Context) {
+	asOf := time.Now().Truncate(24 * time.Hour)
+	if date := c.Query("date"); date != "" {
+		var err error
+		if asOf, err = time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	rates := []*models.InsuranceRate{}
+	for _, insuranceType := range insuranceTypes {
+		rate, err := scanInsuranceRate(database.DB.QueryRow(insuranceRateSelectQuery+`
+			WHERE insurance_type = ? AND effective_date <= ?
+			ORDER BY effective_date DESC LIMIT 1
+		`, insuranceType, asOf.Format("2006-01-02")))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		rates = append(rates, rate)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"date": asOf.Format("2006-01-02"), "rates": rates})
+}
+
+// CreateInsuranceRate adds the rate of an insurance from an effective date, such as next year's rates
+func CreateInsuranceRate(c *gin.Context) {
+	var req InsuranceRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !isInsuranceType(req.InsuranceType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid insurance type", "insurance_types": insuranceTypes})
+		return
+	}
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective date format (YYYY-MM-DD)"})
+		return
+	}
+	if *req.EmployeeRate < 0 || *req.EmployeeRate >= 1 || *req.EmployerRate < 0 || *req.EmployerRate >= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rates must be fractions between 0 and 1 (e.g. 0.045 for 4.5%)"})
+		return
+	}
+
+	var existingID int
+	err = database.DB.QueryRow(`
+		SELECT id FROM insurance_rates WHERE insurance_type = ? AND effective_date = ?
+	`, req.InsuranceType, effectiveDate.Format("2006-01-02")).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A rate already exists for this insurance and effective date", "rate_id": existingID})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var description sql.NullString
+	if req.Description != "" {
+		description = sql.NullString{String: req.Description, Valid: true}
+	}
+
+	result, err := database.DB.Exec(`
+		INSERT INTO insurance_rates (insurance_type, effective_date, employee_rate, employer_rate, description)
+		VALUES (?, ?, ?, ?, ?)
+	`, req.InsuranceType, effectiveDate.Format("2006-01-02"), *req.EmployeeRate, *req.EmployerRate, description)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create insurance rate"})
+		return
+	}
+	id, _ := result.LastInsertId()
+
+	rate, err := scanInsuranceRate(database.DB.QueryRow(insuranceRateSelectQuery+" WHERE id = ?", id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created insurance rate"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+// DeleteInsuranceRate removes a rate that has not taken effect yet. Rates already in force
+// are kept so earlier pay periods keep their amounts.
+func DeleteInsuranceRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid insurance rate ID"})
+		return
+	}
+
+	rate, err := scanInsuranceRate(database.DB.QueryRow(insuranceRateSelectQuery+" WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Insurance rate not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	if !rate.EffectiveDate.After(today) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Rates already in force cannot be deleted"})
+		return
+	}
+
+	if _, err := database.DB.Exec("DELETE FROM insurance_rates WHERE id = ?", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete insurance rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Insurance rate deleted successfully"})
+}
@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MinimumWageRequest struct {
+	Year        int     `json:"year" binding:"required"`
+	HourlyWage  float64 `json:"hourly_wage" binding:"required"`
+	Description string  `json:"description"`
+}
+
+// loadMinimumWage returns the minimum hourly wage in force in the year: that of the latest year on or
+// before it, or zero when the table has none
+func loadMinimumWage(db dbtx, year int) (models.Money, error) {
+	var wage models.Money
+	err := db.QueryRow(`
+		SELECT hourly_wage FROM minimum_wages WHERE year <= ? ORDER BY year DESC LIMIT 1
+	`, year).Scan(&wage)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return wage, err
+}
+
+const minimumWageSelectQuery = `
+	SELECT id, year, hourly_wage, description, created_at FROM minimum_wages
+`
+
+func scanMinimumWage(row rowScanner) (*models.MinimumWage, error) {
+	var wage models.MinimumWage
+	if err := row.Scan(&wage.ID, &wage.Year, &wage.HourlyWage, &wage.Description, &wage.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &wage, nil
+}
+
+// minimumWageViolation is pay that falls below the minimum wage once converted to an hourly wage
+type minimumWageViolation struct {
+	EmployeeID   int          `json:"employee_id"`
+	EmployeeName string       `json:"employee_name"`
+	ContractID   int          `json:"contract_id,omitempty"`
+	PayrollID    int          `json:"payroll_id,omitempty"`
+	SalaryType   string       `json:"salary_type"`
+	CountedPay   models.Money `json:"counted_pay"` // 최저임금 산입 임금
+	Hours        float64      `json:"hours"`       // 환산 시간
+	HourlyWage   float64      `json:"hourly_wage"` // 시간급 환산액
+	MinimumWage  models.Money `json:"minimum_wage"`
+	Shortfall    models.Money `json:"shortfall"` // 최저임금 대비 부족액
+	Message      string       `json:"message"`
+}
+
+// minimumWageError keeps a payroll run with pay below the minimum wage from being approved
+type minimumWageError struct {
+	violations []minimumWageViolation
+}
+
+func (e *minimumWageError) Error() string {
+	return "Payroll run has pay below the minimum wage"
+}
+
+// check converts the counted pay into an hourly wage and reports whether it falls below the minimum
+// wage. Monthly pay covers the monthly contractual hours including the paid weekly holiday (209 for
+// a 40-hour week); hourly pay the hours worked and daily pay 8 hours for each day worked, excluding
+// premiums and weekly holiday pay on both sides.
+func (v *minimumWageViolation) check(workedHours, workedDays, monthlyHours float64) bool {
+	switch v.SalaryType {
+	case "hourly":
+		v.Hours = workedHours
+	case "daily":
+		v.Hours = workedDays * standardDailyHours
+	default:
+		v.Hours = monthlyHours
+	}
+	if v.Hours <= 0 || v.MinimumWage == 0 {
+		return false
+	}
+
+	v.HourlyWage = v.CountedPay.Float64() / v.Hours
+	if v.HourlyWage >= v.MinimumWage.Float64() {
+		return false
+	}
+	v.Shortfall = models.TruncateWon((v.MinimumWage.Float64() - v.HourlyWage) * v.Hours)
+	v.Message = fmt.Sprintf("%s의 시간급 환산액 %s이 최저임금 %s에 미달합니다 (부족액 %s)", v.EmployeeName,
+		formatWon(v.HourlyWage), formatWon(v.MinimumWage.Float64()), formatWon(v.Shortfall.Float64()))
+	return true
+}
+
+// countedAllowancesQuery sums the allowances of a payroll or contract that count toward the minimum
+// wage: fixed allowances paid every month whose type is marked as counted
+const countedAllowancesQuery = `
+	SELECT COALESCE(SUM(i.amount), 0) FROM %s i
+	JOIN allowance_types t ON t.id = i.allowance_type_id
+	WHERE i.%s = %s AND t.payment_type = 'fixed' AND t.minimum_wage = 1`
+
+// payrollRunMinimumWageViolations checks the records of a payroll run against the minimum wage of
+// their pay period
+func payrollRunMinimumWageViolations(db dbtx, runID int) ([]minimumWageViolation, error) {
+	rows, err := db.Query(`
+		SELECT p.id, p.employee_id, e.name, COALESCE(p.salary_type, 'monthly'), p.pay_period_end,
+		       p.base_salary, p.worked_hours, p.worked_days, (`+
+		fmt.Sprintf(countedAllowancesQuery, "payroll_allowance_items", "payroll_id", "p.id")+`)
+		FROM payroll_records p
+		JOIN employees e ON e.id = p.employee_id
+		WHERE p.payroll_run_id = ?
+		ORDER BY e.name
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	type payrollPay struct {
+		violation         minimumWageViolation
+		periodEnd         time.Time
+		base, allowances  models.Money
+		workedHours, days float64
+	}
+	var records []payrollPay
+	for rows.Next() {
+		var r payrollPay
+		err := rows.Scan(&r.violation.PayrollID, &r.violation.EmployeeID, &r.violation.EmployeeName,
+			&r.violation.SalaryType, &r.periodEnd, &r.base, &r.workedHours, &r.days, &r.allowances)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	violations := []minimumWageViolation{}
+	for _, r := range records {
+		if r.violation.MinimumWage, err = loadMinimumWage(db, r.periodEnd.Year()); err != nil {
+			return nil, err
+		}
+		monthlyHours, err := employeeMonthlyHours(db, r.violation.EmployeeID)
+		if err != nil {
+			return nil, err
+		}
+		// The base pay of hourly and daily staff is already the wage times the hours or days worked
+		r.violation.CountedPay = r.base + r.allowances
+		if r.violation.check(r.workedHours, r.days, monthlyHours) {
+			violations = append(violations, r.violation)
+		}
+	}
+	return violations, nil
+}
+
+// contractMinimumWageViolations checks the active contracts of active employees against the minimum
+// wage of the date's year. Monthly contracts count their fixed allowances; hourly and daily contracts
+// are checked on the agreed wage alone.
+func contractMinimumWageViolations(db dbtx, asOf time.Time) ([]minimumWageViolation, error) {
+	minimumWage, err := loadMinimumWage(db, asOf.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT c.id, c.employee_id, e.name, COALESCE(e.salary_type, 'monthly'), c.base_salary, (` +
+		fmt.Sprintf(countedAllowancesQuery, "contract_allowances", "contract_id", "c.id") + `),
+		       c.work_schedule_id, c.working_hours, c.work_days, c.break_time
+		FROM employment_contracts c
+		JOIN employees e ON e.id = c.employee_id
+		WHERE c.is_active = 1 AND e.status = 'active'
+		ORDER BY e.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	type contractPay struct {
+		violation              minimumWageViolation
+		allowances             models.Money
+		scheduleID             sql.NullInt64
+		workingHours, workDays string
+		breakTime              sql.NullString
+	}
+	var contracts []contractPay
+	for rows.Next() {
+		r := contractPay{violation: minimumWageViolation{MinimumWage: minimumWage}}
+		err := rows.Scan(&r.violation.ContractID, &r.violation.EmployeeID, &r.violation.EmployeeName,
+			&r.violation.SalaryType, &r.violation.CountedPay, &r.allowances,
+			&r.scheduleID, &r.workingHours, &r.workDays, &r.breakTime)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		contracts = append(contracts, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	violations := []minimumWageViolation{}
+	for _, r := range contracts {
+		v := r.violation
+		if v.SalaryType != "hourly" && v.SalaryType != "daily" {
+			v.CountedPay += r.allowances
+		}
+		monthlyHours, err := contractMonthlyHours(db, r.scheduleID, r.workingHours, r.workDays, r.breakTime.String)
+		if err != nil {
+			return nil, err
+		}
+		// An hourly wage covers one hour and a daily wage one day
+		if v.check(1, 1, monthlyHours) {
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// GetMinimumWages lists the minimum wage by year
+func GetMinimumWages(c *gin.Context) {
+	rows, err := database.DB.Query(minimumWageSelectQuery + " ORDER BY year DESC")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	wages := []*models.MinimumWage{}
+	for rows.Next() {
+		wage, err := scanMinimumWage(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan minimum wage"})
+			return
+		}
+		wages = append(wages, wage)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"minimum_wages": wages})
+}
+
+// CreateMinimumWage adds the minimum wage of a year, usually next year's once it is announced
+func CreateMinimumWage(c *gin.Context) {
+	var req MinimumWageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.HourlyWage <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Hourly wage must be positive"})
+		return
+	}
+
+	var existingID int
+	err := database.DB.QueryRow("SELECT id FROM minimum_wages WHERE year = ?", req.Year).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A minimum wage already exists for this year", "minimum_wage_id": existingID})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var description sql.NullString
+	if req.Description != "" {
+		description = sql.NullString{String: req.Description, Valid: true}
+	}
+
+	result, err := database.DB.Exec(`
+		INSERT INTO minimum_wages (year, hourly_wage, description) VALUES (?, ?, ?)
+	`, req.Year, models.Won(req.HourlyWage), description)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create minimum wage"})
+		return
+	}
+	id, _ := result.LastInsertId()
+
+	wage, err := scanMinimumWage(database.DB.QueryRow(minimumWageSelectQuery+" WHERE id = ?", id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created minimum wage"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"minimum_wage": wage})
+}
+
+// DeleteMinimumWage removes the minimum wage of a year that has not started yet
+func DeleteMinimumWage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minimum wage ID"})
+		return
+	}
+
+	wage, err := scanMinimumWage(database.DB.QueryRow(minimumWageSelectQuery+" WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Minimum wage not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if wage.Year <= time.Now().Year() {
+		c.JSON(http.StatusConflict, gin.H{"error": "A minimum wage already in force cannot be deleted"})
+		return
+	}
+
+	if _, err := database.DB.Exec("DELETE FROM minimum_wages WHERE id = ?", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete minimum wage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Minimum wage deleted successfully"})
+}
+
+// GetMinimumWageCompliance checks the active contracts against the minimum wage of the date's year
+// (default today), for instance next year's before it takes effect
+func GetMinimumWageCompliance(c *gin.Context) {
+	asOf := time.Now().Truncate(24 * time.Hour)
+	if date := c.Query("date"); date != "" {
+		var err error
+		if asOf, err = time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	minimumWage, err := loadMinimumWage(database.DB, asOf.Year())
+	if err == nil && minimumWage == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No minimum wage is set for " + strconv.Itoa(asOf.Year())})
+		return
+	}
+	var violations []minimumWageViolation
+	if err == nil {
+		violations, err = contractMinimumWageViolations(database.DB, asOf)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":         asOf.Format("2006-01-02"),
+		"minimum_wage": minimumWage,
+		"violations":   violations,
+	})
+}
+
+// GetPayrollRunMinimumWage checks the records of a payroll run against the minimum wage
+func GetPayrollRunMinimumWage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	if _, err := loadPayrollRun(database.DB, id); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	violations, err := payrollRunMinimumWageViolations(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payroll_run_id": id, "violations": violations})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"testing"
+
+	"labor-management-system/internal/models"
+)
+
+// The cases use the 2025 minimum wage of 10,030원 (monthly 2,096,270원 for 209 hours) published by
+// 고용노동부
+func TestMinimumWageViolationCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		salaryType    string
+		countedPay    models.Money
+		workedHours   float64
+		workedDays    float64
+		monthlyHours  float64
+		minimumWage   models.Money
+		wantViolation bool
+		wantHours     float64
+		wantShortfall models.Money
+	}{
+		{"monthly at the minimum", "monthly", 2096270, 0, 0, 209, 10030, false, 209, 0},
+		{"monthly 10 won short", "monthly", 2096260, 0, 0, 209, 10030, true, 209, 10},
+		{"monthly 1,900,000", "monthly", 1900000, 0, 0, 209, 10030, true, 209, 196270},
+		{"part-time 20 hours a week", "monthly", 1043120, 0, 0, 104, 10030, false, 104, 0},
+		{"hourly 10,000 for 100 hours", "hourly", 1000000, 100, 0, 0, 10030, true, 100, 3000},
+		{"daily 80,000 for 10 days", "daily", 800000, 0, 10, 0, 10030, true, 80, 2400},
+		{"daily 90,000 for 10 days", "daily", 900000, 0, 10, 0, 10030, false, 80, 0},
+		{"no hours worked", "hourly", 0, 0, 0, 0, 10030, false, 0, 0},
+		{"no minimum wage for the year", "monthly", 1000000, 0, 0, 209, 0, false, 209, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := minimumWageViolation{SalaryType: tt.salaryType, CountedPay: tt.countedPay, MinimumWage: tt.minimumWage}
+			if got := v.check(tt.workedHours, tt.workedDays, tt.monthlyHours); got != tt.wantViolation {
+				t.Errorf("check() = %v, want %v", got, tt.wantViolation)
+			}
+			if v.Hours != tt.wantHours {
+				t.Errorf("hours = %g, want %g", v.Hours, tt.wantHours)
+			}
+			if v.Shortfall != tt.wantShortfall {
+				t.Errorf("shortfall = %d, want %d", v.Shortfall, tt.wantShortfall)
+			}
+		})
+	}
+}
+
+// 월 소정근로시간 = (주 소정근로시간 + 주휴시간) x 365 / 7 / 12, as in the 고용노동부 guidance
+func TestMonthlyScheduledHours(t *testing.T) {
+	tests := []struct {
+		weeklyHours float64
+		want        float64
+	}{
+		{40, 209},
+		{52, 209},
+		{35, 183},
+		{25, 130},
+		{20, 104},
+		{14, 61},
+	}
+	for _, tt := range tests {
+		if got := monthlyScheduledHours(tt.weeklyHours); got != tt.want {
+			t.Errorf("monthlyScheduledHours(%g) = %g, want %g", tt.weeklyHours, got, tt.want)
+		}
+	}
+}
@@ -15,98 +15,501 @@ type CreatePayrollRequest struct {
 	EmployeeID      int     `json:"employee_id" binding:"required"`
 	PayPeriodStart  string  `json:"pay_period_start" binding:"required"`
 	PayPeriodEnd    string  `json:"pay_period_end" binding:"required"`
-	BaseSalary      float64 `json:"base_salary" binding:"required"`
+	BaseSalary      float64 `json:"base_salary" binding:"required"` // 월급, 시급제는 시급, 일급제는 일급
+	WorkedHours     *float64 `json:"worked_hours"`                   // 시급제 근로시간 (생략 시 근태 기록)
+	WorkedDays      *float64 `json:"worked_days"`                    // 일급제 근무일수 (생략 시 근태 기록)
 	OvertimeHours   float64 `json:"overtime_hours"`
-	HolidayHours    float64 `json:"holiday_hours"`
+	HolidayHours    float64 `json:"holiday_hours"`          // 휴일근로 8시간 이내
+	HolidayOvertimeHours float64 `json:"holiday_overtime_hours"` // 휴일근로 8시간 초과
+	NightHours      float64 `json:"night_hours"`            // 야간근로 (22:00~06:00)
+	FromAttendance  bool    `json:"from_attendance"`        // 연장·휴일·야간근로시간을 근태 기록에서 집계
 	Allowances      float64 `json:"allowances"`
+	AllowanceItems  []PayrollAllowanceInput `json:"allowance_items"`
 	Bonus           float64 `json:"bonus"`
+	UnpaidLeaveDays float64 `json:"unpaid_leave_days"`
 	OtherDeductions float64 `json:"other_deductions"`
 }
 
-// PayrollCalculator handles payroll calculations
+// 월 소정근로시간 (주 40시간 + 주휴 8시간) x 365 / 7 / 12
+const standardMonthlyHours = 209
+
+// 1일 소정근로시간
+const standardDailyHours = 8
+
+// PayrollCalculator handles payroll calculations. Amounts are whole won; each line is
+// truncated by its own rule so the totals reconcile exactly with the stored record.
 type PayrollCalculator struct {
-	BaseSalary      float64
-	OvertimeHours   float64
-	HolidayHours    float64
-	Allowances      float64
-	Bonus           float64
-	OtherDeductions float64
+	SalaryType      string       // monthly, hourly, daily
+	BaseSalary      models.Money // 월급, 시급 또는 일급
+	MonthlyHours    float64      // 월급제: 월 소정근로시간 (0이면 209시간)
+	WorkedHours     float64      // 시급제: 연장근로를 제외한 근로시간
+	WorkedDays      float64      // 일급제: 근무일수
+	OvertimeHours        float64 // 연장근로 (휴일 제외)
+	HolidayHours         float64 // 휴일근로 8시간 이내
+	HolidayOvertimeHours float64 // 휴일근로 8시간 초과
+	NightHours           float64 // 야간근로 (22:00~06:00), 연장·휴일근로와 중복 가산
+	WeeklyHolidayHours   []float64 // 시급·일급제: 주휴수당이 발생한 주별 주휴시간
+	Allowances      models.Money // 항목 구분 없이 입력된 수당 (과세, 통상임금 제외)
+	AllowanceItems  []payrollAllowanceLine
+	Bonus           models.Money
+	UnpaidLeaveDays float64 // 무급휴가 일수 (통상임금 일급만큼 공제)
+	OtherDeductions models.Money
+
+	// 2월 급여에 반영하는 전년도 연말정산 차감징수세액 (환급은 음수)
+	YearEndSettlementID int
+	YearEndTax          models.Money
+	YearEndLocalTax     models.Money
+
+	// 이전 급여의 소급·정정 증감액. 원 급여 기간에 귀속되므로 이번 기간의 과세 급여와 세액·보험료 계산에서 제외
+	AdjustmentIDs        []int
+	AdjustmentPay        models.Money
+	AdjustmentDeductions models.Money
+
+	// 급여 기간의 4대보험 요율, 신고 기준소득월액과 상·하한
+	InsuranceRates     *insuranceRates
+	ContributionBases  map[string]models.Money
+	ContributionLimits map[string]contributionLimit
+
+	// 급여 기간의 최저임금 (시급). 통상시급의 하한
+	MinimumWage models.Money
+
+	// 근로소득 간이세액표와 공제대상가족 수, 원천징수 비율 (80/100/120%)
+	WithholdingTable *withholdingTable
+	Dependents       int
+	WithholdingRate  int
+}
+
+// BasePay is the base pay of the period: the monthly salary, or the hourly or daily wage
+// times the hours or days actually worked
+func (pc *PayrollCalculator) BasePay() models.Money {
+	switch pc.SalaryType {
+	case "hourly":
+		return pc.BaseSalary.MulRate(pc.WorkedHours)
+	case "daily":
+		return pc.BaseSalary.MulRate(pc.WorkedDays)
+	default:
+		return pc.BaseSalary
+	}
+}
+
+// monthlyHours is the monthly contractual hours the monthly salary pays for
+func (pc *PayrollCalculator) monthlyHours() float64 {
+	if pc.MonthlyHours > 0 {
+		return pc.MonthlyHours
+	}
+	return standardMonthlyHours
+}
+
+// OrdinaryHourlyWage returns the 통상시급. For monthly staff it is the base salary plus the fixed
+// allowances counted in ordinary wage, spread over the monthly contractual hours; hourly staff have
+// their hourly wage and daily staff their daily wage over the standard daily hours.
+func (pc *PayrollCalculator) OrdinaryHourlyWage() float64 {
+	var hourlyWage float64
+	switch pc.SalaryType {
+	case "hourly":
+		hourlyWage = pc.BaseSalary.Float64()
+	case "daily":
+		hourlyWage = pc.BaseSalary.Float64() / standardDailyHours
+	default:
+		ordinaryWage := pc.BaseSalary
+		for _, line := range pc.AllowanceItems {
+			if line.Type.OrdinaryWage && line.Type.PaymentType == "fixed" {
+				ordinaryWage += line.Amount
+			}
+		}
+		hourlyWage = ordinaryWage.Float64() / pc.monthlyHours()
+	}
+
+	if minWage := pc.MinimumWage.Float64(); hourlyWage < minWage {
+		hourlyWage = minWage
+	}
+	return hourlyWage
 }
 
-func (pc *PayrollCalculator) Calculate() map[string]float64 {
-	// Korean tax and insurance rates (2024 기준 - 실제 환경에서는 설정에서 관리)
+// AllowanceBreakdown splits each allowance item into its taxable and non-taxable part.
+// The non-taxable limit of a type applies to the month, across all items of that type.
+func (pc *PayrollCalculator) AllowanceBreakdown() []models.PayrollAllowanceItem {
+	used := make(map[int]models.Money)
+	items := make([]models.PayrollAllowanceItem, 0, len(pc.AllowanceItems))
+	for _, line := range pc.AllowanceItems {
+		var nonTaxable models.Money
+		if !line.Type.Taxable {
+			nonTaxable = minMoney(line.Amount, maxMoney(models.Won(line.Type.NonTaxableLimit)-used[line.Type.ID], 0))
+			used[line.Type.ID] += nonTaxable
+		}
+		items = append(items, models.PayrollAllowanceItem{
+			AllowanceTypeID:  line.Type.ID,
+			Name:             line.Type.Name,
+			Amount:           line.Amount,
+			TaxableAmount:    line.Amount - nonTaxable,
+			NonTaxableAmount: nonTaxable,
+		})
+	}
+	return items
+}
+
+// Calculate works out every pay and deduction line. Pay lines drop the fraction of a won;
+// taxes and insurance premiums drop the units digit as well (원 단위 절사).
+func (pc *PayrollCalculator) Calculate() map[string]models.Money {
 	const (
-		overtimeRate         = 1.5  // 연장근로 가산율
-		holidayRate          = 2.0  // 휴일근로 가산율
-		incomeTaxRate        = 0.05 // 소득세율 (간소화)
-		localTaxRate         = 0.1  // 지방소득세율 (소득세의 10%)
-		nationalPensionRate  = 0.045 // 국민연금 4.5%
-		healthInsuranceRate  = 0.0354 // 건강보험 3.54%
-		longTermCareRate     = 0.004564 // 장기요양보험 0.4564%
-		employmentInsuranceRate = 0.009 // 고용보험 0.9%
-		minWage              = 9860 // 2024년 최저임금 (시급)
+		overtimeRate        = 1.5 // 연장근로 가산율
+		holidayRate         = 1.5 // 휴일근로 가산율 (8시간 이내)
+		holidayOvertimeRate = 2.0 // 휴일근로 가산율 (8시간 초과)
+		nightRate           = 0.5 // 야간근로 가산분
+		localTaxRate        = 0.1 // 지방소득세율 (소득세의 10%)
 	)
 
-	// 기본급 기준 시급 계산 (월급 -> 시급)
-	hourlyWage := pc.BaseSalary / (22 * 8) // 월 22일, 일 8시간 기준
-	if hourlyWage < minWage {
-		hourlyWage = minWage
+	// 급여 기간에 시행 중인 4대보험 요율
+	rates := insuranceRates{}
+	if pc.InsuranceRates != nil {
+		rates = *pc.InsuranceRates
 	}
 
+	// 통상임금 기준 시급 계산
+	hourlyWage := pc.OrdinaryHourlyWage()
+
 	// 연장근로수당 계산
-	overtimePay := pc.OvertimeHours * hourlyWage * overtimeRate
+	overtimePay := models.TruncateWon(pc.OvertimeHours * hourlyWage * overtimeRate)
 
 	// 휴일근로수당 계산
-	holidayPay := pc.HolidayHours * hourlyWage * holidayRate
+	holidayPay := models.TruncateWon((pc.HolidayHours*holidayRate + pc.HolidayOvertimeHours*holidayOvertimeRate) * hourlyWage)
+
+	// 야간근로수당 계산 (가산분만, 근로시간 자체는 기본급·연장·휴일근로수당에서 지급)
+	nightPay := models.TruncateWon(pc.NightHours * hourlyWage * nightRate)
+
+	// 주휴수당 계산 (주별로 원 미만 절사). 월급제는 월급에 포함
+	var weeklyHolidayPay models.Money
+	for _, hours := range pc.WeeklyHolidayHours {
+		weeklyHolidayPay += models.TruncateWon(hours * hourlyWage)
+	}
+
+	// 수당 합계 및 비과세 금액
+	allowances := pc.Allowances
+	var nonTaxablePay models.Money
+	for _, item := range pc.AllowanceBreakdown() {
+		allowances += item.Amount
+		nonTaxablePay += item.NonTaxableAmount
+	}
+
+	// 기본급: 월급제는 월급, 시급·일급제는 실제 근로시간·근무일수 기준
+	basePay := pc.BasePay()
+
+	// 무급휴가 공제 (월급제만, 통상임금 일급 x 일수, 기본급 한도). 시급·일급제는 근무하지 않은 날이 지급되지 않음
+	var unpaidLeaveDeduction models.Money
+	if pc.SalaryType != "hourly" && pc.SalaryType != "daily" {
+		unpaidLeaveDeduction = minMoney(models.TruncateWon(pc.UnpaidLeaveDays*hourlyWage*standardDailyHours), basePay)
+	}
 
 	// 총 지급액 계산
-	grossPay := pc.BaseSalary + overtimePay + holidayPay + pc.Allowances + pc.Bonus
-
-	// 4대보험 및 세금 계산
-	nationalPension := grossPay * nationalPensionRate
-	healthInsurance := grossPay * healthInsuranceRate
-	longTermCare := healthInsurance * longTermCareRate
-	employmentInsurance := grossPay * employmentInsuranceRate
-
-	// 소득세 계산 (간이세액표 적용 필요하지만 여기서는 단순화)
-	incomeTax := grossPay * incomeTaxRate
-	localTax := incomeTax * localTaxRate
-
-	// 총 공제액
-	totalDeductions := nationalPension + healthInsurance + longTermCare + 
-					  employmentInsurance + incomeTax + localTax + pc.OtherDeductions
-
-	// 실지급액
-	netPay := grossPay - totalDeductions
-
-	return map[string]float64{
-		"overtime_pay":         overtimePay,
-		"holiday_pay":          holidayPay,
-		"gross_pay":            grossPay,
-		"income_tax":           incomeTax,
-		"local_tax":            localTax,
-		"national_pension":     nationalPension,
-		"health_insurance":     healthInsurance,
-		"long_term_care":       longTermCare,
-		"employment_insurance": employmentInsurance,
-		"total_deductions":     totalDeductions,
-		"net_pay":              netPay,
+	grossPay := basePay - unpaidLeaveDeduction + overtimePay + holidayPay + nightPay + weeklyHolidayPay + allowances + pc.Bonus
+	taxablePay := grossPay - nonTaxablePay
+
+	// 4대보험 계산: 국민연금·건강보험은 신고 기준소득월액(상·하한 적용), 고용보험은 과세 급여 기준.
+	// 보험료는 10원 미만 절사
+	pensionBase := pc.contributionBase("national_pension", taxablePay)
+	healthInsuranceBase := pc.contributionBase("health_insurance", taxablePay)
+	nationalPension := pensionBase.MulRate(rates.NationalPension).Truncate(10)
+	healthInsurance := healthInsuranceBase.MulRate(rates.HealthInsurance).Truncate(10)
+	longTermCare := healthInsurance.MulRate(rates.LongTermCare).Truncate(10)
+	employmentInsurance := taxablePay.MulRate(rates.EmploymentInsurance).Truncate(10)
+
+	// 소득세 계산 (근로소득 간이세액표, 비과세 급여 제외), 원 단위 절사
+	var incomeTax models.Money
+	if pc.WithholdingTable != nil {
+		incomeTax = pc.WithholdingTable.incomeTax(taxablePay, pc.Dependents, pc.WithholdingRate)
+	}
+	localTax := incomeTax.MulRate(localTaxRate).Truncate(10)
+
+	// 총 공제액 (연말정산 환급액과 소급·정정 감액은 공제에서 차감)
+	totalDeductions := nationalPension + healthInsurance + longTermCare +
+		employmentInsurance + incomeTax + localTax + pc.YearEndTax + pc.YearEndLocalTax +
+		pc.AdjustmentDeductions + pc.OtherDeductions
+
+	// 실지급액 (소급·정정 지급액 포함)
+	netPay := grossPay + pc.AdjustmentPay - totalDeductions
+
+	return map[string]models.Money{
+		"base_pay":               basePay,
+		"overtime_pay":           overtimePay,
+		"holiday_pay":            holidayPay,
+		"night_pay":              nightPay,
+		"weekly_holiday_pay":     weeklyHolidayPay,
+		"allowances":             allowances,
+		"unpaid_leave_deduction": unpaidLeaveDeduction,
+		"gross_pay":              grossPay,
+		"taxable_pay":            taxablePay,
+		"non_taxable_pay":        nonTaxablePay,
+		"income_tax":             incomeTax,
+		"local_tax":              localTax,
+		"national_pension":       nationalPension,
+		"health_insurance":       healthInsurance,
+		"long_term_care":         longTermCare,
+		"employment_insurance":   employmentInsurance,
+		"pension_base":           pensionBase,
+		"health_insurance_base":  healthInsuranceBase,
+		"year_end_tax":           pc.YearEndTax,
+		"year_end_local_tax":     pc.YearEndLocalTax,
+		"adjustment_pay":         pc.AdjustmentPay,
+		"adjustment_deductions":  pc.AdjustmentDeductions,
+		"total_deductions":       totalDeductions,
+		"net_pay":                netPay,
+	}
+}
+
+// weeklyHolidayHours is the total of the paid weekly holiday hours of the period
+func (pc *PayrollCalculator) weeklyHolidayHours() float64 {
+	total := 0.0
+	for _, hours := range pc.WeeklyHolidayHours {
+		total += hours
+	}
+	return total
+}
+
+func minMoney(a, b models.Money) models.Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxMoney(a, b models.Money) models.Money {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// applyPayPeriod sets the insurance rates and withholding tax table in force at the end of the pay period
+// and the monthly contractual hours of the employee
+func (pc *PayrollCalculator) applyPayPeriod(db dbtx, employeeID int, periodEnd time.Time) error {
+	rates, err := loadInsuranceRates(db, periodEnd)
+	if err != nil {
+		return err
+	}
+	pc.InsuranceRates = rates
+
+	if pc.ContributionLimits, err = loadContributionLimits(db, periodEnd); err != nil {
+		return err
+	}
+	if pc.ContributionBases, err = loadContributionBases(db, employeeID, periodEnd); err != nil {
+		return err
+	}
+	if pc.MinimumWage, err = loadMinimumWage(db, periodEnd.Year()); err != nil {
+		return err
+	}
+	if pc.MonthlyHours, err = employeeMonthlyHours(db, employeeID); err != nil {
+		return err
+	}
+
+	return pc.applyWithholding(db, employeeID, periodEnd)
+}
+
+// applyAttendance sets the time worked in the period from the attendance records: the hours, days
+// and paid weekly holidays of hourly and daily staff and, with premiums, the overtime, holiday and
+// night hours
+func (pc *PayrollCalculator) applyAttendance(db dbtx, employeeID int, start, end time.Time, premiums bool) error {
+	if !premiums && pc.SalaryType != "hourly" && pc.SalaryType != "daily" {
+		return nil
+	}
+
+	worked, err := loadAttendedTime(db, employeeID, start, end)
+	if err != nil {
+		return err
+	}
+	pc.WorkedHours = worked.RegularHours
+	pc.WorkedDays = worked.Days
+	if pc.SalaryType == "hourly" || pc.SalaryType == "daily" {
+		weeks, err := loadWorkWeeks(db, employeeID, start, end)
+		if err != nil {
+			return err
+		}
+		pc.WeeklyHolidayHours = nil
+		for _, week := range weeks {
+			if week.Eligible {
+				pc.WeeklyHolidayHours = append(pc.WeeklyHolidayHours, week.WeeklyHolidayHours)
+			}
+		}
+	}
+	if premiums {
+		pc.OvertimeHours = worked.OvertimeHours
+		pc.HolidayHours = worked.HolidayHours
+		pc.HolidayOvertimeHours = worked.HolidayOvertimeHours
+		pc.NightHours = worked.NightHours
+	}
+	return nil
+}
+
+// payrollCalculatorFromRequest builds the calculator for a payroll request, resolving its allowance items
+// and the rates in force at the end of the pay period
+func payrollCalculatorFromRequest(db dbtx, req CreatePayrollRequest, periodStart, periodEnd time.Time) (*PayrollCalculator, error) {
+	salaryType, err := employeeSalaryType(db, req.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []payrollAllowanceLine
+	if req.AllowanceItems != nil || req.Allowances == 0 {
+		lines, err = payrollAllowanceLines(db, req.EmployeeID, req.AllowanceItems)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	calculator := &PayrollCalculator{
+		SalaryType:      salaryType,
+		BaseSalary:      models.Won(req.BaseSalary),
+		OvertimeHours:   req.OvertimeHours,
+		HolidayHours:    req.HolidayHours,
+		HolidayOvertimeHours: req.HolidayOvertimeHours,
+		NightHours:      req.NightHours,
+		Allowances:      models.Won(req.Allowances),
+		AllowanceItems:  lines,
+		Bonus:           models.Won(req.Bonus),
+		UnpaidLeaveDays: req.UnpaidLeaveDays,
+		OtherDeductions: models.Won(req.OtherDeductions),
+	}
+	if err := calculator.applyAttendance(db, req.EmployeeID, periodStart, periodEnd, req.FromAttendance); err != nil {
+		return nil, err
+	}
+	// Entered hours and days take precedence over the attendance records
+	if req.WorkedHours != nil {
+		calculator.WorkedHours = *req.WorkedHours
+	}
+	if req.WorkedDays != nil {
+		calculator.WorkedDays = *req.WorkedDays
+	}
+	if err := calculator.applyPayPeriod(db, req.EmployeeID, periodEnd); err != nil {
+		return nil, err
+	}
+	return calculator, nil
+}
+
+const payrollSelectQuery = `
+	SELECT p.id, p.employee_id, p.payroll_run_id, p.pay_period_start, p.pay_period_end, 
+	       p.salary_type, p.wage_rate, p.worked_hours, p.worked_days,
+	       p.base_salary, p.overtime_hours, p.overtime_pay, p.holiday_hours, 
+	       p.holiday_pay, p.holiday_overtime_hours, p.night_hours, p.night_pay,
+	       p.weekly_holiday_hours, p.weekly_holiday_pay, p.allowances, p.bonus, p.unpaid_leave_days, p.unpaid_leave_deduction, p.gross_pay, p.taxable_pay, p.non_taxable_pay,
+	       p.income_tax, p.local_tax, p.national_pension, p.health_insurance, p.employment_insurance, 
+	       p.long_term_care, p.pension_base, p.health_insurance_base, p.year_end_tax, p.year_end_local_tax,
+	       p.adjustment_pay, p.adjustment_deductions, p.other_deductions, p.total_deductions, p.net_pay, 
+	       p.pay_date, p.is_paid, p.created_at, p.updated_at,
+	       e.name as employee_name, e.employee_number
+	FROM payroll_records p
+	JOIN employees e ON p.employee_id = e.id
+`
+
+func scanPayrollWithEmployee(row rowScanner) (*models.PayrollRecord, string, string, error) {
+	var payroll models.PayrollRecord
+	var employeeName, employeeNumber string
+
+	err := row.Scan(
+		&payroll.ID, &payroll.EmployeeID, &payroll.PayrollRunID, &payroll.PayPeriodStart, &payroll.PayPeriodEnd,
+		&payroll.SalaryType, &payroll.WageRate, &payroll.WorkedHours, &payroll.WorkedDays,
+		&payroll.BaseSalary, &payroll.OvertimeHours, &payroll.OvertimePay, &payroll.HolidayHours,
+		&payroll.HolidayPay, &payroll.HolidayOvertimeHours, &payroll.NightHours, &payroll.NightPay,
+		&payroll.WeeklyHolidayHours, &payroll.WeeklyHolidayPay,
+		&payroll.Allowances, &payroll.Bonus,
+		&payroll.UnpaidLeaveDays, &payroll.UnpaidLeaveDeduction, &payroll.GrossPay,
+		&payroll.TaxablePay, &payroll.NonTaxablePay,
+		&payroll.IncomeTax, &payroll.LocalTax, &payroll.NationalPension, &payroll.HealthInsurance,
+		&payroll.EmploymentInsurance, &payroll.LongTermCare, &payroll.PensionBase,
+		&payroll.HealthInsuranceBase, &payroll.YearEndTax, &payroll.YearEndLocalTax,
+		&payroll.AdjustmentPay, &payroll.AdjustmentDeductions, &payroll.OtherDeductions,
+		&payroll.TotalDeductions, &payroll.NetPay, &payroll.PayDate, &payroll.IsPaid,
+		&payroll.CreatedAt, &payroll.UpdatedAt, &employeeName, &employeeNumber,
+	)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return &payroll, employeeName, employeeNumber, nil
+}
+
+// insertPayrollRecord saves a calculated payroll record and its allowance items
+func insertPayrollRecord(tx dbtx, employeeID int, runID sql.NullInt64, start, end time.Time, calculator *PayrollCalculator) (int64, error) {
+	calculations := calculator.Calculate()
+
+	result, err := tx.Exec(`
+		INSERT INTO payroll_records (employee_id, payroll_run_id, pay_period_start, pay_period_end, 
+		                            salary_type, wage_rate, worked_hours, worked_days, base_salary, overtime_hours, overtime_pay, holiday_hours, 
+		                            holiday_pay, holiday_overtime_hours, night_hours, night_pay,
+		                            weekly_holiday_hours, weekly_holiday_pay,
+		                            allowances, bonus, unpaid_leave_days, unpaid_leave_deduction,
+		                            gross_pay, taxable_pay, non_taxable_pay,
+		                            income_tax, local_tax, national_pension, health_insurance, employment_insurance, 
+		                            long_term_care, pension_base, health_insurance_base,
+		                            year_end_tax, year_end_local_tax, adjustment_pay, adjustment_deductions,
+		                            other_deductions, total_deductions, net_pay)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, employeeID, runID, start, end, calculator.SalaryType, calculator.BaseSalary,
+		calculator.WorkedHours, calculator.WorkedDays, calculations["base_pay"], calculator.OvertimeHours,
+		calculations["overtime_pay"], calculator.HolidayHours, calculations["holiday_pay"],
+		calculator.HolidayOvertimeHours, calculator.NightHours, calculations["night_pay"],
+		calculator.weeklyHolidayHours(), calculations["weekly_holiday_pay"], calculations["allowances"], calculator.Bonus, calculator.UnpaidLeaveDays,
+		calculations["unpaid_leave_deduction"], calculations["gross_pay"],
+		calculations["taxable_pay"], calculations["non_taxable_pay"], calculations["income_tax"],
+		calculations["local_tax"], calculations["national_pension"], calculations["health_insurance"],
+		calculations["employment_insurance"], calculations["long_term_care"],
+		calculations["pension_base"], calculations["health_insurance_base"],
+		calculator.YearEndTax, calculator.YearEndLocalTax, calculator.AdjustmentPay, calculator.AdjustmentDeductions,
+		calculator.OtherDeductions, calculations["total_deductions"], calculations["net_pay"])
+	if err != nil {
+		return 0, err
+	}
+
+	payrollID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := postYearEndSettlement(tx, calculator.YearEndSettlementID, payrollID); err != nil {
+		return 0, err
+	}
+	if err := markPayrollAdjustmentsApplied(tx, calculator.AdjustmentIDs, payrollID); err != nil {
+		return 0, err
+	}
+
+	if err := savePayrollAllowanceItems(tx, payrollID, calculator.AllowanceBreakdown()); err != nil {
+		return 0, err
+	}
+	return payrollID, nil
+}
+
+// payrollResponse loads a payroll record with its employee and allowance items
+func payrollResponse(id int) (map[string]interface{}, error) {
+	payroll, employeeName, employeeNumber, err := scanPayrollWithEmployee(
+		database.DB.QueryRow(payrollSelectQuery+" WHERE p.id = ?", id))
+	if err != nil {
+		return nil, err
 	}
+
+	allowanceItems, err := loadPayrollAllowanceItems(database.DB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Corrections recorded against this payroll, and those settled with it
+	adjustments, err := queryPayrollAdjustments(database.DB, "a.original_payroll_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	includedAdjustments, err := queryPayrollAdjustments(database.DB, "a.status = 'applied' AND a.applied_payroll_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"payroll":              payroll,
+		"employee_name":        employeeName,
+		"employee_number":      employeeNumber,
+		"allowance_items":      allowanceItems,
+		"adjustments":          adjustments,
+		"included_adjustments": includedAdjustments,
+	}, nil
 }
 
 func GetPayrollRecords(c *gin.Context) {
-	rows, err := database.DB.Query(`
-		SELECT p.id, p.employee_id, p.pay_period_start, p.pay_period_end, 
-		       p.base_salary, p.overtime_hours, p.overtime_pay, p.holiday_hours, 
-		       p.holiday_pay, p.allowances, p.bonus, p.gross_pay, p.income_tax, 
-		       p.local_tax, p.national_pension, p.health_insurance, p.employment_insurance, 
-		       p.long_term_care, p.other_deductions, p.total_deductions, p.net_pay, 
-		       p.pay_date, p.is_paid, p.created_at, p.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM payroll_records p
-		JOIN employees e ON p.employee_id = e.id
-		ORDER BY p.pay_period_start DESC, e.name
-	`)
+	rows, err := database.DB.Query(payrollSelectQuery + " ORDER BY p.pay_period_start DESC, e.name")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
@@ -115,18 +518,7 @@ func GetPayrollRecords(c *gin.Context) {
 
 	var payrolls []map[string]interface{}
 	for rows.Next() {
-		var payroll models.PayrollRecord
-		var employeeName, employeeNumber string
-		
-		err := rows.Scan(
-			&payroll.ID, &payroll.EmployeeID, &payroll.PayPeriodStart, &payroll.PayPeriodEnd,
-			&payroll.BaseSalary, &payroll.OvertimeHours, &payroll.OvertimePay, &payroll.HolidayHours,
-			&payroll.HolidayPay, &payroll.Allowances, &payroll.Bonus, &payroll.GrossPay,
-			&payroll.IncomeTax, &payroll.LocalTax, &payroll.NationalPension, &payroll.HealthInsurance,
-			&payroll.EmploymentInsurance, &payroll.LongTermCare, &payroll.OtherDeductions,
-			&payroll.TotalDeductions, &payroll.NetPay, &payroll.PayDate, &payroll.IsPaid,
-			&payroll.CreatedAt, &payroll.UpdatedAt, &employeeName, &employeeNumber,
-		)
+		payroll, employeeName, employeeNumber, err := scanPayrollWithEmployee(rows)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan payroll"})
 			return
@@ -150,30 +542,7 @@ func GetPayrollRecord(c *gin.Context) {
 		return
 	}
 
-	var payroll models.PayrollRecord
-	var employeeName, employeeNumber string
-
-	err = database.DB.QueryRow(`
-		SELECT p.id, p.employee_id, p.pay_period_start, p.pay_period_end, 
-		       p.base_salary, p.overtime_hours, p.overtime_pay, p.holiday_hours, 
-		       p.holiday_pay, p.allowances, p.bonus, p.gross_pay, p.income_tax, 
-		       p.local_tax, p.national_pension, p.health_insurance, p.employment_insurance, 
-		       p.long_term_care, p.other_deductions, p.total_deductions, p.net_pay, 
-		       p.pay_date, p.is_paid, p.created_at, p.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM payroll_records p
-		JOIN employees e ON p.employee_id = e.id
-		WHERE p.id = ?
-	`, id).Scan(
-		&payroll.ID, &payroll.EmployeeID, &payroll.PayPeriodStart, &payroll.PayPeriodEnd,
-		&payroll.BaseSalary, &payroll.OvertimeHours, &payroll.OvertimePay, &payroll.HolidayHours,
-		&payroll.HolidayPay, &payroll.Allowances, &payroll.Bonus, &payroll.GrossPay,
-		&payroll.IncomeTax, &payroll.LocalTax, &payroll.NationalPension, &payroll.HealthInsurance,
-		&payroll.EmploymentInsurance, &payroll.LongTermCare, &payroll.OtherDeductions,
-		&payroll.TotalDeductions, &payroll.NetPay, &payroll.PayDate, &payroll.IsPaid,
-		&payroll.CreatedAt, &payroll.UpdatedAt, &employeeName, &employeeNumber,
-	)
-
+	payrollData, err := payrollResponse(id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll record not found"})
@@ -183,12 +552,6 @@ func GetPayrollRecord(c *gin.Context) {
 		return
 	}
 
-	payrollData := map[string]interface{}{
-		"payroll":         payroll,
-		"employee_name":   employeeName,
-		"employee_number": employeeNumber,
-	}
-
 	c.JSON(http.StatusOK, payrollData)
 }
 
@@ -213,74 +576,44 @@ func CreatePayrollRecord(c *gin.Context) {
 	}
 
 	// Calculate payroll using the calculator
-	calculator := PayrollCalculator{
-		BaseSalary:      req.BaseSalary,
-		OvertimeHours:   req.OvertimeHours,
-		HolidayHours:    req.HolidayHours,
-		Allowances:      req.Allowances,
-		Bonus:           req.Bonus,
-		OtherDeductions: req.OtherDeductions,
+	calculator, err := payrollCalculatorFromRequest(database.DB, req, payPeriodStart, payPeriodEnd)
+	if err == nil {
+		err = calculator.applyYearEndSettlement(database.DB, req.EmployeeID, payPeriodStart, 0)
+	}
+	if err == nil {
+		err = calculator.applyPayrollAdjustments(database.DB, req.EmployeeID, 0)
+	}
+	if err != nil {
+		respondPayrollCalculatorError(c, err)
+		return
 	}
 
-	calculations := calculator.Calculate()
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
 
 	// Insert payroll record
-	result, err := database.DB.Exec(`
-		INSERT INTO payroll_records (employee_id, pay_period_start, pay_period_end, 
-		                            base_salary, overtime_hours, overtime_pay, holiday_hours, 
-		                            holiday_pay, allowances, bonus, gross_pay, income_tax, 
-		                            local_tax, national_pension, health_insurance, employment_insurance, 
-		                            long_term_care, other_deductions, total_deductions, net_pay)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, req.EmployeeID, payPeriodStart, payPeriodEnd, req.BaseSalary, req.OvertimeHours,
-		calculations["overtime_pay"], req.HolidayHours, calculations["holiday_pay"],
-		req.Allowances, req.Bonus, calculations["gross_pay"], calculations["income_tax"],
-		calculations["local_tax"], calculations["national_pension"], calculations["health_insurance"],
-		calculations["employment_insurance"], calculations["long_term_care"], req.OtherDeductions,
-		calculations["total_deductions"], calculations["net_pay"])
-
+	payrollID, err := insertPayrollRecord(tx, req.EmployeeID, sql.NullInt64{}, payPeriodStart, payPeriodEnd, calculator)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payroll record"})
 		return
 	}
 
-	payrollID, _ := result.LastInsertId()
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
 
 	// Retrieve created payroll record with employee info
-	var payroll models.PayrollRecord
-	var employeeName, employeeNumber string
-
-	err = database.DB.QueryRow(`
-		SELECT p.id, p.employee_id, p.pay_period_start, p.pay_period_end, 
-		       p.base_salary, p.overtime_hours, p.overtime_pay, p.holiday_hours, 
-		       p.holiday_pay, p.allowances, p.bonus, p.gross_pay, p.income_tax, 
-		       p.local_tax, p.national_pension, p.health_insurance, p.employment_insurance, 
-		       p.long_term_care, p.other_deductions, p.total_deductions, p.net_pay, 
-		       p.pay_date, p.is_paid, p.created_at, p.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM payroll_records p
-		JOIN employees e ON p.employee_id = e.id
-		WHERE p.id = ?
-	`, payrollID).Scan(
-		&payroll.ID, &payroll.EmployeeID, &payroll.PayPeriodStart, &payroll.PayPeriodEnd,
-		&payroll.BaseSalary, &payroll.OvertimeHours, &payroll.OvertimePay, &payroll.HolidayHours,
-		&payroll.HolidayPay, &payroll.Allowances, &payroll.Bonus, &payroll.GrossPay,
-		&payroll.IncomeTax, &payroll.LocalTax, &payroll.NationalPension, &payroll.HealthInsurance,
-		&payroll.EmploymentInsurance, &payroll.LongTermCare, &payroll.OtherDeductions,
-		&payroll.TotalDeductions, &payroll.NetPay, &payroll.PayDate, &payroll.IsPaid,
-		&payroll.CreatedAt, &payroll.UpdatedAt, &employeeName, &employeeNumber,
-	)
-
+	payrollData, err := payrollResponse(int(payrollID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created payroll record"})
 		return
 	}
-
-	payrollData := map[string]interface{}{
-		"payroll":         payroll,
-		"employee_name":   employeeName,
-		"employee_number": employeeNumber,
-	}
+	payrollData["ordinary_hourly_wage"] = calculator.OrdinaryHourlyWage()
 
 	c.JSON(http.StatusCreated, payrollData)
 }
@@ -298,6 +631,10 @@ func UpdatePayrollRecord(c *gin.Context) {
 		return
 	}
 
+	if !checkPayrollUnlocked(c, id) {
+		return
+	}
+
 	// Parse pay period dates
 	payPeriodStart, err := time.Parse("2006-01-02", req.PayPeriodStart)
 	if err != nil {
@@ -312,75 +649,101 @@ func UpdatePayrollRecord(c *gin.Context) {
 	}
 
 	// Recalculate payroll
-	calculator := PayrollCalculator{
-		BaseSalary:      req.BaseSalary,
-		OvertimeHours:   req.OvertimeHours,
-		HolidayHours:    req.HolidayHours,
-		Allowances:      req.Allowances,
-		Bonus:           req.Bonus,
-		OtherDeductions: req.OtherDeductions,
+	calculator, err := payrollCalculatorFromRequest(database.DB, req, payPeriodStart, payPeriodEnd)
+	if err == nil {
+		err = calculator.applyYearEndSettlement(database.DB, req.EmployeeID, payPeriodStart, id)
+	}
+	if err == nil {
+		err = calculator.applyPayrollAdjustments(database.DB, req.EmployeeID, id)
+	}
+	if err != nil {
+		respondPayrollCalculatorError(c, err)
+		return
 	}
 
 	calculations := calculator.Calculate()
 
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
 	// Update payroll record
-	_, err = database.DB.Exec(`
+	result, err := tx.Exec(`
 		UPDATE payroll_records SET pay_period_start = ?, pay_period_end = ?, 
+		                          salary_type = ?, wage_rate = ?, worked_hours = ?, worked_days = ?,
 		                          base_salary = ?, overtime_hours = ?, overtime_pay = ?, 
-		                          holiday_hours = ?, holiday_pay = ?, allowances = ?, 
-		                          bonus = ?, gross_pay = ?, income_tax = ?, local_tax = ?, 
+		                          holiday_hours = ?, holiday_pay = ?, holiday_overtime_hours = ?,
+		                          night_hours = ?, night_pay = ?, weekly_holiday_hours = ?,
+		                          weekly_holiday_pay = ?, allowances = ?, 
+		                          bonus = ?, unpaid_leave_days = ?, unpaid_leave_deduction = ?,
+		                          gross_pay = ?, taxable_pay = ?, non_taxable_pay = ?,
+		                          income_tax = ?, local_tax = ?, 
 		                          national_pension = ?, health_insurance = ?, employment_insurance = ?, 
-		                          long_term_care = ?, other_deductions = ?, total_deductions = ?, 
+		                          long_term_care = ?, pension_base = ?, health_insurance_base = ?,
+		                          year_end_tax = ?, year_end_local_tax = ?,
+		                          adjustment_pay = ?, adjustment_deductions = ?,
+		                          other_deductions = ?, total_deductions = ?, 
 		                          net_pay = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
-	`, payPeriodStart, payPeriodEnd, req.BaseSalary, req.OvertimeHours, calculations["overtime_pay"],
-		req.HolidayHours, calculations["holiday_pay"], req.Allowances, req.Bonus,
-		calculations["gross_pay"], calculations["income_tax"], calculations["local_tax"],
+	`, payPeriodStart, payPeriodEnd, calculator.SalaryType, calculator.BaseSalary,
+		calculator.WorkedHours, calculator.WorkedDays, calculations["base_pay"], calculator.OvertimeHours, calculations["overtime_pay"],
+		calculator.HolidayHours, calculations["holiday_pay"], calculator.HolidayOvertimeHours,
+		calculator.NightHours, calculations["night_pay"], calculator.weeklyHolidayHours(),
+		calculations["weekly_holiday_pay"], calculations["allowances"], calculator.Bonus,
+		req.UnpaidLeaveDays, calculations["unpaid_leave_deduction"], calculations["gross_pay"], calculations["taxable_pay"], calculations["non_taxable_pay"],
+		calculations["income_tax"], calculations["local_tax"],
 		calculations["national_pension"], calculations["health_insurance"], calculations["employment_insurance"],
-		calculations["long_term_care"], req.OtherDeductions, calculations["total_deductions"],
+		calculations["long_term_care"], calculations["pension_base"], calculations["health_insurance_base"],
+		calculator.YearEndTax, calculator.YearEndLocalTax, calculator.AdjustmentPay, calculator.AdjustmentDeductions,
+		calculator.OtherDeductions, calculations["total_deductions"],
 		calculations["net_pay"], id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll record"})
 		return
 	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll record not found"})
+		return
+	}
 
-	// Retrieve updated payroll record with employee info
-	var payroll models.PayrollRecord
-	var employeeName, employeeNumber string
+	if err := savePayrollAllowanceItems(tx, int64(id), calculator.AllowanceBreakdown()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save allowance items"})
+		return
+	}
 
-	err = database.DB.QueryRow(`
-		SELECT p.id, p.employee_id, p.pay_period_start, p.pay_period_end, 
-		       p.base_salary, p.overtime_hours, p.overtime_pay, p.holiday_hours, 
-		       p.holiday_pay, p.allowances, p.bonus, p.gross_pay, p.income_tax, 
-		       p.local_tax, p.national_pension, p.health_insurance, p.employment_insurance, 
-		       p.long_term_care, p.other_deductions, p.total_deductions, p.net_pay, 
-		       p.pay_date, p.is_paid, p.created_at, p.updated_at,
-		       e.name as employee_name, e.employee_number
-		FROM payroll_records p
-		JOIN employees e ON p.employee_id = e.id
-		WHERE p.id = ?
-	`, id).Scan(
-		&payroll.ID, &payroll.EmployeeID, &payroll.PayPeriodStart, &payroll.PayPeriodEnd,
-		&payroll.BaseSalary, &payroll.OvertimeHours, &payroll.OvertimePay, &payroll.HolidayHours,
-		&payroll.HolidayPay, &payroll.Allowances, &payroll.Bonus, &payroll.GrossPay,
-		&payroll.IncomeTax, &payroll.LocalTax, &payroll.NationalPension, &payroll.HealthInsurance,
-		&payroll.EmploymentInsurance, &payroll.LongTermCare, &payroll.OtherDeductions,
-		&payroll.TotalDeductions, &payroll.NetPay, &payroll.PayDate, &payroll.IsPaid,
-		&payroll.CreatedAt, &payroll.UpdatedAt, &employeeName, &employeeNumber,
-	)
+	if err := releaseYearEndSettlements(tx, "id = ?", id); err == nil {
+		err = postYearEndSettlement(tx, calculator.YearEndSettlementID, int64(id))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to post year-end settlement"})
+		return
+	}
 
+	if err := releasePayrollAdjustments(tx, "id = ?", id); err == nil {
+		err = markPayrollAdjustmentsApplied(tx, calculator.AdjustmentIDs, int64(id))
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated payroll record"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply payroll adjustments"})
 		return
 	}
 
-	payrollData := map[string]interface{}{
-		"payroll":         payroll,
-		"employee_name":   employeeName,
-		"employee_number": employeeNumber,
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
 	}
 
+	// Retrieve updated payroll record with employee info
+	payrollData, err := payrollResponse(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated payroll record"})
+		return
+	}
+	payrollData["ordinary_hourly_wage"] = calculator.OrdinaryHourlyWage()
+
 	c.JSON(http.StatusOK, payrollData)
 }
 
@@ -391,12 +754,41 @@ func DeletePayrollRecord(c *gin.Context) {
 		return
 	}
 
-	// Delete payroll record
-	_, err = database.DB.Exec("DELETE FROM payroll_records WHERE id = ?", id)
+	if !checkPayrollUnlocked(c, id) {
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Delete payroll record; a year-end settlement posted to it is posted again with the next February payroll,
+	// and the adjustments applied to it with the next payroll
+	err = releaseYearEndSettlements(tx, "id = ?", id)
+	if err == nil {
+		err = releasePayrollAdjustments(tx, "id = ?", id)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll record"})
 		return
 	}
+	if _, err := tx.Exec("DELETE FROM payroll_allowance_items WHERE payroll_id = ?", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll record"})
+		return
+	}
+	_, err = tx.Exec("DELETE FROM payroll_records WHERE id = ?", id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll record"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Payroll record deleted successfully"})
 }
\ No newline at end of file
@@ -0,0 +1,449 @@
+package handlers
+
+import (
+	"database/sql"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PayrollAdjustmentRequest is the corrected input of a paid payroll record. Omitted fields keep the
+// value as last corrected; the payroll is recalculated at the rates of its own period.
+type PayrollAdjustmentRequest struct {
+	Reason               string                  `json:"reason" binding:"required"`
+	BaseSalary           *float64                `json:"base_salary"` // 월급, 시급제는 시급, 일급제는 일급
+	WorkedHours          *float64                `json:"worked_hours"`
+	WorkedDays           *float64                `json:"worked_days"`
+	OvertimeHours        *float64                `json:"overtime_hours"`
+	HolidayHours         *float64                `json:"holiday_hours"`
+	HolidayOvertimeHours *float64                `json:"holiday_overtime_hours"`
+	NightHours           *float64                `json:"night_hours"`
+	Allowances           *float64                `json:"allowances"`
+	AllowanceItems       []PayrollAllowanceInput `json:"allowance_items"`
+	Bonus                *float64                `json:"bonus"`
+	UnpaidLeaveDays      *float64                `json:"unpaid_leave_days"`
+	OtherDeductions      *float64                `json:"other_deductions"`
+}
+
+// payrollAdjustmentInputColumns are the corrected input kept with an adjustment, in the order of
+// payrollAdjustmentInputFields
+var payrollAdjustmentInputColumns = []string{
+	"wage_rate", "worked_hours", "worked_days", "overtime_hours", "holiday_hours", "holiday_overtime_hours",
+	"night_hours", "unpaid_leave_days",
+}
+
+func payrollAdjustmentInputFields(a *models.PayrollAdjustment) []interface{} {
+	return []interface{}{
+		&a.WageRate, &a.WorkedHours, &a.WorkedDays, &a.OvertimeHours, &a.HolidayHours, &a.HolidayOvertimeHours,
+		&a.NightHours, &a.UnpaidLeaveDays,
+	}
+}
+
+// payrollAdjustmentColumns are the payroll lines an adjustment corrects, in the order of
+// payrollAdjustmentFields
+var payrollAdjustmentColumns = []string{
+	"base_salary", "overtime_pay", "holiday_pay", "night_pay", "weekly_holiday_pay", "allowances", "bonus",
+	"unpaid_leave_deduction", "gross_pay", "taxable_pay", "non_taxable_pay", "income_tax", "local_tax",
+	"national_pension", "health_insurance", "long_term_care", "employment_insurance", "other_deductions",
+	"total_deductions", "net_pay",
+}
+
+func payrollAdjustmentFields(a *models.PayrollAdjustment) []*models.Money {
+	return []*models.Money{
+		&a.BaseSalary, &a.OvertimePay, &a.HolidayPay, &a.NightPay, &a.WeeklyHolidayPay, &a.Allowances, &a.Bonus,
+		&a.UnpaidLeaveDeduction, &a.GrossPay, &a.TaxablePay, &a.NonTaxablePay, &a.IncomeTax, &a.LocalTax,
+		&a.NationalPension, &a.HealthInsurance, &a.LongTermCare, &a.EmploymentInsurance, &a.OtherDeductions,
+		&a.TotalDeductions, &a.NetPay,
+	}
+}
+
+// payrollRecordLines are the amounts of a payroll record in the order of payrollAdjustmentColumns
+func payrollRecordLines(p *models.PayrollRecord) []*models.Money {
+	return []*models.Money{
+		&p.BaseSalary, &p.OvertimePay, &p.HolidayPay, &p.NightPay, &p.WeeklyHolidayPay, &p.Allowances, &p.Bonus,
+		&p.UnpaidLeaveDeduction, &p.GrossPay, &p.TaxablePay, &p.NonTaxablePay, &p.IncomeTax, &p.LocalTax,
+		&p.NationalPension, &p.HealthInsurance, &p.LongTermCare, &p.EmploymentInsurance, &p.OtherDeductions,
+		&p.TotalDeductions, &p.NetPay,
+	}
+}
+
+var payrollAdjustmentSelectQuery = `
+	SELECT a.id, a.original_payroll_id, a.employee_id, e.name, p.pay_period_start, p.pay_period_end, a.reason,
+	       a.` + strings.Join(payrollAdjustmentInputColumns, ", a.") + `,
+	       a.` + strings.Join(payrollAdjustmentColumns, ", a.") + `,
+	       a.status, a.applied_payroll_id, a.created_by, a.created_at, a.applied_at
+	FROM payroll_adjustments a
+	JOIN payroll_records p ON p.id = a.original_payroll_id
+	JOIN employees e ON e.id = a.employee_id`
+
+func scanPayrollAdjustment(row rowScanner) (*models.PayrollAdjustment, error) {
+	var a models.PayrollAdjustment
+	dest := []interface{}{&a.ID, &a.OriginalPayrollID, &a.EmployeeID, &a.EmployeeName, &a.PayPeriodStart,
+		&a.PayPeriodEnd, &a.Reason}
+	dest = append(dest, payrollAdjustmentInputFields(&a)...)
+	for _, field := range payrollAdjustmentFields(&a) {
+		dest = append(dest, field)
+	}
+	dest = append(dest, &a.Status, &a.AppliedPayrollID, &a.CreatedBy, &a.CreatedAt, &a.AppliedAt)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func queryPayrollAdjustments(db dbtx, condition string, args ...interface{}) ([]models.PayrollAdjustment, error) {
+	rows, err := db.Query(payrollAdjustmentSelectQuery+" WHERE "+condition+" ORDER BY a.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	adjustments := []models.PayrollAdjustment{}
+	for rows.Next() {
+		a, err := scanPayrollAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, *a)
+	}
+	return adjustments, rows.Err()
+}
+
+// applyPayrollAdjustments settles the employee's pending adjustments, and those already applied to
+// the payroll record being recalculated, with the payroll
+func (pc *PayrollCalculator) applyPayrollAdjustments(db dbtx, employeeID int, payrollID int) error {
+	pc.AdjustmentIDs, pc.AdjustmentPay, pc.AdjustmentDeductions = nil, 0, 0
+
+	rows, err := db.Query(`
+		SELECT id, gross_pay, total_deductions FROM payroll_adjustments
+		WHERE employee_id = ? AND (status = 'pending' OR (status = 'applied' AND applied_payroll_id = ?))
+		ORDER BY id
+	`, employeeID, payrollID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var pay, deductions models.Money
+		if err := rows.Scan(&id, &pay, &deductions); err != nil {
+			return err
+		}
+		pc.AdjustmentIDs = append(pc.AdjustmentIDs, id)
+		pc.AdjustmentPay += pay
+		pc.AdjustmentDeductions += deductions
+	}
+	return rows.Err()
+}
+
+// markPayrollAdjustmentsApplied records the payroll the adjustments were settled with
+func markPayrollAdjustmentsApplied(tx dbtx, ids []int, payrollID int64) error {
+	for _, id := range ids {
+		_, err := tx.Exec(`
+			UPDATE payroll_adjustments SET status = 'applied', applied_payroll_id = ?, applied_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, payrollID, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releasePayrollAdjustments returns adjustments applied to the payroll records matching the condition
+// to pending, before those records are deleted or recalculated
+func releasePayrollAdjustments(tx dbtx, condition string, args ...interface{}) error {
+	_, err := tx.Exec(`
+		UPDATE payroll_adjustments SET status = 'pending', applied_payroll_id = NULL, applied_at = NULL
+		WHERE status = 'applied' AND applied_payroll_id IN (SELECT id FROM payroll_records WHERE `+condition+`)
+	`, args...)
+	return err
+}
+
+// correctedPayroll is the original payroll record as corrected by the adjustments recorded against it:
+// the input of the latest adjustment and the amounts paid plus all differences
+func correctedPayroll(db dbtx, original *models.PayrollRecord) (*models.PayrollRecord, error) {
+	current := *original
+	adjustments, err := queryPayrollAdjustments(db, "a.original_payroll_id = ?", original.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range adjustments {
+		current.WageRate, current.WorkedHours, current.WorkedDays = a.WageRate, a.WorkedHours, a.WorkedDays
+		current.OvertimeHours, current.HolidayHours = a.OvertimeHours, a.HolidayHours
+		current.HolidayOvertimeHours, current.NightHours = a.HolidayOvertimeHours, a.NightHours
+		current.UnpaidLeaveDays = a.UnpaidLeaveDays
+
+		lines := payrollRecordLines(&current)
+		for i, delta := range payrollAdjustmentFields(&a) {
+			*lines[i] += *delta
+		}
+	}
+	return &current, nil
+}
+
+// correctedPayrollRequest is the payroll request of the record as last corrected, with the new
+// corrections applied. Allowance items stay those of the original record unless replaced.
+func correctedPayrollRequest(db dbtx, original *models.PayrollRecord, req PayrollAdjustmentRequest) (CreatePayrollRequest, error) {
+	items, err := loadPayrollAllowanceItems(db, original.ID)
+	if err != nil {
+		return CreatePayrollRequest{}, err
+	}
+	corrected := CreatePayrollRequest{
+		EmployeeID:           original.EmployeeID,
+		BaseSalary:           original.WageRate.Float64(),
+		WorkedHours:          &original.WorkedHours,
+		WorkedDays:           &original.WorkedDays,
+		OvertimeHours:        original.OvertimeHours,
+		HolidayHours:         original.HolidayHours,
+		HolidayOvertimeHours: original.HolidayOvertimeHours,
+		NightHours:           original.NightHours,
+		Allowances:           original.Allowances.Float64(),
+		AllowanceItems:       []PayrollAllowanceInput{},
+		Bonus:                original.Bonus.Float64(),
+		UnpaidLeaveDays:      original.UnpaidLeaveDays,
+		OtherDeductions:      original.OtherDeductions.Float64(),
+	}
+	// Itemized allowances are part of the record's allowance total
+	for _, item := range items {
+		corrected.AllowanceItems = append(corrected.AllowanceItems, PayrollAllowanceInput{
+			AllowanceTypeID: item.AllowanceTypeID, Amount: item.Amount.Float64(),
+		})
+		corrected.Allowances -= item.Amount.Float64()
+	}
+
+	for _, field := range []struct {
+		value  *float64
+		target *float64
+	}{
+		{req.BaseSalary, &corrected.BaseSalary},
+		{req.OvertimeHours, &corrected.OvertimeHours},
+		{req.HolidayHours, &corrected.HolidayHours},
+		{req.HolidayOvertimeHours, &corrected.HolidayOvertimeHours},
+		{req.NightHours, &corrected.NightHours},
+		{req.Allowances, &corrected.Allowances},
+		{req.Bonus, &corrected.Bonus},
+		{req.UnpaidLeaveDays, &corrected.UnpaidLeaveDays},
+		{req.OtherDeductions, &corrected.OtherDeductions},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+	if req.WorkedHours != nil {
+		corrected.WorkedHours = req.WorkedHours
+	}
+	if req.WorkedDays != nil {
+		corrected.WorkedDays = req.WorkedDays
+	}
+	if req.AllowanceItems != nil {
+		corrected.AllowanceItems = req.AllowanceItems
+	}
+	return corrected, nil
+}
+
+// GetPayrollAdjustments lists the adjustments recorded against a payroll record
+func GetPayrollAdjustments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll ID"})
+		return
+	}
+
+	adjustments, err := queryPayrollAdjustments(database.DB, "a.original_payroll_id = ?", id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"adjustments": adjustments})
+}
+
+// CreatePayrollAdjustment recalculates a paid payroll record with the corrected input and records the
+// difference from what was paid and adjusted so far, to be settled with the next payroll
+func CreatePayrollAdjustment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll ID"})
+		return
+	}
+
+	var req PayrollAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Touching the record first makes concurrent corrections of it wait for each other, so each one
+	// is computed against the adjustments already recorded
+	if _, err := tx.Exec("UPDATE payroll_records SET updated_at = updated_at WHERE id = ?", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	original, _, _, err := scanPayrollWithEmployee(tx.QueryRow(payrollSelectQuery+" WHERE p.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll record not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	// Only paid payroll is adjusted; records still open are corrected in place
+	var runStatus string
+	err = tx.QueryRow("SELECT COALESCE(MAX(status), '') FROM payroll_runs WHERE id = ?", original.PayrollRunID).
+		Scan(&runStatus)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !original.IsPaid && !payrollRunLocked(runStatus) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll record has not been paid; update it instead"})
+		return
+	}
+
+	current, err := correctedPayroll(tx, original)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	corrected, err := correctedPayrollRequest(tx, current, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	calculator, err := payrollCalculatorFromRequest(tx, corrected, original.PayPeriodStart, original.PayPeriodEnd)
+	if err != nil {
+		respondPayrollCalculatorError(c, err)
+		return
+	}
+	// The year-end settlement and adjustments settled with the original stay as they were
+	calculator.YearEndTax, calculator.YearEndLocalTax = original.YearEndTax, original.YearEndLocalTax
+	calculator.AdjustmentPay, calculator.AdjustmentDeductions = original.AdjustmentPay, original.AdjustmentDeductions
+
+	calculations := calculator.Calculate()
+	calculations["base_salary"] = calculations["base_pay"]
+	calculations["bonus"] = calculator.Bonus
+	calculations["other_deductions"] = calculator.OtherDeductions
+
+	adjustment := models.PayrollAdjustment{
+		OriginalPayrollID:    id,
+		EmployeeID:           original.EmployeeID,
+		Reason:               req.Reason,
+		WageRate:             calculator.BaseSalary,
+		WorkedHours:          calculator.WorkedHours,
+		WorkedDays:           calculator.WorkedDays,
+		OvertimeHours:        calculator.OvertimeHours,
+		HolidayHours:         calculator.HolidayHours,
+		HolidayOvertimeHours: calculator.HolidayOvertimeHours,
+		NightHours:           calculator.NightHours,
+		UnpaidLeaveDays:      calculator.UnpaidLeaveDays,
+	}
+	values := []interface{}{adjustment.OriginalPayrollID, adjustment.EmployeeID, adjustment.Reason,
+		adjustment.WageRate, adjustment.WorkedHours, adjustment.WorkedDays, adjustment.OvertimeHours,
+		adjustment.HolidayHours, adjustment.HolidayOvertimeHours, adjustment.NightHours, adjustment.UnpaidLeaveDays}
+	paid := payrollRecordLines(current)
+	changed := false
+	for i, field := range payrollAdjustmentFields(&adjustment) {
+		*field = calculations[payrollAdjustmentColumns[i]] - *paid[i]
+		changed = changed || *field != 0
+		values = append(values, *field)
+	}
+	if !changed {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The correction does not change the payroll"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	values = append(values, userID)
+	result, err := tx.Exec(`
+		INSERT INTO payroll_adjustments (original_payroll_id, employee_id, reason, `+
+		strings.Join(append(append([]string{}, payrollAdjustmentInputColumns...), payrollAdjustmentColumns...), ", ")+`, created_by)
+		VALUES (?, ?, ?`+strings.Repeat(", ?", len(payrollAdjustmentInputColumns)+len(payrollAdjustmentColumns))+`, ?)
+	`, values...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payroll adjustment"})
+		return
+	}
+	adjustmentID, _ := result.LastInsertId()
+
+	created, err := scanPayrollAdjustment(tx.QueryRow(payrollAdjustmentSelectQuery+" WHERE a.id = ?", adjustmentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created payroll adjustment"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListPayrollAdjustments lists adjustments, optionally by status (pending, applied) and employee
+func ListPayrollAdjustments(c *gin.Context) {
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+	if status := c.Query("status"); status != "" {
+		conditions = append(conditions, "a.status = ?")
+		args = append(args, status)
+	}
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		conditions = append(conditions, "a.employee_id = ?")
+		args = append(args, employeeID)
+	}
+
+	adjustments, err := queryPayrollAdjustments(database.DB, strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"adjustments": adjustments})
+}
+
+// DeletePayrollAdjustment withdraws an adjustment that has not been settled yet
+func DeletePayrollAdjustment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll adjustment ID"})
+		return
+	}
+
+	var status string
+	err = database.DB.QueryRow("SELECT status FROM payroll_adjustments WHERE id = ?", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll adjustment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if status != "pending" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll adjustment has been applied to payroll"})
+		return
+	}
+
+	if _, err := database.DB.Exec("DELETE FROM payroll_adjustments WHERE id = ? AND status = 'pending'", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll adjustment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payroll adjustment deleted successfully"})
+}
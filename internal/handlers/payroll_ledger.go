@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ledgerTotals are the hours and amounts of a ledger line, summed for subtotals and totals
+type ledgerTotals struct {
+	WorkedDays           float64                 `json:"worked_days"`
+	WorkedHours          float64                 `json:"worked_hours"`
+	OvertimeHours        float64                 `json:"overtime_hours"`
+	NightHours           float64                 `json:"night_hours"`
+	HolidayHours         float64                 `json:"holiday_hours"`
+	BasePay              models.Money            `json:"base_pay"`
+	OvertimePay          models.Money            `json:"overtime_pay"`
+	HolidayPay           models.Money            `json:"holiday_pay"`
+	NightPay             models.Money            `json:"night_pay"`
+	WeeklyHolidayPay     models.Money            `json:"weekly_holiday_pay"`
+	AllowanceItems       map[string]models.Money `json:"allowance_items"`
+	Allowances           models.Money            `json:"allowances"`
+	Bonus                models.Money            `json:"bonus"`
+	UnpaidLeaveDeduction models.Money            `json:"unpaid_leave_deduction"`
+	GrossPay             models.Money            `json:"gross_pay"`
+	IncomeTax            models.Money            `json:"income_tax"`
+	LocalTax             models.Money            `json:"local_tax"`
+	NationalPension      models.Money            `json:"national_pension"`
+	HealthInsurance      models.Money            `json:"health_insurance"`
+	LongTermCare         models.Money            `json:"long_term_care"`
+	EmploymentInsurance  models.Money            `json:"employment_insurance"`
+	YearEndTax           models.Money            `json:"year_end_tax"`
+	YearEndLocalTax      models.Money            `json:"year_end_local_tax"`
+	AdjustmentPay        models.Money            `json:"adjustment_pay"`
+	AdjustmentDeductions models.Money            `json:"adjustment_deductions"`
+	OtherDeductions      models.Money            `json:"other_deductions"`
+	TotalDeductions      models.Money            `json:"total_deductions"`
+	NetPay               models.Money            `json:"net_pay"`
+}
+
+func (t *ledgerTotals) add(o ledgerTotals) {
+	t.WorkedDays += o.WorkedDays
+	t.WorkedHours += o.WorkedHours
+	t.OvertimeHours += o.OvertimeHours
+	t.NightHours += o.NightHours
+	t.HolidayHours += o.HolidayHours
+	t.BasePay += o.BasePay
+	t.OvertimePay += o.OvertimePay
+	t.HolidayPay += o.HolidayPay
+	t.NightPay += o.NightPay
+	t.WeeklyHolidayPay += o.WeeklyHolidayPay
+	if t.AllowanceItems == nil {
+		t.AllowanceItems = make(map[string]models.Money)
+	}
+	for name, amount := range o.AllowanceItems {
+		t.AllowanceItems[name] += amount
+	}
+	t.Allowances += o.Allowances
+	t.Bonus += o.Bonus
+	t.UnpaidLeaveDeduction += o.UnpaidLeaveDeduction
+	t.GrossPay += o.GrossPay
+	t.IncomeTax += o.IncomeTax
+	t.LocalTax += o.LocalTax
+	t.NationalPension += o.NationalPension
+	t.HealthInsurance += o.HealthInsurance
+	t.LongTermCare += o.LongTermCare
+	t.EmploymentInsurance += o.EmploymentInsurance
+	t.YearEndTax += o.YearEndTax
+	t.YearEndLocalTax += o.YearEndLocalTax
+	t.AdjustmentPay += o.AdjustmentPay
+	t.AdjustmentDeductions += o.AdjustmentDeductions
+	t.OtherDeductions += o.OtherDeductions
+	t.TotalDeductions += o.TotalDeductions
+	t.NetPay += o.NetPay
+}
+
+// ledgerRow is one employee's line of the 임금대장 (근로기준법 시행령 제27조)
+type ledgerRow struct {
+	PayrollID      int          `json:"payroll_id"`
+	EmployeeID     int          `json:"employee_id"`
+	EmployeeNumber string       `json:"employee_number"`
+	EmployeeName   string       `json:"employee_name"`
+	Position       string       `json:"position"`
+	HireDate       time.Time    `json:"hire_date"`
+	SalaryType     string       `json:"salary_type"`
+	WageRate       models.Money `json:"wage_rate"`
+	ledgerTotals
+}
+
+type ledgerDepartment struct {
+	Department string       `json:"department"`
+	Headcount  int          `json:"headcount"`
+	Rows       []ledgerRow  `json:"rows"`
+	Subtotal   ledgerTotals `json:"subtotal"`
+}
+
+type ledgerCompany struct {
+	Name               string `json:"name"`
+	RegistrationNumber string `json:"registration_number"`
+	Address            string `json:"address"`
+	Phone              string `json:"phone"`
+}
+
+type payrollLedger struct {
+	PayPeriod      string             `json:"pay_period"`
+	Company        ledgerCompany      `json:"company"`
+	AllowanceNames []string           `json:"allowance_names"`
+	Departments    []ledgerDepartment `json:"departments"`
+	Headcount      int                `json:"headcount"`
+	Totals         ledgerTotals       `json:"totals"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+}
+
+// loadPayrollLedger builds the ledger from the payroll records whose period starts in the month,
+// grouped by department and ordered by employee number
+func loadPayrollLedger(db dbtx, periodStart time.Time) (*payrollLedger, error) {
+	from, until := periodStart.Format("2006-01-02"), periodStart.AddDate(0, 1, 0).Format("2006-01-02")
+
+	settings, err := loadSettings(db, "company_name", "company_registration_number", "company_address", "company_phone")
+	if err != nil {
+		return nil, err
+	}
+	ledger := &payrollLedger{
+		PayPeriod: periodStart.Format("2006-01"),
+		Company: ledgerCompany{
+			Name:               settings["company_name"],
+			RegistrationNumber: settings["company_registration_number"],
+			Address:            settings["company_address"],
+			Phone:              settings["company_phone"],
+		},
+		AllowanceNames: []string{},
+		Departments:    []ledgerDepartment{},
+		GeneratedAt:    time.Now(),
+	}
+
+	// Allowances itemized as paid, in allowance type order
+	items := make(map[int]map[string]models.Money)
+	rows, err := db.Query(`
+		SELECT i.payroll_id, i.name, i.amount
+		FROM payroll_allowance_items i
+		JOIN payroll_records p ON p.id = i.payroll_id
+		WHERE p.pay_period_start >= ? AND p.pay_period_start < ?
+		ORDER BY i.allowance_type_id, i.id
+	`, from, until)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var payrollID int
+		var name string
+		var amount models.Money
+		if err := rows.Scan(&payrollID, &name, &amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if items[payrollID] == nil {
+			items[payrollID] = make(map[string]models.Money)
+		}
+		items[payrollID][name] += amount
+		if !seen[name] {
+			seen[name] = true
+			ledger.AllowanceNames = append(ledger.AllowanceNames, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(`
+		SELECT p.id, p.employee_id, e.employee_number, e.name, COALESCE(e.department, ''), COALESCE(e.position, ''),
+		       e.hire_date, COALESCE(p.salary_type, 'monthly'), p.wage_rate,
+		       p.worked_days, p.worked_hours, p.overtime_hours, p.night_hours, p.holiday_hours + p.holiday_overtime_hours,
+		       p.base_salary, p.overtime_pay, p.holiday_pay, p.night_pay, p.weekly_holiday_pay, p.allowances, p.bonus,
+		       p.unpaid_leave_deduction, p.gross_pay, p.income_tax, p.local_tax, p.national_pension, p.health_insurance,
+		       p.long_term_care, p.employment_insurance, p.year_end_tax, p.year_end_local_tax,
+		       p.adjustment_pay, p.adjustment_deductions, p.other_deductions, p.total_deductions, p.net_pay
+		FROM payroll_records p
+		JOIN employees e ON e.id = p.employee_id
+		WHERE p.pay_period_start >= ? AND p.pay_period_start < ?
+		ORDER BY COALESCE(e.department, ''), e.employee_number, p.pay_period_start
+	`, from, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ledgerRow
+		var department string
+		err := rows.Scan(&row.PayrollID, &row.EmployeeID, &row.EmployeeNumber, &row.EmployeeName, &department, &row.Position,
+			&row.HireDate, &row.SalaryType, &row.WageRate,
+			&row.WorkedDays, &row.WorkedHours, &row.OvertimeHours, &row.NightHours, &row.HolidayHours,
+			&row.BasePay, &row.OvertimePay, &row.HolidayPay, &row.NightPay, &row.WeeklyHolidayPay, &row.Allowances, &row.Bonus,
+			&row.UnpaidLeaveDeduction, &row.GrossPay, &row.IncomeTax, &row.LocalTax, &row.NationalPension, &row.HealthInsurance,
+			&row.LongTermCare, &row.EmploymentInsurance, &row.YearEndTax, &row.YearEndLocalTax,
+			&row.AdjustmentPay, &row.AdjustmentDeductions, &row.OtherDeductions, &row.TotalDeductions, &row.NetPay)
+		if err != nil {
+			return nil, err
+		}
+		row.AllowanceItems = items[row.PayrollID]
+		if row.AllowanceItems == nil {
+			row.AllowanceItems = map[string]models.Money{}
+		}
+
+		if department == "" {
+			department = "미지정"
+		}
+		if n := len(ledger.Departments); n == 0 || ledger.Departments[n-1].Department != department {
+			ledger.Departments = append(ledger.Departments, ledgerDepartment{Department: department, Rows: []ledgerRow{}})
+		}
+		dept := &ledger.Departments[len(ledger.Departments)-1]
+		dept.Rows = append(dept.Rows, row)
+		dept.Headcount++
+		dept.Subtotal.add(row.ledgerTotals)
+		ledger.Headcount++
+		ledger.Totals.add(row.ledgerTotals)
+	}
+	if ledger.Totals.AllowanceItems == nil {
+		ledger.Totals.AllowanceItems = map[string]models.Money{}
+	}
+	return ledger, rows.Err()
+}
+
+// ledgerColumn is an hours or amount column of the exported ledger
+type ledgerColumn struct {
+	Title string
+	Hours bool
+	Value func(t *ledgerTotals) float64
+}
+
+func ledgerMoney(get func(t *ledgerTotals) models.Money) func(t *ledgerTotals) float64 {
+	return func(t *ledgerTotals) float64 { return get(t).Float64() }
+}
+
+// ledgerColumns lists the columns after the employee details; itemized adds a column per
+// allowance paid in the month ahead of the allowance total
+func ledgerColumns(allowanceNames []string, itemized bool) []ledgerColumn {
+	columns := []ledgerColumn{
+		{"근로일수", true, func(t *ledgerTotals) float64 { return t.WorkedDays }},
+		{"근로시간", true, func(t *ledgerTotals) float64 { return t.WorkedHours }},
+		{"연장시간", true, func(t *ledgerTotals) float64 { return t.OvertimeHours }},
+		{"야간시간", true, func(t *ledgerTotals) float64 { return t.NightHours }},
+		{"휴일시간", true, func(t *ledgerTotals) float64 { return t.HolidayHours }},
+		{"기본급", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.BasePay })},
+		{"연장수당", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.OvertimePay })},
+		{"휴일수당", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.HolidayPay })},
+		{"야간수당", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.NightPay })},
+		{"주휴수당", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.WeeklyHolidayPay })},
+	}
+	if itemized {
+		for _, name := range allowanceNames {
+			name := name
+			columns = append(columns, ledgerColumn{name, false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.AllowanceItems[name] })})
+		}
+	}
+	return append(columns, []ledgerColumn{
+		{"수당계", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.Allowances })},
+		{"상여금", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.Bonus })},
+		{"무급공제", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.UnpaidLeaveDeduction })},
+		{"지급총액", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.GrossPay })},
+		{"소득세", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.IncomeTax })},
+		{"지방소득세", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.LocalTax })},
+		{"국민연금", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.NationalPension })},
+		{"건강보험", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.HealthInsurance })},
+		{"장기요양", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.LongTermCare })},
+		{"고용보험", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.EmploymentInsurance })},
+		{"연말정산", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.YearEndTax + t.YearEndLocalTax })},
+		{"소급공제", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.AdjustmentDeductions })},
+		{"기타공제", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.OtherDeductions })},
+		{"공제총액", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.TotalDeductions })},
+		{"소급지급", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.AdjustmentPay })},
+		{"실지급액", false, ledgerMoney(func(t *ledgerTotals) models.Money { return t.NetPay })},
+	}...)
+}
+
+var salaryTypeNames = map[string]string{"monthly": "월급", "hourly": "시급", "daily": "일급"}
+
+func ledgerXLSX(ledger *payrollLedger) ([]byte, error) {
+	columns := ledgerColumns(ledger.AllowanceNames, true)
+	details := []string{"사번", "성명", "직급", "입사일", "급여형태", "임금"}
+
+	text := func(s string) xlsxCell { return xlsxCell{Value: s} }
+	bold := func(s string) xlsxCell { return xlsxCell{Value: s, Bold: true} }
+	figures := func(t *ledgerTotals, isTotal bool) []xlsxCell {
+		cells := make([]xlsxCell, len(columns))
+		for i, column := range columns {
+			format := "won"
+			if column.Hours {
+				format = "hours"
+			}
+			cells[i] = xlsxCell{Value: column.Value(t), Format: format, Bold: isTotal}
+		}
+		return cells
+	}
+
+	sheet := xlsxSheet{Name: "임금대장 " + ledger.PayPeriod}
+	sheet.Rows = append(sheet.Rows,
+		[]xlsxCell{bold(fmt.Sprintf("임금대장 (%s)", ledger.PayPeriod))},
+		[]xlsxCell{text("사업장"), text(ledger.Company.Name), text("사업자등록번호"), text(ledger.Company.RegistrationNumber)},
+		[]xlsxCell{text("소재지"), text(ledger.Company.Address), text("전화번호"), text(ledger.Company.Phone)},
+		nil,
+	)
+
+	header := []xlsxCell{bold("부서")}
+	for _, title := range details {
+		header = append(header, bold(title))
+	}
+	for _, column := range columns {
+		header = append(header, bold(column.Title))
+	}
+	sheet.Rows = append(sheet.Rows, header)
+
+	for _, dept := range ledger.Departments {
+		for i := range dept.Rows {
+			row := &dept.Rows[i]
+			cells := []xlsxCell{
+				text(dept.Department), text(row.EmployeeNumber), text(row.EmployeeName), text(row.Position),
+				text(row.HireDate.Format("2006-01-02")), text(salaryTypeNames[row.SalaryType]),
+				{Value: row.WageRate, Format: "won"},
+			}
+			sheet.Rows = append(sheet.Rows, append(cells, figures(&row.ledgerTotals, false)...))
+		}
+		subtotal := make([]xlsxCell, 1+len(details))
+		subtotal[0] = bold(fmt.Sprintf("%s 소계 (%d명)", dept.Department, dept.Headcount))
+		sheet.Rows = append(sheet.Rows, append(subtotal, figures(&dept.Subtotal, true)...))
+	}
+	total := make([]xlsxCell, 1+len(details))
+	total[0] = bold(fmt.Sprintf("합계 (%d명)", ledger.Headcount))
+	sheet.Rows = append(sheet.Rows, append(total, figures(&ledger.Totals, true)...))
+
+	sheet.ColumnWidths = []float64{16, 10, 10, 10, 11, 8, 11}
+	for _, column := range columns {
+		if column.Hours {
+			sheet.ColumnWidths = append(sheet.ColumnWidths, 8)
+		} else {
+			sheet.ColumnWidths = append(sheet.ColumnWidths, 12)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeXLSX(&buf, sheet); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ledgerPDF prints the ledger on landscape A4 with the allowances as one total column,
+// repeating the column header on each page
+func ledgerPDF(ledger *payrollLedger) ([]byte, error) {
+	columns := ledgerColumns(ledger.AllowanceNames, false)
+
+	pdf, err := newKoreanPDF("L")
+	if err != nil {
+		return nil, err
+	}
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(false, 10)
+	_, pageHeight := pdf.GetPageSize()
+
+	const numberWidth, nameWidth, hoursWidth, rowHeight = 14.0, 16.0, 9.0, 5.0
+	amountWidth := (277 - numberWidth - nameWidth - 5*hoursWidth) / float64(len(columns)-5)
+
+	printHeader := func() {
+		pdf.SetFont(pdfFont, "B", 5.5)
+		pdf.CellFormat(numberWidth, rowHeight, "사번", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(nameWidth, rowHeight, "성명", "1", 0, "C", false, 0, "")
+		for _, column := range columns {
+			width := amountWidth
+			if column.Hours {
+				width = hoursWidth
+			}
+			pdf.CellFormat(width, rowHeight, column.Title, "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(rowHeight)
+	}
+	printRow := func(number, name string, t *ledgerTotals, isTotal bool) {
+		if pdf.GetY()+rowHeight > pageHeight-10 {
+			pdf.AddPage()
+			printHeader()
+		}
+		style := ""
+		if isTotal {
+			style = "B"
+		}
+		pdf.SetFont(pdfFont, style, 5.5)
+		pdf.CellFormat(numberWidth, rowHeight, number, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(nameWidth, rowHeight, name, "1", 0, "L", false, 0, "")
+		for _, column := range columns {
+			if column.Hours {
+				pdf.CellFormat(hoursWidth, rowHeight, strconv.FormatFloat(column.Value(t), 'f', -1, 64), "1", 0, "R", false, 0, "")
+			} else {
+				pdf.CellFormat(amountWidth, rowHeight, groupThousands(int64(column.Value(t))), "1", 0, "R", false, 0, "")
+			}
+		}
+		pdf.Ln(rowHeight)
+	}
+
+	pdf.AddPage()
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.Cell(0, 8, fmt.Sprintf("임금대장 (%s)", ledger.PayPeriod))
+	pdf.Ln(9)
+	pdf.SetFont(pdfFont, "", 8)
+	pdf.Cell(0, 5, fmt.Sprintf("사업장: %s   사업자등록번호: %s", ledger.Company.Name, ledger.Company.RegistrationNumber))
+	pdf.Ln(5)
+	pdf.Cell(0, 5, fmt.Sprintf("소재지: %s   전화번호: %s   작성일: %s", ledger.Company.Address, ledger.Company.Phone,
+		ledger.GeneratedAt.Format("2006-01-02")))
+	pdf.Ln(7)
+	printHeader()
+
+	for _, dept := range ledger.Departments {
+		for i := range dept.Rows {
+			row := &dept.Rows[i]
+			printRow(row.EmployeeNumber, row.EmployeeName, &row.ledgerTotals, false)
+		}
+		printRow(dept.Department, fmt.Sprintf("소계 %d명", dept.Headcount), &dept.Subtotal, true)
+	}
+	printRow("합계", fmt.Sprintf("%d명", ledger.Headcount), &ledger.Totals, true)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetPayrollLedger returns the 임금대장 of a month (pay_period=YYYY-MM, default this month)
+// as JSON, or as a file with format=xlsx or format=pdf
+func GetPayrollLedger(c *gin.Context) {
+	periodStart, err := time.Parse("2006-01", c.DefaultQuery("pay_period", time.Now().Format("2006-01")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay period format (YYYY-MM)"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "xlsx" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be json, xlsx or pdf"})
+		return
+	}
+
+	ledger, err := loadPayrollLedger(database.DB, periodStart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load payroll ledger"})
+		return
+	}
+
+	switch format {
+	case "xlsx":
+		content, err := ledgerXLSX(ledger)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate XLSX"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=payroll_ledger_%s.xlsx", ledger.PayPeriod))
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", content)
+	case "pdf":
+		content, err := ledgerPDF(ledger)
+		if err != nil {
+			respondPDFError(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=payroll_ledger_%s.pdf", ledger.PayPeriod))
+		c.Data(http.StatusOK, "application/pdf", content)
+	default:
+		c.JSON(http.StatusOK, ledger)
+	}
+}
@@ -0,0 +1,755 @@
+package handlers
+
+import (
+	"database/sql"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unpaidLeaveTypes are the leave types deducted from pay
+var unpaidLeaveTypes = map[string]bool{"unpaid": true}
+
+type CreatePayrollRunRequest struct {
+	PayPeriod string `json:"pay_period" binding:"required"` // YYYY-MM
+	PayDate   string `json:"pay_date"`
+}
+
+const payrollRunSelectQuery = `
+	SELECT id, pay_period, period_start, period_end, pay_date, status, created_by,
+	       approved_by, approved_at, paid_at, created_at, updated_at
+	FROM payroll_runs
+`
+
+func scanPayrollRun(row rowScanner) (*models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := row.Scan(
+		&run.ID, &run.PayPeriod, &run.PeriodStart, &run.PeriodEnd, &run.PayDate, &run.Status,
+		&run.CreatedBy, &run.ApprovedBy, &run.ApprovedAt, &run.PaidAt, &run.CreatedAt, &run.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func loadPayrollRun(db dbtx, id int) (*models.PayrollRun, error) {
+	return scanPayrollRun(db.QueryRow(payrollRunSelectQuery+" WHERE id = ?", id))
+}
+
+// payrollRunLocked reports whether a run can no longer be changed
+func payrollRunLocked(status string) bool {
+	return status == "approved" || status == "paid"
+}
+
+// checkPayrollUnlocked responds with 409 and returns false when the payroll record belongs to
+// an approved or paid run, or has been paid. Paid payroll is corrected with an adjustment.
+func checkPayrollUnlocked(c *gin.Context, payrollID int) bool {
+	var status string
+	var paid bool
+	err := database.DB.QueryRow(`
+		SELECT COALESCE(r.status, ''), p.is_paid FROM payroll_records p
+		LEFT JOIN payroll_runs r ON p.payroll_run_id = r.id
+		WHERE p.id = ?
+	`, payrollID).Scan(&status, &paid)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return false
+	}
+	if payrollRunLocked(status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll run has been " + status + " and is locked; record a payroll adjustment instead"})
+		return false
+	}
+	if paid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll record has been paid; record a payroll adjustment instead"})
+		return false
+	}
+	return true
+}
+
+// attendedTime is the time an employee worked within a pay period, split by how it is paid
+type attendedTime struct {
+	RegularHours         float64 // 소정근로시간 내 근로 (휴일 제외)
+	Days                 float64 // 근무일수 (휴일 제외)
+	OvertimeHours        float64 // 승인된 연장근로
+	HolidayHours         float64 // 휴일근로 8시간 이내
+	HolidayOvertimeHours float64 // 휴일근로 8시간 초과
+	NightHours           float64 // 야간근로 (22:00~06:00)
+}
+
+// loadAttendedTime sums the attendance of an employee within the period. Holidays are the weekly
+// holiday of the work schedule (Sunday without one) and days recorded with the holiday status.
+// Overtime, which includes all work on an off day, is paid only once approved.
+func loadAttendedTime(db dbtx, employeeID int, start, end time.Time) (attendedTime, error) {
+	var worked attendedTime
+
+	schedule, err := employeeWorkSchedule(db, employeeID)
+	if err != nil {
+		return worked, err
+	}
+	weeklyHoliday := time.Sunday
+	if schedule != nil {
+		weeklyHoliday = schedule.WeeklyHoliday
+	}
+
+	rows, err := db.Query(`
+		SELECT work_date, clock_in, clock_out, total_hours, overtime_hours, overtime_status, status
+		FROM attendance_logs
+		WHERE employee_id = ? AND work_date >= ? AND work_date <= ? AND total_hours > 0
+	`, employeeID, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return worked, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var log models.AttendanceLog
+		err := rows.Scan(&log.WorkDate, &log.ClockIn, &log.ClockOut, &log.TotalHours,
+			&log.OvertimeHours, &log.OvertimeStatus, &log.Status)
+		if err != nil {
+			return worked, err
+		}
+
+		total, overtime := log.TotalHours.Float64, log.OvertimeHours.Float64
+		paid := total
+		if log.OvertimeStatus != "approved" {
+			paid -= overtime
+		}
+
+		if log.Status == "holiday" || log.WorkDate.Weekday() == weeklyHoliday {
+			worked.HolidayHours += minFloat(paid, standardDailyHours)
+			worked.HolidayOvertimeHours += maxFloat(paid-standardDailyHours, 0)
+		} else {
+			worked.RegularHours += total - overtime
+			worked.Days++
+			if log.OvertimeStatus == "approved" {
+				worked.OvertimeHours += overtime
+			}
+		}
+
+		if log.ClockIn.Valid && log.ClockOut.Valid {
+			clockIn, errIn := time.Parse("15:04:05", log.ClockIn.String)
+			clockOut, errOut := time.Parse("15:04:05", log.ClockOut.String)
+			if errIn == nil && errOut == nil {
+				var scheduled *scheduledAttendance
+				if schedule != nil {
+					scheduled = &scheduledAttendance{Schedule: schedule, Day: scheduleDay(schedule, log.WorkDate.Weekday())}
+				}
+				worked.NightHours += minFloat(scheduled.nightHours(clockIn, clockOut), paid)
+			}
+		}
+	}
+	return worked, rows.Err()
+}
+
+// unpaidLeaveDays counts the approved unpaid leave of an employee within the period.
+// Leave spanning the period boundary is prorated by the calendar days inside the period.
+func unpaidLeaveDays(db dbtx, employeeID int, start, end time.Time) (float64, error) {
+	rows, err := db.Query(`
+		SELECT leave_type, start_date, end_date, days_requested FROM leave_requests
+		WHERE employee_id = ? AND status = 'approved'
+	`, employeeID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	days := 0.0
+	for rows.Next() {
+		var leave models.LeaveRequest
+		if err := rows.Scan(&leave.LeaveType, &leave.StartDate, &leave.EndDate, &leave.DaysRequested); err != nil {
+			return 0, err
+		}
+		if !unpaidLeaveTypes[leave.LeaveType] {
+			continue
+		}
+
+		leaveStart := leave.StartDate.Truncate(24 * time.Hour)
+		leaveEnd := leave.EndDate.Truncate(24 * time.Hour)
+		overlapStart, overlapEnd := leaveStart, leaveEnd
+		if overlapStart.Before(start) {
+			overlapStart = start
+		}
+		if overlapEnd.After(end) {
+			overlapEnd = end
+		}
+		if overlapEnd.Before(overlapStart) {
+			continue
+		}
+
+		total := leaveEnd.Sub(leaveStart).Hours()/24 + 1
+		overlap := overlapEnd.Sub(overlapStart).Hours()/24 + 1
+		days += leave.DaysRequested * overlap / total
+	}
+	return days, rows.Err()
+}
+
+// generatePayrollDrafts creates a draft payroll record for every active employee with an active
+// contract. The base salary and fixed allowances follow the contract; overtime, holiday and night
+// work and unpaid leave come from attendance and approved leave requests, and hourly and daily
+// staff are paid for the hours or days recorded in attendance. Employees without a contract are skipped.
+func generatePayrollDrafts(tx dbtx, run *models.PayrollRun) (int, []gin.H, error) {
+	// Every contract of the employee is loaded, latest start first, and the one covering the period end is used;
+	// renewals and replaced contracts would otherwise produce one draft per contract
+	rows, err := tx.Query(`
+		SELECT e.id, e.name, COALESCE(e.salary_type, 'monthly'), c.id, c.base_salary, c.start_date, c.end_date,
+		       c.work_schedule_id, c.working_hours, c.work_days, c.break_time
+		FROM employees e
+		LEFT JOIN employment_contracts c ON c.employee_id = e.id
+		WHERE e.status = 'active'
+		ORDER BY e.id, c.start_date DESC, c.id DESC
+	`)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	type payee struct {
+		id           int
+		name         string
+		salaryType   string
+		contractID   sql.NullInt64
+		baseSalary   sql.NullFloat64
+		scheduleID   sql.NullInt64
+		workingHours sql.NullString
+		workDays     sql.NullString
+		breakTime    sql.NullString
+	}
+	periodEnd := run.PeriodEnd.Format("2006-01-02")
+	var payees []payee
+	for rows.Next() {
+		var p payee
+		var startDate, endDate sql.NullTime
+		if err := rows.Scan(&p.id, &p.name, &p.salaryType, &p.contractID, &p.baseSalary, &startDate, &endDate,
+			&p.scheduleID, &p.workingHours, &p.workDays, &p.breakTime); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		covers := startDate.Valid && startDate.Time.Format("2006-01-02") <= periodEnd &&
+			(!endDate.Valid || endDate.Time.Format("2006-01-02") >= periodEnd)
+		if !covers {
+			p.contractID, p.baseSalary = sql.NullInt64{}, sql.NullFloat64{}
+		}
+		if n := len(payees); n > 0 && payees[n-1].id == p.id {
+			if !payees[n-1].contractID.Valid && p.contractID.Valid {
+				payees[n-1] = p
+			}
+			continue
+		}
+		payees = append(payees, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	table, err := loadWithholdingTable(tx, run.PeriodEnd)
+	if err != nil {
+		return 0, nil, err
+	}
+	rates, err := loadInsuranceRates(tx, run.PeriodEnd)
+	if err != nil {
+		return 0, nil, err
+	}
+	limits, err := loadContributionLimits(tx, run.PeriodEnd)
+	if err != nil {
+		return 0, nil, err
+	}
+	minimumWage, err := loadMinimumWage(tx, run.PeriodEnd.Year())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	runID := sql.NullInt64{Int64: int64(run.ID), Valid: true}
+	created := 0
+	skipped := []gin.H{}
+	for _, p := range payees {
+		if !p.contractID.Valid {
+			skipped = append(skipped, gin.H{"employee_id": p.id, "employee_name": p.name, "reason": "no contract covering the period end"})
+			continue
+		}
+
+		lines, err := contractAllowanceLines(tx, p.contractID.Int64)
+		if err != nil {
+			return 0, nil, err
+		}
+		leaveDays, err := unpaidLeaveDays(tx, p.id, run.PeriodStart, run.PeriodEnd)
+		if err != nil {
+			return 0, nil, err
+		}
+		bases, err := loadContributionBases(tx, p.id, run.PeriodEnd)
+		if err != nil {
+			return 0, nil, err
+		}
+		dependents, withholdingRate, err := employeeWithholding(tx, p.id)
+		if err != nil {
+			return 0, nil, err
+		}
+		monthlyHours, err := contractMonthlyHours(tx, p.scheduleID, p.workingHours.String, p.workDays.String, p.breakTime.String)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		calculator := &PayrollCalculator{
+			SalaryType:         p.salaryType,
+			BaseSalary:         models.Won(p.baseSalary.Float64),
+			MonthlyHours:       monthlyHours,
+			AllowanceItems:     lines,
+			UnpaidLeaveDays:    leaveDays,
+			InsuranceRates:     rates,
+			ContributionBases:  bases,
+			ContributionLimits: limits,
+			MinimumWage:        minimumWage,
+			WithholdingTable:   table,
+			Dependents:         dependents,
+			WithholdingRate:    withholdingRate,
+		}
+		if err := calculator.applyAttendance(tx, p.id, run.PeriodStart, run.PeriodEnd, true); err != nil {
+			return 0, nil, err
+		}
+		if err := calculator.applyYearEndSettlement(tx, p.id, run.PeriodStart, 0); err != nil {
+			return 0, nil, err
+		}
+		if err := calculator.applyPayrollAdjustments(tx, p.id, 0); err != nil {
+			return 0, nil, err
+		}
+		if _, err := insertPayrollRecord(tx, p.id, runID, run.PeriodStart, run.PeriodEnd, calculator); err != nil {
+			return 0, nil, err
+		}
+		created++
+	}
+
+	return created, skipped, nil
+}
+
+// deletePayrollRunRecords removes the generated records of a run
+func deletePayrollRunRecords(tx dbtx, runID int) error {
+	if err := releaseYearEndSettlements(tx, "payroll_run_id = ?", runID); err != nil {
+		return err
+	}
+	if err := releasePayrollAdjustments(tx, "payroll_run_id = ?", runID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+		DELETE FROM payroll_allowance_items
+		WHERE payroll_id IN (SELECT id FROM payroll_records WHERE payroll_run_id = ?)
+	`, runID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM payroll_records WHERE payroll_run_id = ?", runID)
+	return err
+}
+
+// payrollDiff compares the amounts of a record with the employee's record of the previous month
+func payrollDiff(previous, current *models.PayrollRecord) gin.H {
+	fields := []struct {
+		name              string
+		previous, current models.Money
+	}{
+		{"base_salary", previous.BaseSalary, current.BaseSalary},
+		{"overtime_pay", previous.OvertimePay, current.OvertimePay},
+		{"holiday_pay", previous.HolidayPay, current.HolidayPay},
+		{"night_pay", previous.NightPay, current.NightPay},
+		{"weekly_holiday_pay", previous.WeeklyHolidayPay, current.WeeklyHolidayPay},
+		{"allowances", previous.Allowances, current.Allowances},
+		{"unpaid_leave_deduction", previous.UnpaidLeaveDeduction, current.UnpaidLeaveDeduction},
+		{"gross_pay", previous.GrossPay, current.GrossPay},
+		{"total_deductions", previous.TotalDeductions, current.TotalDeductions},
+		{"net_pay", previous.NetPay, current.NetPay},
+	}
+
+	diff := gin.H{}
+	for _, f := range fields {
+		diff[f.name] = gin.H{"previous": f.previous, "current": f.current, "change": f.current - f.previous}
+	}
+	return diff
+}
+
+// payrollRunResponse loads a run with its records, totals and the change of each record
+// against the employee's payroll of the previous month
+func payrollRunResponse(id int) (gin.H, error) {
+	run, err := loadPayrollRun(database.DB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.Query(payrollSelectQuery+" WHERE p.payroll_run_id = ? ORDER BY e.name", id)
+	if err != nil {
+		return nil, err
+	}
+
+	type runRecord struct {
+		payroll        *models.PayrollRecord
+		employeeName   string
+		employeeNumber string
+	}
+	var records []runRecord
+	for rows.Next() {
+		payroll, employeeName, employeeNumber, err := scanPayrollWithEmployee(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		records = append(records, runRecord{payroll, employeeName, employeeNumber})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	previousStart := run.PeriodStart.AddDate(0, -1, 0)
+	previousEnd := run.PeriodStart.AddDate(0, 0, -1)
+
+	totals := map[string]models.Money{}
+	payrolls := []gin.H{}
+	for _, r := range records {
+		totals["gross_pay"] += r.payroll.GrossPay
+		totals["total_deductions"] += r.payroll.TotalDeductions
+		totals["adjustment_pay"] += r.payroll.AdjustmentPay
+		totals["net_pay"] += r.payroll.NetPay
+
+		entry := gin.H{
+			"payroll":         r.payroll,
+			"employee_name":   r.employeeName,
+			"employee_number": r.employeeNumber,
+			"diff":            nil,
+		}
+
+		previous, _, _, err := scanPayrollWithEmployee(database.DB.QueryRow(payrollSelectQuery+`
+			WHERE p.employee_id = ? AND p.pay_period_start >= ? AND p.pay_period_start <= ?
+			ORDER BY p.id DESC LIMIT 1
+		`, r.payroll.EmployeeID, previousStart, previousEnd))
+		if err == nil {
+			entry["diff"] = payrollDiff(previous, r.payroll)
+		} else if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		payrolls = append(payrolls, entry)
+	}
+
+	violations, err := payrollRunMinimumWageViolations(database.DB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"run":                     run,
+		"payrolls":                payrolls,
+		"employee_count":          len(records),
+		"totals":                  totals,
+		"minimum_wage_violations": violations,
+	}, nil
+}
+
+func GetPayrollRuns(c *gin.Context) {
+	rows, err := database.DB.Query(payrollRunSelectQuery + " ORDER BY pay_period DESC")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	runs := []*models.PayrollRun{}
+	for rows.Next() {
+		run, err := scanPayrollRun(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan payroll run"})
+			return
+		}
+		runs = append(runs, run)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+func GetPayrollRun(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	runData, err := payrollRunResponse(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, runData)
+}
+
+// CreatePayrollRun opens the payroll of a pay period and generates a draft for every active employee
+func CreatePayrollRun(c *gin.Context) {
+	var req CreatePayrollRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	periodStart, err := time.Parse("2006-01", req.PayPeriod)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay period format (YYYY-MM)"})
+		return
+	}
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	var payDate sql.NullTime
+	if req.PayDate != "" {
+		date, err := time.Parse("2006-01-02", req.PayDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay date format (YYYY-MM-DD)"})
+			return
+		}
+		payDate = sql.NullTime{Time: date, Valid: true}
+	}
+
+	var existingID int
+	err = database.DB.QueryRow("SELECT id FROM payroll_runs WHERE pay_period = ?", req.PayPeriod).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll run already exists for this pay period", "run_id": existingID})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO payroll_runs (pay_period, period_start, period_end, pay_date, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, req.PayPeriod, periodStart, periodEnd, payDate, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payroll run"})
+		return
+	}
+	runID, _ := result.LastInsertId()
+
+	run, err := loadPayrollRun(tx, int(runID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load payroll run"})
+		return
+	}
+
+	created, skipped, err := generatePayrollDrafts(tx, run)
+	if err == errNoWithholdingTable || err == errNoInsuranceRates {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate payroll drafts"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	runData, err := payrollRunResponse(int(runID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created payroll run"})
+		return
+	}
+	runData["created"] = created
+	runData["skipped"] = skipped
+
+	c.JSON(http.StatusCreated, runData)
+}
+
+// RegeneratePayrollRun replaces the drafts of a run with freshly generated ones,
+// picking up contract, overtime and leave changes made since
+func RegeneratePayrollRun(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	run, err := loadPayrollRun(tx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if run.Status != "draft" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft payroll runs can be regenerated"})
+		return
+	}
+
+	if err := deletePayrollRunRecords(tx, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payroll drafts"})
+		return
+	}
+
+	created, skipped, err := generatePayrollDrafts(tx, run)
+	if err == errNoWithholdingTable || err == errNoInsuranceRates {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate payroll drafts"})
+		return
+	}
+
+	if _, err := tx.Exec("UPDATE payroll_runs SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll run"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	runData, err := payrollRunResponse(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payroll run"})
+		return
+	}
+	runData["created"] = created
+	runData["skipped"] = skipped
+
+	c.JSON(http.StatusOK, runData)
+}
+
+// transitionPayrollRun moves a run from one status to the next. apply makes any further
+// changes that belong to the transition within the same transaction.
+func transitionPayrollRun(c *gin.Context, from, to string, apply func(tx *sql.Tx, run *models.PayrollRun) error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	run, err := loadPayrollRun(tx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if run.Status != from {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Payroll run must be " + from + " to become " + to,
+			"status": run.Status,
+		})
+		return
+	}
+
+	_, err = tx.Exec("UPDATE payroll_runs SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", to, id)
+	if err == nil && apply != nil {
+		err = apply(tx, run)
+	}
+	if wageErr, ok := err.(*minimumWageError); ok {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": wageErr.Error(), "violations": wageErr.violations})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll run"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	runData, err := payrollRunResponse(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runData)
+}
+
+// SubmitPayrollRun sends the drafts of a run for review
+func SubmitPayrollRun(c *gin.Context) {
+	transitionPayrollRun(c, "draft", "review", nil)
+}
+
+// ReturnPayrollRun sends a run under review back to draft for corrections
+func ReturnPayrollRun(c *gin.Context) {
+	transitionPayrollRun(c, "review", "draft", nil)
+}
+
+// ApprovePayrollRun approves a reviewed run. Its records are locked from then on, so pay below
+// the minimum wage has to be corrected first. The check reads the records in the approving
+// transaction, after the status change, so a run regenerated meanwhile cannot slip past it.
+func ApprovePayrollRun(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	transitionPayrollRun(c, "review", "approved", func(tx *sql.Tx, run *models.PayrollRun) error {
+		violations, err := payrollRunMinimumWageViolations(tx, run.ID)
+		if err != nil {
+			return err
+		}
+		if len(violations) > 0 {
+			return &minimumWageError{violations: violations}
+		}
+
+		_, err = tx.Exec(`
+			UPDATE payroll_runs SET approved_by = ?, approved_at = CURRENT_TIMESTAMP WHERE id = ?
+		`, userID, run.ID)
+		return err
+	})
+}
+
+// MarkPayrollRunPaid records the payment of an approved run on its pay date, or today. Records
+// already paid by a transfer batch keep their transfer date.
+func MarkPayrollRunPaid(c *gin.Context) {
+	transitionPayrollRun(c, "approved", "paid", func(tx *sql.Tx, run *models.PayrollRun) error {
+		payDate := run.PayDate.Time
+		if !run.PayDate.Valid {
+			payDate = time.Now().Truncate(24 * time.Hour)
+		}
+
+		_, err := tx.Exec(`
+			UPDATE payroll_runs SET pay_date = ?, paid_at = CURRENT_TIMESTAMP WHERE id = ?
+		`, payDate, run.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE payroll_records SET is_paid = 1, pay_date = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE payroll_run_id = ? AND is_paid = 0
+		`, payDate, run.ID)
+		return err
+	})
+}
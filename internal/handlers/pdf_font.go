@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// pdfFont is the family the Korean TrueType font is registered under; the core PDF fonts such as Arial have no Hangul glyphs
+const pdfFont = "korean"
+
+// Default font files, relative to the working directory; PDF_FONT_PATH and PDF_BOLD_FONT_PATH override them
+const (
+	defaultPDFFontPath     = "fonts/NanumGothic.ttf"
+	defaultPDFBoldFontPath = "fonts/NanumGothicBold.ttf"
+)
+
+// pdfFontError is a Korean font that could not be read
+type pdfFontError struct {
+	path string
+}
+
+func (e *pdfFontError) Error() string {
+	return "Korean PDF font not found at " + e.path + "; install a UTF-8 TrueType font such as NanumGothic or set PDF_FONT_PATH"
+}
+
+// newKoreanPDF creates an A4 document with the Korean font registered as pdfFont in regular and bold.
+// Without a bold font file the regular one is used for bold text.
+func newKoreanPDF(orientation string) (*gofpdf.Fpdf, error) {
+	regularPath := os.Getenv("PDF_FONT_PATH")
+	if regularPath == "" {
+		regularPath = defaultPDFFontPath
+	}
+	regular, err := os.ReadFile(regularPath)
+	if err != nil {
+		return nil, &pdfFontError{path: regularPath}
+	}
+
+	boldPath := os.Getenv("PDF_BOLD_FONT_PATH")
+	if boldPath == "" {
+		boldPath = defaultPDFBoldFontPath
+	}
+	bold, err := os.ReadFile(boldPath)
+	if err != nil {
+		bold = regular
+	}
+
+	pdf := gofpdf.New(orientation, "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", regular)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", bold)
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+	return pdf, nil
+}
+
+// respondPDFError reports a failure to render a PDF, naming the font when it is missing
+func respondPDFError(c *gin.Context, err error) {
+	if fontErr, ok := err.(*pdfFontError); ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fontErr.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF"})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+)
+
+// Environment variables holding the keys; each kind of personal data has its own key so one leak does not expose the other
+const (
+	accountKeyEnv = "ACCOUNT_ENCRYPTION_KEY"
+	piiKeyEnv     = "PII_ENCRYPTION_KEY"
+)
+
+// encryptionKeyError is returned instead of encrypting with a default key
+type encryptionKeyError struct {
+	env string
+}
+
+func (e *encryptionKeyError) Error() string {
+	return "encryption key (" + e.env + ") is not configured"
+}
+
+var errInvalidCiphertext = errors.New("invalid encrypted value")
+
+// fieldCipher builds the AES-256-GCM cipher for the key in the environment variable
+func fieldCipher(env string) (cipher.AEAD, error) {
+	key := os.Getenv(env)
+	if key == "" {
+		return nil, &encryptionKeyError{env: env}
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealField encrypts the value, returning nonce and ciphertext in base64
+func sealField(env, value string) (string, error) {
+	aead, err := fieldCipher(env)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+func openField(env, encrypted string) (string, error) {
+	aead, err := fieldCipher(env)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(data) < aead.NonceSize() {
+		return "", errInvalidCiphertext
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// encryptPII seals personal identifiers such as the 주민등록번호 with PII_ENCRYPTION_KEY
+func encryptPII(value string) (string, error) {
+	return sealField(piiKeyEnv, value)
+}
+
+func decryptPII(encrypted string) (string, error) {
+	return openField(piiKeyEnv, encrypted)
+}
+
+// encryptAccountNumber seals a pay account number with ACCOUNT_ENCRYPTION_KEY
+func encryptAccountNumber(accountNumber string) (string, error) {
+	return sealField(accountKeyEnv, accountNumber)
+}
+
+func decryptAccountNumber(encrypted string) (string, error) {
+	return openField(accountKeyEnv, encrypted)
+}
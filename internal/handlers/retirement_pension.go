@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"database/sql"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retirementPensionTypes are the 퇴직급여 제도 an employee can be under: 퇴직금, 확정급여형(DB)
+// and 확정기여형(DC) 퇴직연금
+var retirementPensionTypes = map[string]bool{"severance": true, "db": true, "dc": true}
+
+type RetirementPensionRequest struct {
+	PensionType string `json:"pension_type" binding:"required"` // severance, db, dc
+}
+
+type CalculateRetirementPensionContributionsRequest struct {
+	PlanYear int `json:"plan_year" binding:"required"`
+}
+
+type RetirementPensionPaymentRequest struct {
+	PaidAmount *float64 `json:"paid_amount" binding:"required"` // 0 records that nothing was paid
+	PaidDate   string   `json:"paid_date" binding:"required"`
+}
+
+// pensionYear is the DC contribution owed for one year of service against what was paid
+type pensionYear struct {
+	Year           int          `json:"year"`
+	AnnualWages    models.Money `json:"annual_wages"`
+	RequiredAmount models.Money `json:"required_amount"`
+	PaidAmount     models.Money `json:"paid_amount"`
+}
+
+// employeeRetirementPensionType returns the employee's 퇴직급여 제도, severance pay by default
+func employeeRetirementPensionType(db dbtx, employeeID int) (string, error) {
+	var pensionType string
+	err := db.QueryRow(`
+		SELECT COALESCE(retirement_pension_type, 'severance') FROM employees WHERE id = ?
+	`, employeeID).Scan(&pensionType)
+	return pensionType, err
+}
+
+// annualWages is the 연간 임금총액 of the employee: the gross pay of the payroll periods starting in
+// the year, with the adjustments of them settled so far
+func annualWages(db dbtx, employeeID, year int) (models.Money, error) {
+	from := strconv.Itoa(year) + "-01-01"
+	until := strconv.Itoa(year+1) + "-01-01"
+
+	var wages models.Money
+	err := db.QueryRow(`
+		SELECT COALESCE(SUM(p.gross_pay), 0) +
+		       COALESCE((SELECT SUM(a.gross_pay) FROM payroll_adjustments a
+		                 JOIN payroll_records o ON o.id = a.original_payroll_id
+		                 WHERE a.employee_id = ? AND a.status = 'applied'
+		                   AND o.pay_period_start >= ? AND o.pay_period_start < ?), 0)
+		FROM payroll_records p
+		WHERE p.employee_id = ? AND p.pay_period_start >= ? AND p.pay_period_start < ?
+	`, employeeID, from, until, employeeID, from, until).Scan(&wages)
+	return wages, err
+}
+
+// dcPensionYears lists the DC contribution owed for each year from the first to the last, a twelfth
+// of the year's wages, with what was recorded as paid
+func dcPensionYears(db dbtx, employeeID, firstYear, lastYear int) ([]pensionYear, error) {
+	paid := make(map[int]models.Money)
+	rows, err := db.Query(`
+		SELECT plan_year, paid_amount FROM retirement_pension_contributions
+		WHERE employee_id = ? AND plan_year >= ? AND plan_year <= ?
+	`, employeeID, firstYear, lastYear)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var year int
+		var amount models.Money
+		if err := rows.Scan(&year, &amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		paid[year] = amount
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	years := []pensionYear{}
+	for year := firstYear; year <= lastYear; year++ {
+		wages, err := annualWages(db, employeeID, year)
+		if err != nil {
+			return nil, err
+		}
+		line := pensionYear{Year: year, AnnualWages: wages, RequiredAmount: wages.MulRate(1.0 / 12), PaidAmount: paid[year]}
+		if line.AnnualWages == 0 && line.PaidAmount == 0 {
+			continue
+		}
+		years = append(years, line)
+	}
+	return years, nil
+}
+
+const retirementPensionContributionSelectQuery = `
+	SELECT c.id, c.employee_id, e.name, c.plan_year, c.annual_wages, c.required_amount, c.paid_amount,
+	       c.paid_date, c.created_at, c.updated_at
+	FROM retirement_pension_contributions c
+	JOIN employees e ON e.id = c.employee_id`
+
+func scanRetirementPensionContribution(row rowScanner) (*models.RetirementPensionContribution, error) {
+	var contribution models.RetirementPensionContribution
+	err := row.Scan(&contribution.ID, &contribution.EmployeeID, &contribution.EmployeeName, &contribution.PlanYear,
+		&contribution.AnnualWages, &contribution.RequiredAmount, &contribution.PaidAmount, &contribution.PaidDate,
+		&contribution.CreatedAt, &contribution.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+// GetEmployeeRetirementPension returns the employee's 퇴직급여 제도 and, under DC, the contributions
+// owed and paid for each year of payroll
+func GetEmployeeRetirementPension(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	pensionType, err := employeeRetirementPensionType(database.DB, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	response := gin.H{"employee_id": id, "pension_type": pensionType}
+	if pensionType == "dc" {
+		var hireDate time.Time
+		err := database.DB.QueryRow("SELECT hire_date FROM employees WHERE id = ?", id).Scan(&hireDate)
+		var years []pensionYear
+		if err == nil {
+			years, err = dcPensionYears(database.DB, id, hireDate.Year(), time.Now().Year())
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		response["contributions"] = years
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateEmployeeRetirementPension sets the employee's 퇴직급여 제도
+func UpdateEmployeeRetirementPension(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req RetirementPensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !retirementPensionTypes[req.PensionType] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pension type must be severance, db or dc"})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE employees SET retirement_pension_type = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, req.PensionType, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update retirement pension"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"employee_id": id, "pension_type": req.PensionType})
+}
+
+// GetRetirementPensionContributions lists the DC contributions of a plan year (plan_year, default
+// this year) or of one employee (employee_id)
+func GetRetirementPensionContributions(c *gin.Context) {
+	query := retirementPensionContributionSelectQuery + " WHERE 1=1"
+	var args []interface{}
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		query += " AND c.employee_id = ?"
+		args = append(args, employeeID)
+	} else {
+		planYear, err := strconv.Atoi(c.DefaultQuery("plan_year", strconv.Itoa(time.Now().Year())))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan year"})
+			return
+		}
+		query += " AND c.plan_year = ?"
+		args = append(args, planYear)
+	}
+
+	rows, err := database.DB.Query(query+" ORDER BY c.plan_year DESC, e.name", args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	contributions := []*models.RetirementPensionContribution{}
+	var required, paid models.Money
+	for rows.Next() {
+		contribution, err := scanRetirementPensionContribution(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan contribution"})
+			return
+		}
+		required += contribution.RequiredAmount
+		paid += contribution.PaidAmount
+		contributions = append(contributions, contribution)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"contributions":   contributions,
+		"required_amount": required,
+		"paid_amount":     paid,
+		"shortfall":       maxMoney(required-paid, 0),
+	})
+}
+
+// CalculateRetirementPensionContributions works out the contribution of the plan year for every DC
+// employee paid in it, a twelfth of the year's wages. Amounts already paid are kept.
+func CalculateRetirementPensionContributions(c *gin.Context) {
+	var req CalculateRetirementPensionContributionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT DISTINCT e.id FROM employees e
+		JOIN payroll_records p ON p.employee_id = e.id
+		WHERE e.retirement_pension_type = 'dc' AND p.pay_period_start >= ? AND p.pay_period_start < ?
+		ORDER BY e.id
+	`, strconv.Itoa(req.PlanYear)+"-01-01", strconv.Itoa(req.PlanYear+1)+"-01-01")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	var employeeIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		employeeIDs = append(employeeIDs, id)
+	}
+	rows.Close()
+
+	for _, employeeID := range employeeIDs {
+		wages, err := annualWages(tx, employeeID, req.PlanYear)
+		if err == nil {
+			_, err = tx.Exec(`
+				INSERT INTO retirement_pension_contributions (employee_id, plan_year, annual_wages, required_amount)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT(employee_id, plan_year) DO UPDATE SET
+					annual_wages = excluded.annual_wages, required_amount = excluded.required_amount,
+					updated_at = CURRENT_TIMESTAMP
+			`, employeeID, req.PlanYear, wages, wages.MulRate(1.0/12))
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate contribution"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"plan_year": req.PlanYear, "calculated": len(employeeIDs)})
+}
+
+// RecordRetirementPensionContribution records the amount paid into the DC account for the year
+func RecordRetirementPensionContribution(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contribution ID"})
+		return
+	}
+
+	var req RetirementPensionPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	paidDate, err := time.Parse("2006-01-02", req.PaidDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paid date format (YYYY-MM-DD)"})
+		return
+	}
+	if *req.PaidAmount < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Paid amount cannot be negative"})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE retirement_pension_contributions SET paid_amount = ?, paid_date = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, models.Won(*req.PaidAmount), paidDate, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record contribution"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contribution not found"})
+		return
+	}
+
+	contribution, err := scanRetirementPensionContribution(database.DB.QueryRow(retirementPensionContributionSelectQuery+" WHERE c.id = ?", id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load contribution"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contribution)
+}
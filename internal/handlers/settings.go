@@ -3,6 +3,7 @@ package handlers
 import (
 	"labor-management-system/database"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -122,4 +123,31 @@ func SetSettingValue(key, value, description string) error {
 	`, key, value, description)
 	
 	return err
-}
\ No newline at end of file
+}
+// loadSettings reads the given settings; missing keys are left out of the map
+func loadSettings(db dbtx, keys ...string) (map[string]string, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(keys)), ",")
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+
+	rows, err := db.Query(`
+		SELECT setting_key, COALESCE(setting_value, '') FROM system_settings
+		WHERE setting_key IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		settings[key] = value
+	}
+	return settings, rows.Err()
+}
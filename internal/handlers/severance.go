@@ -0,0 +1,613 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serviceYearsDeductionBrackets are the 근속연수공제 by years of service (소득세법 제48조), highest
+// first. The rate is the deduction per year over the bracket.
+var serviceYearsDeductionBrackets = []excessPayBracket{
+	{20, 40000000, 3000000},
+	{10, 15000000, 2500000},
+	{5, 5000000, 2000000},
+	{0, 0, 1000000},
+}
+
+// convertedPayDeductionBrackets are the 환산급여공제 by converted pay (소득세법 제48조), highest first
+var convertedPayDeductionBrackets = []excessPayBracket{
+	{300000000, 151700000, 0.35},
+	{100000000, 61700000, 0.45},
+	{70000000, 45200000, 0.55},
+	{8000000, 8000000, 0.60},
+	{0, 0, 1.00},
+}
+
+type SeveranceSettlementRequest struct {
+	EmployeeID     int    `json:"employee_id" binding:"required"`
+	RetirementDate string `json:"retirement_date" binding:"required"` // 퇴직일 (마지막 근무일 다음 날)
+}
+
+type PaySeveranceSettlementRequest struct {
+	PayDate string `json:"pay_date"` // 생략 시 오늘
+}
+
+// excludedLeave is approved leave left out of the service period
+type excludedLeave struct {
+	LeaveID   int    `json:"leave_id"`
+	LeaveType string `json:"leave_type"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Days      int    `json:"days"` // 근속기간에 속하고 앞선 휴직과 겹치지 않는 일수
+}
+
+// severanceCalculation is a severance settlement with the working behind it
+type severanceCalculation struct {
+	models.SeveranceSettlement
+	Eligible       bool            `json:"eligible"` // 계속근로기간 1년 이상
+	ExcludedLeaves []excludedLeave `json:"excluded_leaves"`
+	AverageWage    *averageWage    `json:"average_wage,omitempty"`
+	PensionYears   []pensionYear   `json:"pension_years,omitempty"` // 확정기여형 연도별 부담금
+	Steps          []string        `json:"steps"`
+}
+
+// severanceExcludedLeaves lists the approved leave of the types excluded from the service period
+// (setting severance_excluded_leave_types) that falls between start and end. Overlapping leave is
+// excluded once: each leave's Days leaves out the days an earlier one already covers.
+func severanceExcludedLeaves(db dbtx, employeeID int, start, end time.Time) ([]excludedLeave, error) {
+	leaves := []excludedLeave{}
+	settings, err := loadSettings(db, "severance_excluded_leave_types")
+	if err != nil {
+		return nil, err
+	}
+	var leaveTypes []interface{}
+	for _, leaveType := range strings.Split(settings["severance_excluded_leave_types"], ",") {
+		if leaveType = strings.TrimSpace(leaveType); leaveType != "" {
+			leaveTypes = append(leaveTypes, leaveType)
+		}
+	}
+	if len(leaveTypes) == 0 {
+		return leaves, nil
+	}
+
+	rows, err := db.Query(`
+		SELECT id, leave_type, start_date, end_date FROM leave_requests
+		WHERE employee_id = ? AND status = 'approved' AND end_date >= ? AND start_date <= ?
+		  AND leave_type IN (`+strings.TrimSuffix(strings.Repeat("?,", len(leaveTypes)), ",")+`)
+		ORDER BY start_date
+	`, append([]interface{}{employeeID, start, end}, leaveTypes...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coveredUntil time.Time
+	for rows.Next() {
+		var leave excludedLeave
+		var from, until time.Time
+		if err := rows.Scan(&leave.LeaveID, &leave.LeaveType, &from, &until); err != nil {
+			return nil, err
+		}
+		leave.StartDate = from.Format("2006-01-02")
+		leave.EndDate = until.Format("2006-01-02")
+		if from.Before(start) {
+			from = start
+		}
+		if until.After(end) {
+			until = end
+		}
+		if !coveredUntil.IsZero() && !from.After(coveredUntil) {
+			from = coveredUntil.AddDate(0, 0, 1)
+		}
+		if !from.After(until) {
+			leave.Days = daysBetween(from, until)
+			coveredUntil = until
+		}
+		leaves = append(leaves, leave)
+	}
+	return leaves, rows.Err()
+}
+
+// taxServiceYears counts the years of service for the 퇴직소득세, a part year counting as a whole
+// one, after taking out the excluded days
+func taxServiceYears(start, end time.Time, excludedDays int) int {
+	end = end.AddDate(0, 0, -excludedDays)
+	years := 0
+	for !start.AddDate(years, 0, 0).After(end) {
+		years++
+	}
+	return years
+}
+
+// calculateRetirementIncomeTax works out the 퇴직소득세 on the retirement income (소득세법 제48조,
+// 제55조): the 근속연수공제 is taken off, the rest is converted to a yearly amount (환산급여), the
+// 환산급여공제 is taken off that, and the basic rates are applied and brought back over the years of
+// service. The tax withheld drops amounts under 10 won, as 국고금 관리법 제47조 requires of
+// national revenue, and the 지방소득세 is a tenth of it, likewise truncated.
+func calculateRetirementIncomeTax(s *models.SeveranceSettlement) {
+	const localTaxRate = 0.1
+
+	s.ServiceYearsDeduction, s.ConvertedPay, s.ConvertedPayDeduction, s.TaxBase = 0, 0, 0, 0
+	s.ConvertedTax, s.IncomeTax, s.LocalTax = 0, 0, 0
+	if s.ServiceYears < 1 || s.RetirementIncome <= 0 {
+		return
+	}
+
+	income := s.RetirementIncome.Float64()
+	years := float64(s.ServiceYears)
+	s.ServiceYearsDeduction = models.TruncateWon(math.Min(bracketAmount(serviceYearsDeductionBrackets, years), income))
+	s.ConvertedPay = models.TruncateWon((income - s.ServiceYearsDeduction.Float64()) * 12 / years)
+	convertedPay := s.ConvertedPay.Float64()
+	s.ConvertedPayDeduction = models.TruncateWon(math.Min(bracketAmount(convertedPayDeductionBrackets, convertedPay), convertedPay))
+	s.TaxBase = maxMoney(s.ConvertedPay-s.ConvertedPayDeduction, 0)
+	s.ConvertedTax = models.TruncateWon(bracketAmount(incomeTaxRates, s.TaxBase.Float64()))
+	s.IncomeTax = s.ConvertedTax.MulRate(years / 12).Truncate(10)
+	s.LocalTax = s.IncomeTax.MulRate(localTaxRate).Truncate(10)
+}
+
+// calculateSeverance works out the settlement of the employee retiring on its retirement date under
+// the employee's current 퇴직급여 제도. The service period runs from the hire date to the day before
+// retirement, less the excluded leave; a year or more of it earns 30 days of average wage per year.
+// Under DC the benefit is the contributions, and what is still owed is paid into the account.
+func calculateSeverance(db dbtx, s *models.SeveranceSettlement) (*severanceCalculation, error) {
+	var hireDate time.Time
+	err := db.QueryRow(`
+		SELECT hire_date, COALESCE(retirement_pension_type, 'severance') FROM employees WHERE id = ?
+	`, s.EmployeeID).Scan(&hireDate, &s.PensionType)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ServiceStart = hireDate
+	s.ServiceEnd = s.RetirementDate.AddDate(0, 0, -1)
+	if s.ServiceEnd.Before(s.ServiceStart) {
+		return nil, &wageBasisError{"Retirement date must be after the hire date"}
+	}
+
+	calc := &severanceCalculation{}
+	if calc.ExcludedLeaves, err = severanceExcludedLeaves(db, s.EmployeeID, s.ServiceStart, s.ServiceEnd); err != nil {
+		return nil, err
+	}
+	s.ExcludedDays = 0
+	for _, leave := range calc.ExcludedLeaves {
+		s.ExcludedDays += leave.Days
+	}
+	s.ServiceDays = daysBetween(s.ServiceStart, s.ServiceEnd) - s.ExcludedDays
+	s.ServiceYears = taxServiceYears(s.ServiceStart, s.ServiceEnd, s.ExcludedDays)
+
+	step := fmt.Sprintf("근속기간 = %s ~ %s (%d일)", s.ServiceStart.Format("2006-01-02"), s.ServiceEnd.Format("2006-01-02"),
+		daysBetween(s.ServiceStart, s.ServiceEnd))
+	if s.ExcludedDays > 0 {
+		step += fmt.Sprintf(" - 제외 휴직 %d일 = %d일", s.ExcludedDays, s.ServiceDays)
+	}
+	calc.Steps = append(calc.Steps, step)
+
+	s.AverageDailyWage, s.SeverancePay, s.DCContributions, s.DCShortfall, s.RetirementIncome, s.CompanyPayment = 0, 0, 0, 0, 0, 0
+	calc.Eligible = s.ServiceDays >= 365
+	if !calc.Eligible {
+		calc.Steps = append(calc.Steps, "계속근로기간이 1년 미만이어서 퇴직급여 지급 대상이 아닙니다")
+		calculateRetirementIncomeTax(s)
+		calc.SeveranceSettlement = *s
+		return calc, nil
+	}
+
+	if calc.AverageWage, err = calculateAverageWage(db, s.EmployeeID, s.RetirementDate); err != nil {
+		return nil, err
+	}
+	s.AverageDailyWage = math.Floor(calc.AverageWage.AppliedDailyWage*100) / 100
+	s.SeverancePay = models.TruncateWon(calc.AverageWage.AppliedDailyWage * 30 * float64(s.ServiceDays) / 365)
+	calc.Steps = append(calc.Steps, fmt.Sprintf("퇴직금 = 1일 평균임금 %s x 30일 x %d일/365 = %s",
+		formatWage(calc.AverageWage.AppliedDailyWage), s.ServiceDays, formatWon(s.SeverancePay.Float64())))
+
+	switch s.PensionType {
+	case "dc":
+		if calc.PensionYears, err = dcPensionYears(db, s.EmployeeID, s.ServiceStart.Year(), s.ServiceEnd.Year()); err != nil {
+			return nil, err
+		}
+		var required models.Money
+		for _, year := range calc.PensionYears {
+			required += year.RequiredAmount
+			s.DCContributions += year.PaidAmount
+		}
+		s.DCShortfall = maxMoney(required-s.DCContributions, 0)
+		s.RetirementIncome = s.DCContributions + s.DCShortfall
+		s.CompanyPayment = s.DCShortfall
+		calc.Steps = append(calc.Steps, fmt.Sprintf("확정기여형 부담금 = 연간 임금총액의 1/12 합계 %s, 납입 %s, 미납 %s (퇴직 시 계정에 납입)",
+			formatWon(required.Float64()), formatWon(s.DCContributions.Float64()), formatWon(s.DCShortfall.Float64())))
+	default:
+		s.RetirementIncome = s.SeverancePay
+	}
+
+	calculateRetirementIncomeTax(s)
+	if s.RetirementIncome > 0 {
+		calc.Steps = append(calc.Steps,
+			fmt.Sprintf("근속연수 %d년, 근속연수공제 %s", s.ServiceYears, formatWon(s.ServiceYearsDeduction.Float64())),
+			fmt.Sprintf("환산급여 = (%s - %s) x 12 / %d = %s", formatWon(s.RetirementIncome.Float64()),
+				formatWon(s.ServiceYearsDeduction.Float64()), s.ServiceYears, formatWon(s.ConvertedPay.Float64())),
+			fmt.Sprintf("과세표준 = 환산급여 %s - 환산급여공제 %s = %s", formatWon(s.ConvertedPay.Float64()),
+				formatWon(s.ConvertedPayDeduction.Float64()), formatWon(s.TaxBase.Float64())),
+			fmt.Sprintf("퇴직소득세 = 환산산출세액 %s x %d / 12 = %s, 지방소득세 %s", formatWon(s.ConvertedTax.Float64()),
+				s.ServiceYears, formatWon(s.IncomeTax.Float64()), formatWon(s.LocalTax.Float64())))
+	}
+
+	switch s.PensionType {
+	case "severance":
+		s.CompanyPayment = s.SeverancePay - s.IncomeTax - s.LocalTax
+		calc.Steps = append(calc.Steps, "회사 지급액 = 퇴직금 - 퇴직소득세 - 지방소득세 = "+formatWon(s.CompanyPayment.Float64()))
+	case "db":
+		calc.Steps = append(calc.Steps, "확정급여형: 퇴직연금사업자가 퇴직금 이상의 급여를 지급하고 퇴직소득세를 원천징수합니다")
+	case "dc":
+		calc.Steps = append(calc.Steps, "확정기여형: 적립금은 퇴직연금사업자가 지급하며 운용수익에 따라 달라집니다")
+	}
+
+	calc.SeveranceSettlement = *s
+	return calc, nil
+}
+
+// severanceSettlementColumns are the calculated fields after employee_id and retirement_date, in the
+// order of severanceSettlementFields
+var severanceSettlementColumns = []string{
+	"pension_type", "service_start", "service_end", "excluded_days", "service_days", "average_daily_wage",
+	"severance_pay", "dc_contributions", "dc_shortfall", "retirement_income", "service_years",
+	"service_years_deduction", "converted_pay", "converted_pay_deduction", "tax_base", "converted_tax",
+	"income_tax", "local_tax", "company_payment",
+}
+
+func severanceSettlementFields(s *models.SeveranceSettlement) []interface{} {
+	return []interface{}{
+		&s.PensionType, &s.ServiceStart, &s.ServiceEnd, &s.ExcludedDays, &s.ServiceDays, &s.AverageDailyWage,
+		&s.SeverancePay, &s.DCContributions, &s.DCShortfall, &s.RetirementIncome, &s.ServiceYears,
+		&s.ServiceYearsDeduction, &s.ConvertedPay, &s.ConvertedPayDeduction, &s.TaxBase, &s.ConvertedTax,
+		&s.IncomeTax, &s.LocalTax, &s.CompanyPayment,
+	}
+}
+
+var severanceSettlementSelectQuery = `
+	SELECT s.id, s.employee_id, e.name, s.retirement_date, s.` + strings.Join(severanceSettlementColumns, ", s.") + `,
+	       s.status, s.pay_date, s.created_by, s.confirmed_at, s.created_at, s.updated_at
+	FROM severance_settlements s
+	JOIN employees e ON e.id = s.employee_id`
+
+func scanSeveranceSettlement(row rowScanner) (*models.SeveranceSettlement, error) {
+	var s models.SeveranceSettlement
+	dest := append([]interface{}{&s.ID, &s.EmployeeID, &s.EmployeeName, &s.RetirementDate}, severanceSettlementFields(&s)...)
+	dest = append(dest, &s.Status, &s.PayDate, &s.CreatedBy, &s.ConfirmedAt, &s.CreatedAt, &s.UpdatedAt)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func loadSeveranceSettlement(db dbtx, id int) (*models.SeveranceSettlement, error) {
+	return scanSeveranceSettlement(db.QueryRow(severanceSettlementSelectQuery+" WHERE s.id = ?", id))
+}
+
+// severanceSettlementValues are the calculated fields of the settlement as query arguments
+func severanceSettlementValues(s *models.SeveranceSettlement) []interface{} {
+	values := make([]interface{}, 0, len(severanceSettlementColumns))
+	for _, field := range severanceSettlementFields(s) {
+		switch v := field.(type) {
+		case *models.Money:
+			values = append(values, *v)
+		case *int:
+			values = append(values, *v)
+		case *float64:
+			values = append(values, *v)
+		case *string:
+			values = append(values, *v)
+		case *time.Time:
+			values = append(values, *v)
+		}
+	}
+	return values
+}
+
+// updateSeveranceSettlement stores the recalculated fields of a settlement
+func updateSeveranceSettlement(db dbtx, s *models.SeveranceSettlement) error {
+	_, err := db.Exec(`
+		UPDATE severance_settlements SET `+strings.Join(severanceSettlementColumns, " = ?, ")+` = ?,
+		       updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, append(severanceSettlementValues(s), s.ID)...)
+	return err
+}
+
+// respondSeveranceError reports a settlement that could not be calculated
+func respondSeveranceError(c *gin.Context, err error) {
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if basisErr, ok := err.(*wageBasisError); ok {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": basisErr.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate severance pay"})
+}
+
+// GetEmployeeSeveranceEstimate estimates the employee's severance pay and 퇴직소득세 for retiring on
+// the date (date, default today) without saving it
+func GetEmployeeSeveranceEstimate(c *gin.Context) {
+	employeeID, retirementDate, ok := wageBasisParams(c)
+	if !ok {
+		return
+	}
+
+	calc, err := calculateSeverance(database.DB, &models.SeveranceSettlement{EmployeeID: employeeID, RetirementDate: retirementDate})
+	if err != nil {
+		respondSeveranceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calc)
+}
+
+// GetSeveranceSettlements lists severance settlements, optionally by status and employee_id
+func GetSeveranceSettlements(c *gin.Context) {
+	query := severanceSettlementSelectQuery + " WHERE 1=1"
+	var args []interface{}
+	if status := c.Query("status"); status != "" {
+		query += " AND s.status = ?"
+		args = append(args, status)
+	}
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		query += " AND s.employee_id = ?"
+		args = append(args, employeeID)
+	}
+
+	rows, err := database.DB.Query(query+" ORDER BY s.retirement_date DESC, e.name", args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	settlements := []*models.SeveranceSettlement{}
+	for rows.Next() {
+		s, err := scanSeveranceSettlement(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan severance settlement"})
+			return
+		}
+		settlements = append(settlements, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settlements": settlements})
+}
+
+// CreateSeveranceSettlement calculates and saves the draft settlement of an employee retiring on the date
+func CreateSeveranceSettlement(c *gin.Context) {
+	var req SeveranceSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	retirementDate, err := time.Parse("2006-01-02", req.RetirementDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retirement date format (YYYY-MM-DD)"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	var existingID int
+	err = tx.QueryRow(`
+		SELECT id FROM severance_settlements WHERE employee_id = ? AND retirement_date = ?
+	`, req.EmployeeID, retirementDate).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A severance settlement already exists for this retirement", "settlement_id": existingID})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	s := &models.SeveranceSettlement{EmployeeID: req.EmployeeID, RetirementDate: retirementDate, Status: "draft"}
+	calc, err := calculateSeverance(tx, s)
+	if err != nil {
+		respondSeveranceError(c, err)
+		return
+	}
+	if !calc.Eligible {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":        "Employee has less than a year of service and is not entitled to severance pay",
+			"service_days": s.ServiceDays,
+		})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	result, err := tx.Exec(`
+		INSERT INTO severance_settlements (employee_id, retirement_date, created_by, `+strings.Join(severanceSettlementColumns, ", ")+`)
+		VALUES (?, ?, ?`+strings.Repeat(", ?", len(severanceSettlementColumns))+`)
+	`, append([]interface{}{s.EmployeeID, s.RetirementDate, userID}, severanceSettlementValues(s)...)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create severance settlement"})
+		return
+	}
+	id, _ := result.LastInsertId()
+
+	stored, err := loadSeveranceSettlement(tx, int(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created severance settlement"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	calc.SeveranceSettlement = *stored
+	c.JSON(http.StatusCreated, calc)
+}
+
+// GetSeveranceSettlement returns a settlement. A draft is recalculated against the current payroll
+// records and returned with its working.
+func GetSeveranceSettlement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid severance settlement ID"})
+		return
+	}
+
+	s, err := loadSeveranceSettlement(database.DB, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Severance settlement not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if s.Status != "draft" {
+		c.JSON(http.StatusOK, s)
+		return
+	}
+
+	calc, err := calculateSeverance(database.DB, s)
+	if err != nil {
+		respondSeveranceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calc)
+}
+
+// transitionSeveranceSettlement moves a settlement on from one status, applying the change of the
+// new one in the same transaction
+func transitionSeveranceSettlement(c *gin.Context, from, to string, apply func(tx *sql.Tx, s *models.SeveranceSettlement) error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid severance settlement ID"})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	s, err := loadSeveranceSettlement(tx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Severance settlement not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if s.Status != from {
+		c.JSON(http.StatusConflict, gin.H{"error": "Settlement must be " + from + " to become " + to, "status": s.Status})
+		return
+	}
+
+	if err := apply(tx, s); err != nil {
+		if _, ok := err.(*wageBasisError); ok {
+			respondSeveranceError(c, err)
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update severance settlement"})
+		}
+		return
+	}
+	_, err = tx.Exec("UPDATE severance_settlements SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", to, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update severance settlement"})
+		return
+	}
+
+	s, err = loadSeveranceSettlement(tx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load severance settlement"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// ConfirmSeveranceSettlement recalculates a draft settlement against the final payroll and fixes it
+func ConfirmSeveranceSettlement(c *gin.Context) {
+	transitionSeveranceSettlement(c, "draft", "confirmed", func(tx *sql.Tx, s *models.SeveranceSettlement) error {
+		calc, err := calculateSeverance(tx, s)
+		if err != nil {
+			return err
+		}
+		if !calc.Eligible {
+			return &wageBasisError{"Employee has less than a year of service and is not entitled to severance pay"}
+		}
+		if err := updateSeveranceSettlement(tx, s); err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE severance_settlements SET confirmed_at = CURRENT_TIMESTAMP WHERE id = ?", s.ID)
+		return err
+	})
+}
+
+// PaySeveranceSettlement records the payment of a confirmed settlement on the pay date, or today
+func PaySeveranceSettlement(c *gin.Context) {
+	var req PaySeveranceSettlementRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	payDate := time.Now().Truncate(24 * time.Hour)
+	if req.PayDate != "" {
+		var err error
+		if payDate, err = time.Parse("2006-01-02", req.PayDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay date format (YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	transitionSeveranceSettlement(c, "confirmed", "paid", func(tx *sql.Tx, s *models.SeveranceSettlement) error {
+		_, err := tx.Exec("UPDATE severance_settlements SET pay_date = ? WHERE id = ?", payDate, s.ID)
+		return err
+	})
+}
+
+// DeleteSeveranceSettlement removes a draft settlement
+func DeleteSeveranceSettlement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid severance settlement ID"})
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM severance_settlements WHERE id = ? AND status = 'draft'", id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete severance settlement"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		var status string
+		if err := database.DB.QueryRow("SELECT status FROM severance_settlements WHERE id = ?", id).Scan(&status); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only draft settlements can be deleted", "status": status})
+		} else {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Severance settlement not found"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Severance settlement deleted successfully"})
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"labor-management-system/internal/models"
+)
+
+// The expected amounts follow 소득세법 제48조 (근속연수공제, 환산급여공제) and 제55조 (기본세율), worked by
+// hand: deduct the 근속연수공제, convert to twelve years' worth (환산급여), deduct the 환산급여공제, tax
+// the rest and bring it back over the years of service.
+func TestCalculateRetirementIncomeTax(t *testing.T) {
+	tests := []struct {
+		name                   string
+		income                 models.Money
+		years                  int
+		wantServiceDeduction   models.Money
+		wantConvertedPay       models.Money
+		wantConvertedDeduction models.Money
+		wantTaxBase            models.Money
+		wantIncomeTax          models.Money
+		wantLocalTax           models.Money
+	}{
+		{
+			// 근속연수공제 5,000,000 + 5 x 2,000,000; 환산급여 85,000,000 x 12 / 10; 환산급여공제
+			// 61,700,000 + 2,000,000 x 45%; tax 840,000 + 25,400,000 x 15% = 4,650,000, x 10 / 12
+			name:                   "100 million won over 10 years",
+			income:                 100000000,
+			years:                  10,
+			wantServiceDeduction:   15000000,
+			wantConvertedPay:       102000000,
+			wantConvertedDeduction: 62600000,
+			wantTaxBase:            39400000,
+			wantIncomeTax:          3875000,
+			wantLocalTax:           387500,
+		},
+		{
+			// 환산급여 27,000,000 x 12 / 3; tax 840,000 + 28,700,000 x 15% = 5,145,000, x 3 / 12 = 1,286,250;
+			// the local tax of 128,625 drops the units
+			name:                   "30 million won over 3 years",
+			income:                 30000000,
+			years:                  3,
+			wantServiceDeduction:   3000000,
+			wantConvertedPay:       108000000,
+			wantConvertedDeduction: 65300000,
+			wantTaxBase:            42700000,
+			wantIncomeTax:          1286250,
+			wantLocalTax:           128620,
+		},
+		{
+			// 환산급여공제 8,000,000 + 4,000,000 x 60%; tax 1,600,000 x 6% = 96,000, x 5 / 12
+			name:                   "10 million won over 5 years",
+			income:                 10000000,
+			years:                  5,
+			wantServiceDeduction:   5000000,
+			wantConvertedPay:       12000000,
+			wantConvertedDeduction: 10400000,
+			wantTaxBase:            1600000,
+			wantIncomeTax:          40000,
+			wantLocalTax:           4000,
+		},
+		{
+			// 근속연수공제 40,000,000 + 5 x 3,000,000; 환산급여 445,000,000 x 12 / 25; 환산급여공제
+			// 61,700,000 + 113,600,000 x 45%; tax 15,360,000 + 12,780,000 x 35% = 19,833,000, x 25 / 12;
+			// the local tax of 4,131,875 drops the units
+			name:                   "500 million won over 25 years",
+			income:                 500000000,
+			years:                  25,
+			wantServiceDeduction:   55000000,
+			wantConvertedPay:       213600000,
+			wantConvertedDeduction: 112820000,
+			wantTaxBase:            100780000,
+			wantIncomeTax:          41318750,
+			wantLocalTax:           4131870,
+		},
+		{
+			name:                 "income within the service deduction",
+			income:               3000000,
+			years:                5,
+			wantServiceDeduction: 3000000,
+		},
+		{
+			name:   "less than a year of service",
+			income: 3000000,
+			years:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := models.SeveranceSettlement{RetirementIncome: tt.income, ServiceYears: tt.years}
+			calculateRetirementIncomeTax(&s)
+			checks := []struct {
+				field     string
+				got, want models.Money
+			}{
+				{"service years deduction", s.ServiceYearsDeduction, tt.wantServiceDeduction},
+				{"converted pay", s.ConvertedPay, tt.wantConvertedPay},
+				{"converted pay deduction", s.ConvertedPayDeduction, tt.wantConvertedDeduction},
+				{"tax base", s.TaxBase, tt.wantTaxBase},
+				{"income tax", s.IncomeTax, tt.wantIncomeTax},
+				{"local tax", s.LocalTax, tt.wantLocalTax},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %d, want %d", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTaxServiceYears(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	tests := []struct {
+		name         string
+		start, end   string
+		excludedDays int
+		want         int
+	}{
+		{"exactly one year", "2024-01-01", "2024-12-31", 0, 1},
+		{"a part year counts as a whole one", "2020-03-02", "2025-03-02", 0, 6},
+		{"excluded leave shortens the period", "2020-03-02", "2025-03-02", 1, 5},
+		{"ten years", "2015-07-01", "2025-06-30", 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taxServiceYears(date(tt.start), date(tt.end), tt.excludedDays); got != tt.want {
+				t.Errorf("taxServiceYears(%s, %s, %d) = %d, want %d", tt.start, tt.end, tt.excludedDays, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// wageBasisError is a wage that cannot be worked out from the employee's payroll, as opposed to a
+// database error
+type wageBasisError struct {
+	message string
+}
+
+func (e *wageBasisError) Error() string {
+	return e.message
+}
+
+// wageItem is one amount of a wage calculation, with the reason it is left out when it is
+type wageItem struct {
+	Name   string       `json:"name"`
+	Amount models.Money `json:"amount"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// ordinaryWage is the 통상임금 of an employee: the fixed pay for the agreed working hours, taken from
+// the latest payroll on or before a date and the current allowance classifications
+type ordinaryWage struct {
+	PayrollID          int          `json:"payroll_id"`
+	PayPeriodStart     string       `json:"pay_period_start"`
+	PayPeriodEnd       string       `json:"pay_period_end"`
+	SalaryType         string       `json:"salary_type"`
+	Items              []wageItem   `json:"items"`                   // 통상임금에 포함된 임금
+	ExcludedItems      []wageItem   `json:"excluded_items"`          // 통상임금에서 제외된 수당
+	MonthlyWage        models.Money `json:"monthly_wage,omitempty"`  // 월 통상임금 (월급제)
+	MonthlyHours       float64      `json:"monthly_hours,omitempty"` // 월 소정근로시간 (월급제)
+	HourlyWage         float64      `json:"hourly_wage"`             // 통상시급
+	DailyWage          float64      `json:"daily_wage"`              // 통상일급 (통상시급 x 8시간)
+	MinimumWage        models.Money `json:"minimum_wage"`
+	MinimumWageApplied bool         `json:"minimum_wage_applied"` // 통상시급이 최저임금보다 낮아 최저임금을 적용
+	Steps              []string     `json:"steps"`
+}
+
+// averageWagePayroll is the part of a payroll that falls in the averaging period
+type averageWagePayroll struct {
+	PayrollID      int          `json:"payroll_id"`
+	PayPeriodStart string       `json:"pay_period_start"`
+	PayPeriodEnd   string       `json:"pay_period_end"`
+	Wages          models.Money `json:"wages"` // 상여금을 제외한 임금 (소급·정정 포함)
+	PeriodDays     int          `json:"period_days"`
+	CountedDays    int          `json:"counted_days"` // 산정 기간에 속한 일수
+	CountedWages   models.Money `json:"counted_wages"`
+}
+
+// averageWageBonus is a bonus paid in the twelve months before the date
+type averageWageBonus struct {
+	PayrollID    int          `json:"payroll_id"`
+	PayPeriodEnd string       `json:"pay_period_end"`
+	Bonus        models.Money `json:"bonus"`
+}
+
+// averageWage is the 평균임금 of an employee: the wages of the three calendar months before a date
+// divided by the calendar days of the period, or the ordinary daily wage when that is higher
+type averageWage struct {
+	PeriodStart       string               `json:"period_start"`
+	PeriodEnd         string               `json:"period_end"`
+	Days              int                  `json:"days"`
+	Payrolls          []averageWagePayroll `json:"payrolls"`
+	Wages             models.Money         `json:"wages"` // 3개월 임금 총액
+	Bonuses           []averageWageBonus   `json:"bonuses"`
+	BonusTotal        models.Money         `json:"bonus_total"`   // 12개월 상여금
+	BonusCounted      models.Money         `json:"bonus_counted"` // 상여금 x 3/12
+	Total             models.Money         `json:"total"`
+	DailyWage         float64              `json:"daily_wage"` // 1일 평균임금
+	OrdinaryDailyWage float64              `json:"ordinary_daily_wage"`
+	AppliedDailyWage  float64              `json:"applied_daily_wage"`
+	Basis             string               `json:"basis"` // average, ordinary
+	OrdinaryWage      *ordinaryWage        `json:"ordinary_wage"`
+	Steps             []string             `json:"steps"`
+}
+
+// formatWage formats a wage with its fraction of a won, e.g. 10,047.84원
+func formatWage(amount float64) string {
+	cents := int64(math.Round(amount * 100))
+	if cents%100 == 0 {
+		return groupThousands(cents/100) + "원"
+	}
+	if cents < 0 {
+		return "-" + groupThousands(-cents/100) + fmt.Sprintf(".%02d원", -cents%100)
+	}
+	return groupThousands(cents/100) + fmt.Sprintf(".%02d원", cents%100)
+}
+
+// daysBetween counts the calendar days from start to end, both included
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// calculateOrdinaryWage works out the ordinary wage in force on the date from the latest payroll
+// starting on or before it. A corrected wage rate recorded by a payroll adjustment replaces the paid one.
+func calculateOrdinaryWage(db dbtx, employeeID int, asOf time.Time) (*ordinaryWage, error) {
+	var wage ordinaryWage
+	var periodStart, periodEnd time.Time
+	var wageRate, baseSalary, allowances models.Money
+	err := db.QueryRow(`
+		SELECT p.id, p.pay_period_start, p.pay_period_end, COALESCE(p.salary_type, 'monthly'),
+		       COALESCE((SELECT a.wage_rate FROM payroll_adjustments a WHERE a.original_payroll_id = p.id
+		                 ORDER BY a.id DESC LIMIT 1), p.wage_rate, 0),
+		       p.base_salary, COALESCE(p.allowances, 0)
+		FROM payroll_records p
+		WHERE p.employee_id = ? AND p.pay_period_start <= ?
+		ORDER BY p.pay_period_start DESC, p.id DESC
+		LIMIT 1
+	`, employeeID, asOf).Scan(&wage.PayrollID, &periodStart, &periodEnd, &wage.SalaryType, &wageRate,
+		&baseSalary, &allowances)
+	if err == sql.ErrNoRows {
+		return nil, &wageBasisError{"Employee has no payroll on or before " + asOf.Format("2006-01-02")}
+	}
+	if err != nil {
+		return nil, err
+	}
+	wage.PayPeriodStart = periodStart.Format("2006-01-02")
+	wage.PayPeriodEnd = periodEnd.Format("2006-01-02")
+	if wageRate == 0 {
+		// Records from before wage rates were stored keep the monthly salary as base salary
+		wageRate = baseSalary
+	}
+
+	rows, err := db.Query(`
+		SELECT i.allowance_type_id, i.name, i.amount, t.payment_type, t.ordinary_wage
+		FROM payroll_allowance_items i
+		JOIN allowance_types t ON t.id = i.allowance_type_id
+		WHERE i.payroll_id = ?
+		ORDER BY i.id
+	`, wage.PayrollID)
+	if err != nil {
+		return nil, err
+	}
+	var lines []payrollAllowanceLine
+	for rows.Next() {
+		var line payrollAllowanceLine
+		err := rows.Scan(&line.Type.ID, &line.Type.Name, &line.Amount, &line.Type.PaymentType, &line.Type.OrdinaryWage)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if wage.MinimumWage, err = loadMinimumWage(db, asOf.Year()); err != nil {
+		return nil, err
+	}
+
+	if wage.SalaryType != "hourly" && wage.SalaryType != "daily" {
+		if wage.MonthlyHours, err = employeeMonthlyHours(db, employeeID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The payroll calculator's ordinary hourly wage is the one overtime was paid on
+	calculator := &PayrollCalculator{SalaryType: wage.SalaryType, BaseSalary: wageRate, MonthlyHours: wage.MonthlyHours, AllowanceItems: lines}
+	hourlyWage := calculator.OrdinaryHourlyWage()
+	calculator.MinimumWage = wage.MinimumWage
+	wage.HourlyWage = calculator.OrdinaryHourlyWage()
+	wage.MinimumWageApplied = wage.HourlyWage > hourlyWage
+	wage.DailyWage = wage.HourlyWage * standardDailyHours
+
+	itemized := models.Money(0)
+	for _, line := range lines {
+		itemized += line.Amount
+		item := wageItem{Name: line.Type.Name, Amount: line.Amount}
+		switch {
+		case wage.SalaryType == "hourly" || wage.SalaryType == "daily":
+			item.Reason = "시급·일급제는 시급·일급으로 산정"
+		case line.Type.PaymentType != "fixed":
+			item.Reason = "매월 정기적으로 지급되지 않는 변동 수당"
+		case !line.Type.OrdinaryWage:
+			item.Reason = "통상임금 제외 수당"
+		default:
+			wage.Items = append(wage.Items, item)
+			continue
+		}
+		wage.ExcludedItems = append(wage.ExcludedItems, item)
+	}
+	if untyped := allowances - itemized; untyped > 0 {
+		wage.ExcludedItems = append(wage.ExcludedItems, wageItem{Name: "기타 수당", Amount: untyped, Reason: "항목 구분 없이 입력된 수당"})
+	}
+
+	switch wage.SalaryType {
+	case "hourly":
+		wage.Items = append([]wageItem{{Name: "시급", Amount: wageRate}}, wage.Items...)
+		wage.Steps = append(wage.Steps, "통상시급 = 시급 "+formatWage(hourlyWage))
+	case "daily":
+		wage.Items = append([]wageItem{{Name: "일급", Amount: wageRate}}, wage.Items...)
+		wage.Steps = append(wage.Steps, fmt.Sprintf("통상시급 = 일급 %s ÷ %d시간 = %s",
+			formatWon(wageRate.Float64()), standardDailyHours, formatWage(hourlyWage)))
+	default:
+		wage.Items = append([]wageItem{{Name: "기본급", Amount: wageRate}}, wage.Items...)
+		terms := make([]string, 0, len(wage.Items))
+		for _, item := range wage.Items {
+			wage.MonthlyWage += item.Amount
+			terms = append(terms, item.Name+" "+formatWon(item.Amount.Float64()))
+		}
+		wage.Steps = append(wage.Steps,
+			fmt.Sprintf("월 통상임금 = %s = %s", strings.Join(terms, " + "), formatWon(wage.MonthlyWage.Float64())),
+			fmt.Sprintf("통상시급 = %s ÷ %g시간 = %s", formatWon(wage.MonthlyWage.Float64()), wage.MonthlyHours, formatWage(hourlyWage)))
+	}
+	if wage.MinimumWageApplied {
+		wage.Steps = append(wage.Steps, fmt.Sprintf("통상시급 %s이 %d년 최저임금 %s보다 낮아 최저임금 적용",
+			formatWage(hourlyWage), asOf.Year(), formatWon(wage.MinimumWage.Float64())))
+	}
+	wage.Steps = append(wage.Steps, fmt.Sprintf("통상일급 = %s x %d시간 = %s",
+		formatWage(wage.HourlyWage), standardDailyHours, formatWage(wage.DailyWage)))
+
+	return &wage, nil
+}
+
+// calculateAverageWage works out the average wage on the date, the day the reason for paying it arose
+// (퇴직일, 휴업일 등). The three calendar months before the date, or the days since hire when shorter,
+// take the pay of each payroll in proportion to the days falling in them, corrected by any payroll
+// adjustments; bonuses paid in the twelve months before count for three twelfths. When the result
+// is below the ordinary daily wage, the ordinary daily wage applies (근로기준법 제2조 제2항).
+func calculateAverageWage(db dbtx, employeeID int, asOf time.Time) (*averageWage, error) {
+	var hireDate time.Time
+	if err := db.QueryRow("SELECT hire_date FROM employees WHERE id = ?", employeeID).Scan(&hireDate); err != nil {
+		return nil, err
+	}
+
+	start := asOf.AddDate(0, -3, 0)
+	end := asOf.AddDate(0, 0, -1)
+	if hireDate.After(start) {
+		start = hireDate
+	}
+	if start.After(end) {
+		return nil, &wageBasisError{"Employee was not employed before " + asOf.Format("2006-01-02")}
+	}
+	bonusFrom := asOf.AddDate(-1, 0, 0)
+
+	wage := averageWage{
+		PeriodStart: start.Format("2006-01-02"),
+		PeriodEnd:   end.Format("2006-01-02"),
+		Days:        daysBetween(start, end),
+		Payrolls:    []averageWagePayroll{},
+		Bonuses:     []averageWageBonus{},
+	}
+
+	rows, err := db.Query(`
+		SELECT p.id, p.pay_period_start, p.pay_period_end, p.gross_pay - COALESCE(p.bonus, 0), COALESCE(p.bonus, 0),
+		       COALESCE((SELECT SUM(a.gross_pay - a.bonus) FROM payroll_adjustments a WHERE a.original_payroll_id = p.id), 0),
+		       COALESCE((SELECT SUM(a.bonus) FROM payroll_adjustments a WHERE a.original_payroll_id = p.id), 0)
+		FROM payroll_records p
+		WHERE p.employee_id = ? AND p.pay_period_end >= ? AND p.pay_period_start <= ?
+		ORDER BY p.pay_period_start, p.id
+	`, employeeID, bonusFrom, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payrollID int
+		var periodStart, periodEnd time.Time
+		var wages, bonus, adjustedWages, adjustedBonus models.Money
+		if err := rows.Scan(&payrollID, &periodStart, &periodEnd, &wages, &bonus, &adjustedWages, &adjustedBonus); err != nil {
+			return nil, err
+		}
+
+		if !periodEnd.Before(bonusFrom) && !periodEnd.After(end) && bonus+adjustedBonus != 0 {
+			wage.Bonuses = append(wage.Bonuses, averageWageBonus{
+				PayrollID:    payrollID,
+				PayPeriodEnd: periodEnd.Format("2006-01-02"),
+				Bonus:        bonus + adjustedBonus,
+			})
+			wage.BonusTotal += bonus + adjustedBonus
+		}
+
+		if periodEnd.Before(start) {
+			continue
+		}
+		from, until := periodStart, periodEnd
+		if from.Before(start) {
+			from = start
+		}
+		if until.After(end) {
+			until = end
+		}
+		line := averageWagePayroll{
+			PayrollID:      payrollID,
+			PayPeriodStart: periodStart.Format("2006-01-02"),
+			PayPeriodEnd:   periodEnd.Format("2006-01-02"),
+			Wages:          wages + adjustedWages,
+			PeriodDays:     daysBetween(periodStart, periodEnd),
+			CountedDays:    daysBetween(from, until),
+		}
+		line.CountedWages = line.Wages
+		step := fmt.Sprintf("%s~%s 급여 %s", line.PayPeriodStart, line.PayPeriodEnd, formatWon(line.Wages.Float64()))
+		if line.CountedDays < line.PeriodDays {
+			line.CountedWages = models.TruncateWon(line.Wages.Float64() * float64(line.CountedDays) / float64(line.PeriodDays))
+			step += fmt.Sprintf(" x %d/%d일 = %s", line.CountedDays, line.PeriodDays, formatWon(line.CountedWages.Float64()))
+		}
+		wage.Steps = append(wage.Steps, step)
+		wage.Payrolls = append(wage.Payrolls, line)
+		wage.Wages += line.CountedWages
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(wage.Payrolls) == 0 {
+		return nil, &wageBasisError{"Employee has no payroll in the three months before " + asOf.Format("2006-01-02")}
+	}
+
+	wage.Steps = append([]string{fmt.Sprintf("산정 기간 = %s ~ %s (%d일)", wage.PeriodStart, wage.PeriodEnd, wage.Days)}, wage.Steps...)
+	wage.Steps = append(wage.Steps, "3개월 임금 총액 = "+formatWon(wage.Wages.Float64()))
+	if wage.BonusTotal != 0 {
+		wage.BonusCounted = models.TruncateWon(wage.BonusTotal.Float64() * 3 / 12)
+		wage.Steps = append(wage.Steps, fmt.Sprintf("상여금 가산 = 12개월 상여금 %s x 3/12 = %s",
+			formatWon(wage.BonusTotal.Float64()), formatWon(wage.BonusCounted.Float64())))
+	}
+	wage.Total = wage.Wages + wage.BonusCounted
+	wage.DailyWage = wage.Total.Float64() / float64(wage.Days)
+	wage.Steps = append(wage.Steps, fmt.Sprintf("1일 평균임금 = %s ÷ %d일 = %s",
+		formatWon(wage.Total.Float64()), wage.Days, formatWage(wage.DailyWage)))
+
+	if wage.OrdinaryWage, err = calculateOrdinaryWage(db, employeeID, asOf); err != nil {
+		return nil, err
+	}
+	wage.OrdinaryDailyWage = wage.OrdinaryWage.DailyWage
+	wage.Basis, wage.AppliedDailyWage = "average", wage.DailyWage
+	if wage.DailyWage < wage.OrdinaryDailyWage {
+		wage.Basis, wage.AppliedDailyWage = "ordinary", wage.OrdinaryDailyWage
+		wage.Steps = append(wage.Steps, fmt.Sprintf("평균임금 %s이 통상일급 %s보다 낮아 통상일급 적용",
+			formatWage(wage.DailyWage), formatWage(wage.OrdinaryDailyWage)))
+	}
+
+	return &wage, nil
+}
+
+// wageBasisParams reads the employee ID and the date (default today) of a wage basis request
+func wageBasisParams(c *gin.Context) (int, time.Time, bool) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return 0, time.Time{}, false
+	}
+
+	asOf := time.Now().Truncate(24 * time.Hour)
+	if date := c.Query("date"); date != "" {
+		if asOf, err = time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (YYYY-MM-DD)"})
+			return 0, time.Time{}, false
+		}
+	}
+	return employeeID, asOf, true
+}
+
+// respondWageBasisError reports a wage that could not be worked out
+func respondWageBasisError(c *gin.Context, err error) {
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if basisErr, ok := err.(*wageBasisError); ok {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": basisErr.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate wage"})
+}
+
+// GetEmployeeOrdinaryWage returns the employee's ordinary wage on the date (date, default today)
+// with its breakdown
+func GetEmployeeOrdinaryWage(c *gin.Context) {
+	employeeID, asOf, ok := wageBasisParams(c)
+	if !ok {
+		return
+	}
+
+	var name string
+	err := database.DB.QueryRow("SELECT name FROM employees WHERE id = ?", employeeID).Scan(&name)
+	var wage *ordinaryWage
+	if err == nil {
+		wage, err = calculateOrdinaryWage(database.DB, employeeID, asOf)
+	}
+	if err != nil {
+		respondWageBasisError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employee_id":   employeeID,
+		"employee_name": name,
+		"date":          asOf.Format("2006-01-02"),
+		"ordinary_wage": wage,
+	})
+}
+
+// GetEmployeeAverageWage returns the employee's average wage on the date (date, default today), the
+// day the reason for paying it arose, with its breakdown
+func GetEmployeeAverageWage(c *gin.Context) {
+	employeeID, asOf, ok := wageBasisParams(c)
+	if !ok {
+		return
+	}
+
+	var name string
+	err := database.DB.QueryRow("SELECT name FROM employees WHERE id = ?", employeeID).Scan(&name)
+	var wage *averageWage
+	if err == nil {
+		wage, err = calculateAverageWage(database.DB, employeeID, asOf)
+	}
+	if err != nil {
+		respondWageBasisError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employee_id":   employeeID,
+		"employee_name": name,
+		"date":          asOf.Format("2006-01-02"),
+		"average_wage":  wage,
+	})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+)
+
+// setupTestDatabase opens a fresh SQLite database with the schema. The schema is read relative to
+// the module root, so the working directory is moved there for the test.
+func setupTestDatabase(t *testing.T) {
+	t.Helper()
+	if os.Getenv("DATABASE_URL") != "" {
+		t.Skip("DATABASE_URL is set; the wage tests run against SQLite only")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(wd, "..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	if err := database.InitDatabase(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Close() })
+}
+
+// roundWage rounds a wage to the 전 shown in the breakdown
+func roundWage(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func TestOrdinaryHourlyWage(t *testing.T) {
+	meal := payrollAllowanceLine{Type: models.AllowanceType{PaymentType: "fixed", OrdinaryWage: true}, Amount: 200000}
+	car := payrollAllowanceLine{Type: models.AllowanceType{PaymentType: "fixed", OrdinaryWage: false}, Amount: 200000}
+	incentive := payrollAllowanceLine{Type: models.AllowanceType{PaymentType: "variable", OrdinaryWage: true}, Amount: 500000}
+
+	tests := []struct {
+		name       string
+		calculator PayrollCalculator
+		want       float64
+	}{
+		{"monthly over 209 hours", PayrollCalculator{SalaryType: "monthly", BaseSalary: 2200000}, 10526.32},
+		{"fixed ordinary allowances count", PayrollCalculator{SalaryType: "monthly", BaseSalary: 2200000,
+			AllowanceItems: []payrollAllowanceLine{meal, car, incentive}}, 11483.25},
+		{"part-time monthly hours", PayrollCalculator{SalaryType: "monthly", BaseSalary: 1200000, MonthlyHours: 104}, 11538.46},
+		{"hourly", PayrollCalculator{SalaryType: "hourly", BaseSalary: 12000}, 12000},
+		{"daily over 8 hours", PayrollCalculator{SalaryType: "daily", BaseSalary: 100000}, 12500},
+		{"minimum wage floor", PayrollCalculator{SalaryType: "monthly", BaseSalary: 2000000, MinimumWage: 10030}, 10030},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roundWage(tt.calculator.OrdinaryHourlyWage()); got != tt.want {
+				t.Errorf("OrdinaryHourlyWage() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+// The cases follow the 고용노동부 평균임금 examples: three calendar months of wages plus three twelfths
+// of the year's bonus, over the calendar days of the period, compared with 통상일급 (통상시급 x 8).
+func TestCalculateAverageWage(t *testing.T) {
+	type payroll struct {
+		start, end         string
+		wageRate, overtime models.Money
+		bonus              models.Money
+	}
+	// A 4,000,000원 bonus paid with March pay, outside the three months but within the year
+	march := func(wageRate models.Money) payroll {
+		return payroll{"2024-03-01", "2024-03-31", wageRate, 0, 4000000}
+	}
+	quarter := func(wageRate, overtime models.Money) []payroll {
+		return []payroll{
+			{"2024-04-01", "2024-04-30", wageRate, overtime, 0},
+			{"2024-05-01", "2024-05-31", wageRate, overtime, 0},
+			{"2024-06-01", "2024-06-30", wageRate, overtime, 0},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		hireDate         string
+		asOf             string
+		payrolls         []payroll
+		wantDays         int
+		wantWages        models.Money
+		wantBonusCounted models.Money
+		wantDailyWage    float64
+		wantOrdinary     float64
+		wantBasis        string
+	}{
+		{
+			// 10,000,000 ÷ 91 = 109,890.11 is below 3,000,000 ÷ 209 x 8 = 114,832.54
+			name:             "ordinary daily wage is higher",
+			hireDate:         "2023-01-02",
+			asOf:             "2024-07-01",
+			payrolls:         append([]payroll{march(3000000)}, quarter(3000000, 0)...),
+			wantDays:         91,
+			wantWages:        9000000,
+			wantBonusCounted: 1000000,
+			wantDailyWage:    109890.11,
+			wantOrdinary:     114832.54,
+			wantBasis:        "ordinary",
+		},
+		{
+			// Overtime of 500,000 a month: 14,500,000 ÷ 91 = 159,340.66 over 4,000,000 ÷ 209 x 8 = 153,110.05
+			name:             "average wage with overtime and bonus",
+			hireDate:         "2023-01-02",
+			asOf:             "2024-07-01",
+			payrolls:         append([]payroll{march(4000000)}, quarter(4000000, 500000)...),
+			wantDays:         91,
+			wantWages:        13500000,
+			wantBonusCounted: 1000000,
+			wantDailyWage:    159340.66,
+			wantOrdinary:     153110.05,
+			wantBasis:        "average",
+		},
+		{
+			// 2024-04-16 ~ 07-15: 15/30 of April, May, June and 15/31 of July (1,451,612) = 8,951,612;
+			// with the bonus 9,951,612 ÷ 91 = 109,358.37
+			name:     "pay periods split by the averaging period",
+			hireDate: "2023-01-02",
+			asOf:     "2024-07-16",
+			payrolls: append(append([]payroll{march(3000000)}, quarter(3000000, 0)...),
+				payroll{"2024-07-01", "2024-07-31", 3000000, 0, 0}),
+			wantDays:         91,
+			wantWages:        8951612,
+			wantBonusCounted: 1000000,
+			wantDailyWage:    109358.37,
+			wantOrdinary:     114832.54,
+			wantBasis:        "ordinary",
+		},
+		{
+			// Hired 2024-05-01, so the period is the 61 days since hire: 7,000,000 ÷ 61 = 114,754.10
+			name:             "shorter than three months since hire",
+			hireDate:         "2024-05-01",
+			asOf:             "2024-07-01",
+			payrolls:         quarter(3500000, 0)[1:],
+			wantDays:         61,
+			wantWages:        7000000,
+			wantBonusCounted: 0,
+			wantDailyWage:    114754.10,
+			wantOrdinary:     133971.29,
+			wantBasis:        "ordinary",
+		},
+	}
+
+	setupTestDatabase(t)
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := database.DB.Exec(`
+				INSERT INTO employees (employee_number, name, hire_date) VALUES (?, ?, ?)
+			`, fmt.Sprintf("T%03d", i+1), tt.name, tt.hireDate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			employeeID, _ := result.LastInsertId()
+			for _, p := range tt.payrolls {
+				gross := p.wageRate + p.overtime + p.bonus
+				_, err := database.DB.Exec(`
+					INSERT INTO payroll_records (employee_id, pay_period_start, pay_period_end, salary_type,
+						wage_rate, base_salary, overtime_pay, bonus, gross_pay, total_deductions, net_pay)
+					VALUES (?, ?, ?, 'monthly', ?, ?, ?, ?, ?, 0, ?)
+				`, employeeID, p.start, p.end, p.wageRate, p.wageRate, p.overtime, p.bonus, gross, gross)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			asOf, _ := time.Parse("2006-01-02", tt.asOf)
+			wage, err := calculateAverageWage(database.DB, int(employeeID), asOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wage.Days != tt.wantDays {
+				t.Errorf("days = %d, want %d", wage.Days, tt.wantDays)
+			}
+			if wage.Wages != tt.wantWages {
+				t.Errorf("wages = %d, want %d", wage.Wages, tt.wantWages)
+			}
+			if wage.BonusCounted != tt.wantBonusCounted {
+				t.Errorf("bonus counted = %d, want %d", wage.BonusCounted, tt.wantBonusCounted)
+			}
+			if got := roundWage(wage.DailyWage); got != tt.wantDailyWage {
+				t.Errorf("daily wage = %.2f, want %.2f", got, tt.wantDailyWage)
+			}
+			if got := roundWage(wage.OrdinaryDailyWage); got != tt.wantOrdinary {
+				t.Errorf("ordinary daily wage = %.2f, want %.2f", got, tt.wantOrdinary)
+			}
+			if wage.Basis != tt.wantBasis {
+				t.Errorf("basis = %s, want %s", wage.Basis, tt.wantBasis)
+			}
+		})
+	}
+}
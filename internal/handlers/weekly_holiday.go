@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// weeklyHolidayMinHours is the contractual time per week from which 주휴수당 is paid (근로기준법 제18조 제3항)
+const weeklyHolidayMinHours = 15
+
+// workWeek is one week of an employee's schedule against their attendance. A week ends on the
+// weekly holiday of the work schedule and belongs to the pay period that holiday falls in.
+type workWeek struct {
+	WeekStart          time.Time `json:"week_start"`
+	WeekEnd            time.Time `json:"week_end"`
+	ScheduledHours     float64   `json:"scheduled_hours"`
+	ActualHours        float64   `json:"actual_hours"`
+	ScheduledDays      int       `json:"scheduled_days"`
+	AttendedDays       int       `json:"attended_days"`
+	Eligible           bool      `json:"eligible"`
+	WeeklyHolidayHours float64   `json:"weekly_holiday_hours"`
+}
+
+// loadWorkWeeks builds the weeks of the period from the employee's work schedule and attendance.
+// A week earns the paid weekly holiday when at least 15 hours are scheduled and every scheduled
+// day was worked or taken as paid leave; it is paid for the scheduled hours over 40 times 8 hours.
+// Employees without a work schedule have no weeks.
+func loadWorkWeeks(db dbtx, employeeID int, start, end time.Time) ([]workWeek, error) {
+	schedule, err := employeeWorkSchedule(db, employeeID)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+
+	firstEnd := start
+	for firstEnd.Weekday() != schedule.WeeklyHoliday {
+		firstEnd = firstEnd.AddDate(0, 0, 1)
+	}
+	if firstEnd.After(end) {
+		return []workWeek{}, nil
+	}
+	rangeStart := firstEnd.AddDate(0, 0, -6)
+
+	worked := make(map[string]float64)
+	rows, err := db.Query(`
+		SELECT work_date, total_hours FROM attendance_logs
+		WHERE employee_id = ? AND work_date >= ? AND work_date <= ? AND total_hours > 0
+	`, employeeID, rangeStart.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var log models.AttendanceLog
+		if err := rows.Scan(&log.WorkDate, &log.TotalHours); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		worked[log.WorkDate.Format("2006-01-02")] += log.TotalHours.Float64
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Paid leave counts as attendance
+	onLeave := make(map[string]bool)
+	rows, err = db.Query(`
+		SELECT leave_type, start_date, end_date FROM leave_requests
+		WHERE employee_id = ? AND status = 'approved' AND end_date >= ? AND start_date <= ?
+	`, employeeID, rangeStart.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var leave models.LeaveRequest
+		if err := rows.Scan(&leave.LeaveType, &leave.StartDate, &leave.EndDate); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if unpaidLeaveTypes[leave.LeaveType] {
+			continue
+		}
+		for d := leave.StartDate.Truncate(24 * time.Hour); !d.After(leave.EndDate); d = d.AddDate(0, 0, 1) {
+			onLeave[d.Format("2006-01-02")] = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	scheduledHours := scheduleWeeklyHours(schedule)
+	weeks := []workWeek{}
+	for weekEnd := firstEnd; !weekEnd.After(end); weekEnd = weekEnd.AddDate(0, 0, 7) {
+		week := workWeek{
+			WeekStart:      weekEnd.AddDate(0, 0, -6),
+			WeekEnd:        weekEnd,
+			ScheduledHours: scheduledHours,
+		}
+		for d := week.WeekStart; !d.After(weekEnd); d = d.AddDate(0, 0, 1) {
+			key := d.Format("2006-01-02")
+			week.ActualHours += worked[key]
+			if scheduleDay(schedule, d.Weekday()) == nil {
+				continue
+			}
+			week.ScheduledDays++
+			if worked[key] > 0 || onLeave[key] {
+				week.AttendedDays++
+			}
+		}
+
+		week.Eligible = scheduledHours >= weeklyHolidayMinHours && week.ScheduledDays > 0 &&
+			week.AttendedDays == week.ScheduledDays
+		if week.Eligible {
+			week.WeeklyHolidayHours = minFloat(scheduledHours, 40) / 40 * standardDailyHours
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks, nil
+}
+
+// GetEmployeeWorkWeeks returns the scheduled and actual hours of each week of a pay period
+// (YYYY-MM, default this month) and whether the week earns the paid weekly holiday
+func GetEmployeeWorkWeeks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	periodStart, err := time.Parse("2006-01", c.DefaultQuery("pay_period", time.Now().Format("2006-01")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay period format (YYYY-MM)"})
+		return
+	}
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	weeks, err := loadWorkWeeks(database.DB, id, periodStart, periodEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work weeks"})
+		return
+	}
+	if weeks == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee's active contract has no work schedule"})
+		return
+	}
+
+	weeklyHolidayHours := 0.0
+	for _, week := range weeks {
+		weeklyHolidayHours += week.WeeklyHolidayHours
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employee_id":          id,
+		"pay_period":           periodStart.Format("2006-01"),
+		"weeks":                weeks,
+		"weekly_holiday_hours": weeklyHolidayHours,
+	})
+}
@@ -0,0 +1,543 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// normalizeResidentNumber drops the hyphen; 주민등록번호 are 13 digits
+func normalizeResidentNumber(residentNumber string) (string, bool) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(residentNumber)
+	if len(digits) != 13 {
+		return "", false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return digits, true
+}
+
+// maskResidentNumber shows the birth date and gender digit only (900505-1******)
+func maskResidentNumber(residentNumber string) string {
+	if len(residentNumber) != 13 {
+		return ""
+	}
+	return residentNumber[:6] + "-" + residentNumber[6:7] + "******"
+}
+
+// employeeResidentNumber returns the employee's decrypted resident number, empty when not registered
+func employeeResidentNumber(db dbtx, employeeID int) (string, error) {
+	var encrypted sql.NullString
+	err := db.QueryRow("SELECT resident_number_encrypted FROM employees WHERE id = ?", employeeID).Scan(&encrypted)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	if !encrypted.Valid || encrypted.String == "" {
+		return "", nil
+	}
+	return decryptPII(encrypted.String)
+}
+
+// withholdingAgent is the company as 원천징수의무자, from the system settings
+type withholdingAgent struct {
+	Name               string
+	Representative     string
+	RegistrationNumber string // 10 digits
+	Address            string
+	Phone              string
+	TaxOfficeCode      string
+	HometaxID          string
+}
+
+func loadWithholdingAgent(db dbtx) (withholdingAgent, error) {
+	settings, err := loadSettings(db, "company_name", "company_representative", "company_registration_number",
+		"company_address", "company_phone", "tax_office_code", "hometax_id")
+	if err != nil {
+		return withholdingAgent{}, err
+	}
+	return withholdingAgent{
+		Name:               settings["company_name"],
+		Representative:     settings["company_representative"],
+		RegistrationNumber: strings.ReplaceAll(settings["company_registration_number"], "-", ""),
+		Address:            settings["company_address"],
+		Phone:              settings["company_phone"],
+		TaxOfficeCode:      settings["tax_office_code"],
+		HometaxID:          settings["hometax_id"],
+	}, nil
+}
+
+// withholdingReceipt is one employee's 근로소득 원천징수영수증 for a tax year. Employees who left during
+// the year are settled up to their last pay period.
+type withholdingReceipt struct {
+	EmployeeNumber string
+	EmployeeName   string
+	ResidentNumber string
+	Address        string
+	WorkFrom       time.Time
+	WorkTo         time.Time
+	Salary         models.Money // 급여 (과세 급여 중 상여를 뺀 금액)
+	Bonus          models.Money // 상여
+	Settlement     *models.YearEndSettlement
+}
+
+// loadWithholdingReceipt gathers the receipt from the year's payroll and the employee's year-end
+// settlement. Without a settlement, or with a draft one, the tax is calculated on the payroll and
+// any declarations made so far. sql.ErrNoRows means the employee was not paid in the year.
+func loadWithholdingReceipt(db dbtx, employeeID, taxYear int) (*withholdingReceipt, error) {
+	var r withholdingReceipt
+	var hireDate time.Time
+	var status sql.NullString
+	err := db.QueryRow(`
+		SELECT employee_number, name, COALESCE(address, ''), hire_date, status FROM employees WHERE id = ?
+	`, employeeID).Scan(&r.EmployeeNumber, &r.EmployeeName, &r.Address, &hireDate, &status)
+	if err != nil {
+		return nil, err
+	}
+	if r.ResidentNumber, err = employeeResidentNumber(db, employeeID); err != nil {
+		return nil, err
+	}
+
+	from, until := strconv.Itoa(taxYear)+"-01-01", strconv.Itoa(taxYear+1)+"-01-01"
+	var lastPeriodEnd time.Time
+	err = db.QueryRow(`
+		SELECT p.pay_period_end FROM payroll_records p
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE p.employee_id = ? AND p.pay_period_start >= ? AND p.pay_period_start < ? AND `+reportedPayrollCond+`
+		ORDER BY p.pay_period_end DESC LIMIT 1
+	`, employeeID, from, until).Scan(&lastPeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	// Counted on the same approved or paid payroll as the year-end settlement's pay
+	err = db.QueryRow(`
+		SELECT COALESCE(SUM(p.bonus), 0) + (
+			SELECT COALESCE(SUM(a.bonus), 0) FROM payroll_adjustments a
+			JOIN payroll_records o ON o.id = a.original_payroll_id
+			JOIN payroll_records p ON p.id = a.applied_payroll_id
+			LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+			WHERE a.employee_id = ? AND a.status = 'applied' AND `+reportedPayrollCond+`
+			  AND o.pay_period_start >= ? AND o.pay_period_start < ?
+		)
+		FROM payroll_records p
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE p.employee_id = ? AND p.pay_period_start >= ? AND p.pay_period_start < ? AND `+reportedPayrollCond+`
+	`, employeeID, from, until, employeeID, from, until).Scan(&r.Bonus)
+	if err != nil {
+		return nil, err
+	}
+
+	r.WorkFrom = time.Date(taxYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if hireDate.After(r.WorkFrom) {
+		r.WorkFrom = hireDate
+	}
+	r.WorkTo = time.Date(taxYear, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if status.String == "terminated" && lastPeriodEnd.Before(r.WorkTo) {
+		r.WorkTo = lastPeriodEnd
+	}
+
+	r.Settlement, err = loadYearEndSettlement(db, employeeID, taxYear)
+	if err == sql.ErrNoRows {
+		r.Settlement = &models.YearEndSettlement{EmployeeID: employeeID, TaxYear: taxYear, Status: "draft"}
+	} else if err != nil {
+		return nil, err
+	}
+	if r.Settlement.Status == "draft" {
+		if err := computeYearEndSettlement(db, r.Settlement); err != nil {
+			return nil, err
+		}
+	}
+
+	r.Bonus = minMoney(r.Bonus, r.Settlement.TotalPay)
+	r.Salary = r.Settlement.TotalPay - r.Bonus
+	return &r, nil
+}
+
+// loadWithholdingReceipts loads the receipts of the employee, or of everyone paid in the tax year
+// when employeeID is 0
+func loadWithholdingReceipts(db dbtx, taxYear, employeeID int) ([]*withholdingReceipt, error) {
+	employeeIDs := []int{employeeID}
+	if employeeID == 0 {
+		var err error
+		if employeeIDs, err = yearEndEmployeeIDs(db, taxYear); err != nil {
+			return nil, err
+		}
+	}
+
+	var receipts []*withholdingReceipt
+	for _, id := range employeeIDs {
+		r, err := loadWithholdingReceipt(db, id, taxYear)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, r)
+	}
+	return receipts, nil
+}
+
+// addWithholdingReceiptPage prints the receipt in the layout of 소득세법 시행규칙 별지 제24호서식(1):
+// withholding agent, income earner, pay by workplace, the tax calculation and the tax statement
+func addWithholdingReceiptPage(pdf *gofpdf.Fpdf, agent withholdingAgent, r *withholdingReceipt, issued time.Time) {
+	s := r.Settlement
+	const rowHeight = 6.5
+	won := func(m models.Money) string { return groupThousands(int64(m)) }
+	cell := func(width float64, text, align string, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont(pdfFont, style, 8)
+		pdf.CellFormat(width, rowHeight, text, "1", 0, align, false, 0, "")
+	}
+	section := func(title string) {
+		pdf.Ln(3)
+		pdf.SetFont(pdfFont, "B", 9)
+		pdf.CellFormat(190, rowHeight, title, "", 1, "L", false, 0, "")
+	}
+
+	pdf.AddPage()
+	pdf.SetFont(pdfFont, "", 8)
+	pdf.CellFormat(190, 5, "[소득세법 시행규칙 별지 제24호서식(1)]", "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFont, "B", 15)
+	pdf.CellFormat(190, 10, "근로소득 원천징수영수증", "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.CellFormat(190, 6, fmt.Sprintf("귀속연도 %d년  (소득자 보관용)", s.TaxYear), "", 1, "C", false, 0, "")
+
+	section("징수의무자")
+	cell(30, "법인명(상호)", "C", true)
+	cell(65, agent.Name, "L", false)
+	cell(30, "대표자(성명)", "C", true)
+	cell(65, agent.Representative, "L", false)
+	pdf.Ln(rowHeight)
+	cell(30, "사업자등록번호", "C", true)
+	cell(65, formatRegistrationNumber(agent.RegistrationNumber), "L", false)
+	cell(30, "소재지(주소)", "C", true)
+	cell(65, agent.Address, "L", false)
+	pdf.Ln(rowHeight)
+
+	section("소득자")
+	cell(30, "성명", "C", true)
+	cell(65, r.EmployeeName, "L", false)
+	cell(30, "주민등록번호", "C", true)
+	cell(65, maskResidentNumber(r.ResidentNumber), "L", false)
+	pdf.Ln(rowHeight)
+	cell(30, "주소", "C", true)
+	cell(160, r.Address, "L", false)
+	pdf.Ln(rowHeight)
+
+	section("I. 근무처별 소득명세")
+	for i, title := range []string{"근무처명", "근무기간", "급여", "상여", "비과세소득", "총급여"} {
+		width := []float64{40, 46, 26, 26, 26, 26}[i]
+		cell(width, title, "C", true)
+	}
+	pdf.Ln(rowHeight)
+	cell(40, agent.Name, "L", false)
+	cell(46, r.WorkFrom.Format("2006.01.02")+" ~ "+r.WorkTo.Format("2006.01.02"), "C", false)
+	cell(26, won(r.Salary), "R", false)
+	cell(26, won(r.Bonus), "R", false)
+	cell(26, won(s.NonTaxablePay), "R", false)
+	cell(26, won(s.TotalPay), "R", false)
+	pdf.Ln(rowHeight)
+
+	section("II. 정산명세")
+	lines := []struct {
+		Title  string
+		Amount models.Money
+	}{
+		{"총급여", s.TotalPay},
+		{"근로소득공제", s.EarnedIncomeDeduction},
+		{"근로소득금액", s.EarnedIncomeAmount},
+		{fmt.Sprintf("기본공제 (%d명)", s.Dependents), s.PersonalDeduction},
+		{"연금보험료공제", s.PensionDeduction},
+		{"특별소득공제 (보험료)", s.SpecialIncomeDeduction},
+		{"신용카드 등 소득공제", s.CardDeduction},
+		{"종합소득 과세표준", s.TaxBase},
+		{"산출세액", s.CalculatedTax},
+		{"근로소득 세액공제", s.EarnedIncomeTaxCredit},
+		{"특별세액공제", s.SpecialTaxCredit},
+		{"표준세액공제", s.StandardTaxCredit},
+	}
+	half := (len(lines) + 1) / 2
+	for i := 0; i < half; i++ {
+		for _, j := range []int{i, i + half} {
+			if j < len(lines) {
+				cell(60, lines[j].Title, "L", true)
+				cell(35, won(lines[j].Amount), "R", false)
+			}
+		}
+		pdf.Ln(rowHeight)
+	}
+
+	section("III. 세액명세")
+	cell(70, "구분", "C", true)
+	cell(40, "소득세", "C", true)
+	cell(40, "지방소득세", "C", true)
+	cell(40, "계", "C", true)
+	pdf.Ln(rowHeight)
+	for _, line := range []struct {
+		Title           string
+		Income, LocalTx models.Money
+	}{
+		{"결정세액", s.DeterminedTax, s.DeterminedLocalTax},
+		{"기납부세액 (주(현)근무지)", s.WithheldTax, s.WithheldLocalTax},
+		{"차감징수세액", s.BalanceTax, s.BalanceLocalTax},
+	} {
+		cell(70, line.Title, "L", true)
+		cell(40, won(line.Income), "R", false)
+		cell(40, won(line.LocalTx), "R", false)
+		cell(40, won(line.Income+line.LocalTx), "R", false)
+		pdf.Ln(rowHeight)
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(190, 7, "위의 원천징수액(근로소득)을 정히 영수(지급)합니다.", "", 1, "C", false, 0, "")
+	pdf.CellFormat(190, 7, issued.Format("2006년 01월 02일"), "", 1, "R", false, 0, "")
+	pdf.CellFormat(190, 7, fmt.Sprintf("징수(보고)의무자  %s  (서명 또는 인)", agent.Name), "", 1, "R", false, 0, "")
+}
+
+// formatRegistrationNumber writes a 10-digit 사업자등록번호 as 123-45-67890
+func formatRegistrationNumber(number string) string {
+	if len(number) != 10 {
+		return number
+	}
+	return number[:3] + "-" + number[3:5] + "-" + number[5:]
+}
+
+func withholdingReceiptsPDF(agent withholdingAgent, receipts []*withholdingReceipt) ([]byte, error) {
+	pdf, err := newKoreanPDF("P")
+	if err != nil {
+		return nil, err
+	}
+	pdf.SetMargins(10, 10, 10)
+	issued := time.Now()
+	for _, r := range receipts {
+		addWithholdingReceiptPage(pdf, agent, r, issued)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// withholdingStatementRecordLength is the record length of the 근로소득 지급명세서 e-filing file
+const withholdingStatementRecordLength = 2010
+
+// fixedAmount is a zero-padded amount field; a negative amount (refund) keeps its sign in the first byte
+func fixedAmount(m models.Money, width int) []byte {
+	return []byte(fmt.Sprintf("%0*d", width, int64(m)))
+}
+
+// submitterType is the 제출자 구분 of the A record: 2 for a 법인, whose 사업자등록번호 has 81 to 88 in
+// the fourth and fifth digits, else 3 for an individual business
+func submitterType(registrationNumber string) string {
+	if len(registrationNumber) == 10 && registrationNumber[3:5] >= "81" && registrationNumber[3:5] <= "88" {
+		return "2"
+	}
+	return "3"
+}
+
+// buildWithholdingStatementFile writes the 근로소득 지급명세서 전산매체 file: an A record for the
+// submitter, a B record with the withholding agent's totals and a C record per income earner, each
+// withholdingStatementRecordLength bytes in EUC-KR. The fields follow the 전산매체 제출요령 layout in
+// order; the positions in the comments are 1-based byte offsets. 종전근무처 (D) and 부양가족 (E)
+// records are not produced as the system keeps no data for them.
+func buildWithholdingStatementFile(agent withholdingAgent, receipts []*withholdingReceipt, submitted time.Time) []byte {
+	const incomeType = "20" // 자료구분: 근로소득
+
+	var totalPay, determinedTax, determinedLocalTax models.Money
+	for _, r := range receipts {
+		totalPay += r.Settlement.TotalPay
+		determinedTax += r.Settlement.DeterminedTax
+		determinedLocalTax += r.Settlement.DeterminedLocalTax
+	}
+
+	var buf bytes.Buffer
+	buf.Write(fixedRecord(withholdingStatementRecordLength,
+		[]byte("A"), []byte(incomeType), fixedField(agent.TaxOfficeCode, 3), // 1, 2, 4
+		[]byte(submitted.Format("20060102")), []byte(submitterType(agent.RegistrationNumber)), // 7 제출연월일, 15 제출자 구분
+		fixedField("", 6), fixedField(agent.HometaxID, 20), []byte("9000"), // 16 세무대리인 관리번호, 22 홈택스 ID, 42 세무프로그램코드 (기타)
+		fixedField(agent.RegistrationNumber, 10), fixedField(agent.Name, 40), // 46, 56 상호
+		fixedField("", 30), fixedField(agent.Representative, 30), // 96 담당자 부서, 126 담당자 성명
+		fixedField(strings.ReplaceAll(agent.Phone, "-", ""), 15), []byte("00001"), []byte("101"))) // 156, 171 신고의무자 수, 176 한글코드 (KS X 1001)
+
+	buf.Write(fixedRecord(withholdingStatementRecordLength,
+		[]byte("B"), []byte(incomeType), fixedField(agent.TaxOfficeCode, 3), []byte("000001"), // 1, 2, 4, 7 일련번호
+		fixedField(agent.RegistrationNumber, 10), fixedField(agent.Name, 40), fixedField(agent.Representative, 30), // 13, 23, 63
+		fixedField("", 13), []byte(fmt.Sprintf("%07d", len(receipts))), []byte("0000000"), // 93 법인등록번호, 106 C 레코드 수, 113 D 레코드 수
+		fixedAmount(totalPay, 14), fixedAmount(determinedTax, 13), fixedAmount(determinedLocalTax, 13), // 120, 134, 147
+		fixedAmount(0, 13), fixedAmount(determinedTax+determinedLocalTax, 13), []byte("1"))) // 160 농어촌특별세, 173 결정세액 계, 186 제출대상기간 (연간)
+
+	for i, r := range receipts {
+		s := r.Settlement
+		settlementType := "1" // 계속근로
+		if r.WorkTo.Before(time.Date(s.TaxYear, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+			settlementType = "2" // 중도퇴사
+		}
+		buf.Write(fixedRecord(withholdingStatementRecordLength,
+			[]byte("C"), []byte(incomeType), fixedField(agent.TaxOfficeCode, 3), []byte(fmt.Sprintf("%06d", i+1)), // 1, 2, 4, 7
+			fixedField(agent.RegistrationNumber, 10), []byte("00"), []byte("1"), fixedField("", 2), // 13, 23 종전근무처 수, 25 거주자, 26 거주지국
+			[]byte("2"), []byte("2"), fixedField(r.EmployeeName, 30), []byte("1"), // 28 외국인 단일세율, 29 파견근로자, 30 성명, 60 내국인
+			fixedField(r.ResidentNumber, 13), fixedField("", 2), []byte("1"), []byte(settlementType), // 61, 74 국적, 76 세대주, 77 연말정산 구분
+			[]byte("2"), fixedField("", 4), []byte("2"), // 78 사업자단위과세, 79 종사업장 일련번호, 83 종교관련종사자
+			fixedField(agent.RegistrationNumber, 10), fixedField(agent.Name, 40), // 84 주(현)근무처, 94 근무처명
+			[]byte(r.WorkFrom.Format("20060102")), []byte(r.WorkTo.Format("20060102")), fixedAmount(0, 8), fixedAmount(0, 8), // 134, 142, 150, 158 감면기간
+			fixedAmount(r.Salary, 11), fixedAmount(r.Bonus, 11), fixedAmount(0, 11), fixedAmount(0, 11), // 166 급여, 177 상여, 188 인정상여, 199 주식매수선택권
+			fixedAmount(0, 11), fixedAmount(0, 11), fixedAmount(0, 11), fixedAmount(r.Salary+r.Bonus, 11), // 210 우리사주, 221 임원퇴직한도초과, 232 직무발명보상금, 243 계
+			fixedAmount(s.NonTaxablePay, 10), fixedAmount(s.TotalPay, 11), // 254 비과세 계, 264 총급여
+			fixedAmount(s.EarnedIncomeDeduction, 10), fixedAmount(s.EarnedIncomeAmount, 11), // 275, 285
+			fixedAmount(s.PersonalDeduction, 8), fixedAmount(s.PensionDeduction, 10), // 296 인적공제, 304 연금보험료공제
+			fixedAmount(s.SpecialIncomeDeduction, 10), fixedAmount(s.CardDeduction, 8), fixedAmount(s.TaxBase, 11), // 314, 324, 332 과세표준
+			fixedAmount(s.CalculatedTax, 10), fixedAmount(s.EarnedIncomeTaxCredit, 8), // 343 산출세액, 353
+			fixedAmount(s.SpecialTaxCredit, 10), fixedAmount(s.StandardTaxCredit, 8), // 361, 371
+			fixedAmount(s.DeterminedTax, 10), fixedAmount(s.DeterminedLocalTax, 10), // 379 결정세액, 389
+			fixedAmount(s.WithheldTax, 10), fixedAmount(s.WithheldLocalTax, 10), // 399 기납부세액, 409
+			fixedAmount(s.BalanceTax, 10), fixedAmount(s.BalanceLocalTax, 10))) // 419 차감징수세액, 429
+	}
+	return buf.Bytes()
+}
+
+// respondWithholdingReceiptError reports a failure to load receipts
+func respondWithholdingReceiptError(c *gin.Context, err error) {
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee has no payroll in the tax year"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load withholding receipt"})
+}
+
+// GetEmployeeWithholdingReceipt returns the employee's 근로소득 원천징수영수증 of the tax year as PDF
+func GetEmployeeWithholdingReceipt(c *gin.Context) {
+	employeeID, taxYear, ok := yearEndParams(c)
+	if !ok {
+		return
+	}
+
+	agent, err := loadWithholdingAgent(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	receipts, err := loadWithholdingReceipts(database.DB, taxYear, employeeID)
+	if err != nil {
+		respondWithholdingReceiptError(c, err)
+		return
+	}
+
+	content, err := withholdingReceiptsPDF(agent, receipts)
+	if err != nil {
+		respondPDFError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=withholding_receipt_%d_%s.pdf", taxYear, receipts[0].EmployeeNumber))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// GetWithholdingReceipts returns the receipts of every employee paid in the tax year (tax_year) as
+// one PDF, a page per employee
+func GetWithholdingReceipts(c *gin.Context) {
+	taxYear, err := strconv.Atoi(c.Query("tax_year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tax_year is required"})
+		return
+	}
+
+	agent, err := loadWithholdingAgent(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	receipts, err := loadWithholdingReceipts(database.DB, taxYear, 0)
+	if err != nil {
+		respondWithholdingReceiptError(c, err)
+		return
+	}
+	if len(receipts) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No payroll in the tax year"})
+		return
+	}
+
+	content, err := withholdingReceiptsPDF(agent, receipts)
+	if err != nil {
+		respondPDFError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=withholding_receipts_%d.pdf", taxYear))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// respondWithholdingStatementFile writes the 지급명세서 e-filing file of the tax year for every employee
+// paid in it, or for one employee. Every employee needs a registered resident number and the settings
+// need the 사업자등록번호 and 관할 세무서 코드.
+func respondWithholdingStatementFile(c *gin.Context, taxYear, employeeID int) {
+	agent, err := loadWithholdingAgent(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if len(agent.RegistrationNumber) != 10 || agent.TaxOfficeCode == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Set company_registration_number and tax_office_code in the system settings first"})
+		return
+	}
+
+	receipts, err := loadWithholdingReceipts(database.DB, taxYear, employeeID)
+	if err != nil {
+		respondWithholdingReceiptError(c, err)
+		return
+	}
+	if len(receipts) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No payroll in the tax year"})
+		return
+	}
+
+	var missing []string
+	for _, r := range receipts {
+		if r.ResidentNumber == "" {
+			missing = append(missing, r.EmployeeNumber)
+		}
+	}
+	if len(missing) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Resident numbers are not registered", "employee_numbers": missing})
+		return
+	}
+
+	content := buildWithholdingStatementFile(agent, receipts, time.Now())
+	c.Header("Content-Disposition", "attachment; filename=C"+agent.RegistrationNumber[:7]+"."+agent.RegistrationNumber[7:])
+	c.Data(http.StatusOK, "text/plain; charset=euc-kr", content)
+}
+
+// GetEmployeeWithholdingStatementFile returns the employee's 지급명세서 e-filing file of the tax year,
+// e.g. to file for a leaver
+func GetEmployeeWithholdingStatementFile(c *gin.Context) {
+	employeeID, taxYear, ok := yearEndParams(c)
+	if !ok {
+		return
+	}
+	respondWithholdingStatementFile(c, taxYear, employeeID)
+}
+
+// GetWithholdingStatementFile returns the 지급명세서 e-filing file of the tax year (tax_year) for every
+// employee paid in it
+func GetWithholdingStatementFile(c *gin.Context) {
+	taxYear, err := strconv.Atoi(c.Query("tax_year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tax_year is required"})
+		return
+	}
+	respondWithholdingStatementFile(c, taxYear, 0)
+}
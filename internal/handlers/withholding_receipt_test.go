@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"labor-management-system/internal/models"
+)
+
+// The positions are the 1-based byte offsets of the 근로소득 지급명세서 전산매체 record layout
+func TestBuildWithholdingStatementFile(t *testing.T) {
+	agent := withholdingAgent{
+		Name:               "주식회사 한빛",
+		Representative:     "김대표",
+		RegistrationNumber: "1238112345",
+		Phone:              "02-1234-5678",
+		TaxOfficeCode:      "101",
+		HometaxID:          "hanbit",
+	}
+	receipts := []*withholdingReceipt{
+		{
+			EmployeeNumber: "E001",
+			EmployeeName:   "홍길동",
+			ResidentNumber: "9005051234567",
+			WorkFrom:       time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			WorkTo:         time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC),
+			Salary:         36000000,
+			Bonus:          4000000,
+			Settlement: &models.YearEndSettlement{TaxYear: 2025, TotalPay: 40000000, DeterminedTax: 1200000,
+				DeterminedLocalTax: 120000, WithheldTax: 1500000, WithheldLocalTax: 150000,
+				BalanceTax: -300000, BalanceLocalTax: -30000},
+		},
+		{
+			EmployeeNumber: "E002",
+			// 20 Hangul characters are 40 bytes in EUC-KR; the name is cut to the 30-byte field
+			EmployeeName:   "가나다라마바사아자차카타파하가나다라마바",
+			ResidentNumber: "9512122345678",
+			WorkFrom:       time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC),
+			WorkTo:         time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC),
+			Salary:         12000000,
+			Settlement:     &models.YearEndSettlement{TaxYear: 2025, TotalPay: 12000000, DeterminedTax: 50000, DeterminedLocalTax: 5000},
+		},
+	}
+
+	content := buildWithholdingStatementFile(agent, receipts, time.Date(2026, time.February, 27, 0, 0, 0, 0, time.UTC))
+	records := bytes.Split(bytes.TrimSuffix(content, []byte("\r\n")), []byte("\r\n"))
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+	for i, record := range records {
+		if len(record) != withholdingStatementRecordLength {
+			t.Errorf("record %d is %d bytes, want %d", i+1, len(record), withholdingStatementRecordLength)
+		}
+	}
+
+	field := func(text string, width int) string { return string(fixedField(text, width)) }
+	tests := []struct {
+		name     string
+		record   int
+		position int
+		want     string
+	}{
+		{"A 레코드 구분", 0, 1, "A20101"},
+		{"A 제출연월일", 0, 7, "20260227"},
+		{"A 제출자 구분 (법인)", 0, 15, "2"},
+		{"A 홈택스 ID", 0, 22, field("hanbit", 20)},
+		{"A 세무프로그램코드", 0, 42, "9000"},
+		{"A 사업자등록번호", 0, 46, "1238112345"},
+		{"A 상호", 0, 56, field("주식회사 한빛", 40)},
+		{"A 담당자 전화번호", 0, 156, field("0212345678", 15)},
+		{"A 신고의무자 수와 한글코드", 0, 171, "00001101"},
+		{"B 레코드 구분과 일련번호", 1, 1, "B20101000001"},
+		{"B 사업자등록번호", 1, 13, "1238112345"},
+		{"B 대표자", 1, 63, field("김대표", 30)},
+		{"B C 레코드 수와 D 레코드 수", 1, 106, "00000020000000"},
+		{"B 소득금액 총계", 1, 120, "00000052000000"},
+		{"B 결정세액 소득세", 1, 134, "0000001250000"},
+		{"B 결정세액 지방소득세", 1, 147, "0000000125000"},
+		{"B 결정세액 계와 제출대상기간", 1, 173, "00000013750001"},
+		{"C 레코드 구분과 일련번호", 2, 1, "C20101000001"},
+		{"C 성명", 2, 30, field("홍길동", 30)},
+		{"C 주민등록번호", 2, 61, "9005051234567"},
+		{"C 계속근로", 2, 77, "1"},
+		{"C 근무처 사업자등록번호", 2, 84, "1238112345"},
+		{"C 근무기간", 2, 134, "2025010120251231"},
+		{"C 급여와 상여", 2, 166, "0003600000000004000000"},
+		{"C 계", 2, 243, "00040000000"},
+		{"C 총급여", 2, 264, "00040000000"},
+		{"C 결정세액", 2, 379, "00012000000000120000"},
+		{"C 기납부세액", 2, 399, "00015000000000150000"},
+		{"C 차감징수세액 (환급)", 2, 419, "-000300000-000030000"},
+		{"C 차감징수세액 뒤는 공란", 2, 439, "   "},
+		{"C 두 번째 일련번호", 3, 7, "000002"},
+		{"C 성명은 글자 단위로 자름", 3, 30, field("가나다라마바사아자차카타파하가", 30)},
+		{"C 중도퇴사", 3, 77, "2"},
+		{"C 중도퇴사자 근무기간", 3, 134, "2025030220250630"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := records[tt.record]
+			start := tt.position - 1
+			if got := string(record[start : start+len(tt.want)]); got != tt.want {
+				t.Errorf("position %d = %q, want %q", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmitterType(t *testing.T) {
+	tests := []struct {
+		registrationNumber string
+		want               string
+	}{
+		{"1238112345", "2"},
+		{"1238812345", "2"},
+		{"1230112345", "3"},
+		{"1239012345", "3"},
+	}
+	for _, tt := range tests {
+		if got := submitterType(tt.registrationNumber); got != tt.want {
+			t.Errorf("submitterType(%s) = %s, want %s", tt.registrationNumber, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withholdingIncomeCodes are the 소득구분 rows of the 원천징수이행상황신고서 reported, in form order.
+// Subtotal rows add up the detail rows whose code starts with Prefix.
+var withholdingIncomeCodes = []struct {
+	Code     string
+	Name     string
+	Subtotal bool
+	Prefix   string
+}{
+	{"A01", "근로소득 간이세액", false, ""},
+	{"A02", "근로소득 중도퇴사", false, ""},
+	{"A03", "근로소득 일용근로", false, ""},
+	{"A04", "근로소득 연말정산", false, ""},
+	{"A10", "근로소득 가감계", true, "A0"},
+	{"A22", "퇴직소득 그 외", false, ""},
+	{"A20", "퇴직소득 가감계", true, "A2"},
+	{"A99", "총합계", true, "A"},
+}
+
+// paidDateExpr is the date a payroll record counts as paid: its pay date, else the planned pay date
+// of its run, else the end of its pay period
+const paidDateExpr = "COALESCE(p.pay_date, r.pay_date, p.pay_period_end)"
+
+// reportedPayrollCond limits the report to pay actually made or approved for payment; drafts and runs
+// still under review have withheld nothing yet
+const reportedPayrollCond = "(p.is_paid = 1 OR r.status IN ('approved', 'paid'))"
+
+// withholdingStatusLine is a 소득구분 row; TotalPay is the taxable and non-taxable pay together
+type withholdingStatusLine struct {
+	Code          string       `json:"code"`
+	Name          string       `json:"name"`
+	Headcount     int          `json:"headcount"`
+	TotalPay      models.Money `json:"total_pay"`
+	TaxablePay    models.Money `json:"taxable_pay"`
+	NonTaxablePay models.Money `json:"non_taxable_pay"`
+	IncomeTax     models.Money `json:"income_tax"`
+	LocalTax      models.Money `json:"local_tax"`
+}
+
+func (l *withholdingStatusLine) add(o withholdingStatusLine) {
+	l.Headcount += o.Headcount
+	l.TotalPay += o.TaxablePay + o.NonTaxablePay
+	l.TaxablePay += o.TaxablePay
+	l.NonTaxablePay += o.NonTaxablePay
+	l.IncomeTax += o.IncomeTax
+	l.LocalTax += o.LocalTax
+}
+
+// withholdingStatusTax settles the month's tax: refunds from year-end and leaver settlements are
+// offset against the tax withheld, and what cannot be offset is carried over to next month
+type withholdingStatusTax struct {
+	Withheld        models.Money `json:"withheld"`         // 징수세액 (환급 제외)
+	Refund          models.Money `json:"refund"`           // 당월 발생 환급세액
+	RefundOffset    models.Money `json:"refund_offset"`    // 당월조정환급세액
+	Payable         models.Money `json:"payable"`          // 납부세액
+	RefundCarryover models.Money `json:"refund_carryover"` // 차월이월환급세액
+}
+
+func settleWithholdingTax(amounts []models.Money) withholdingStatusTax {
+	var t withholdingStatusTax
+	for _, amount := range amounts {
+		if amount >= 0 {
+			t.Withheld += amount
+		} else {
+			t.Refund -= amount
+		}
+	}
+	t.RefundOffset = minMoney(t.Refund, t.Withheld)
+	t.Payable = t.Withheld - t.RefundOffset
+	t.RefundCarryover = t.Refund - t.RefundOffset
+	return t
+}
+
+// withholdingStatusReport is the 원천징수이행상황신고서 of the pay made in a month
+type withholdingStatusReport struct {
+	PayMonth    string                  `json:"pay_month"`
+	Company     ledgerCompany           `json:"company"`
+	Lines       []withholdingStatusLine `json:"lines"`
+	IncomeTax   withholdingStatusTax    `json:"income_tax"`
+	LocalTax    withholdingStatusTax    `json:"local_tax"`
+	GeneratedAt time.Time               `json:"generated_at"`
+}
+
+// loadWithholdingStatus sums the pay made in the month by income code: monthly withholding of daily
+// workers (A03) and everyone else (A01), the settlement of employees who left with this month's pay
+// (A02), the year-end settlements posted to it (A04) and the severance pay the company paid and
+// withheld itself (A22)
+func loadWithholdingStatus(db dbtx, month time.Time) (*withholdingStatusReport, error) {
+	from, until := month.Format("2006-01-02"), month.AddDate(0, 1, 0).Format("2006-01-02")
+
+	settings, err := loadSettings(db, "company_name", "company_registration_number", "company_address", "company_phone")
+	if err != nil {
+		return nil, err
+	}
+	report := &withholdingStatusReport{
+		PayMonth: month.Format("2006-01"),
+		Company: ledgerCompany{
+			Name:               settings["company_name"],
+			RegistrationNumber: settings["company_registration_number"],
+			Address:            settings["company_address"],
+			Phone:              settings["company_phone"],
+		},
+		GeneratedAt: time.Now(),
+	}
+	lines := make(map[string]*withholdingStatusLine)
+	for _, code := range withholdingIncomeCodes {
+		lines[code.Code] = &withholdingStatusLine{Code: code.Code, Name: code.Name}
+	}
+
+	rows, err := db.Query(`
+		SELECT CASE WHEN p.salary_type = 'daily' THEN 'A03' ELSE 'A01' END AS code,
+		       COUNT(DISTINCT p.employee_id), COALESCE(SUM(p.taxable_pay), 0), COALESCE(SUM(p.non_taxable_pay), 0),
+		       COALESCE(SUM(p.income_tax), 0), COALESCE(SUM(p.local_tax), 0)
+		FROM payroll_records p
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE `+reportedPayrollCond+` AND `+paidDateExpr+` >= ? AND `+paidDateExpr+` < ?
+		GROUP BY CASE WHEN p.salary_type = 'daily' THEN 'A03' ELSE 'A01' END
+	`, from, until)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var line withholdingStatusLine
+		if err := rows.Scan(&line.Code, &line.Headcount, &line.TaxablePay, &line.NonTaxablePay, &line.IncomeTax, &line.LocalTax); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lines[line.Code].add(line)
+	}
+	rows.Close()
+
+	// Adjustments of earlier payroll are withheld with the payroll they are settled with
+	rows, err = db.Query(`
+		SELECT CASE WHEN p.salary_type = 'daily' THEN 'A03' ELSE 'A01' END AS code,
+		       COALESCE(SUM(a.taxable_pay), 0), COALESCE(SUM(a.non_taxable_pay), 0),
+		       COALESCE(SUM(a.income_tax), 0), COALESCE(SUM(a.local_tax), 0)
+		FROM payroll_adjustments a
+		JOIN payroll_records p ON p.id = a.applied_payroll_id
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE a.status = 'applied' AND `+reportedPayrollCond+` AND `+paidDateExpr+` >= ? AND `+paidDateExpr+` < ?
+		GROUP BY CASE WHEN p.salary_type = 'daily' THEN 'A03' ELSE 'A01' END
+	`, from, until)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var line withholdingStatusLine
+		if err := rows.Scan(&line.Code, &line.TaxablePay, &line.NonTaxablePay, &line.IncomeTax, &line.LocalTax); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lines[line.Code].add(line)
+	}
+	rows.Close()
+
+	var settled withholdingStatusLine
+	err = db.QueryRow(`
+		SELECT COUNT(DISTINCT s.employee_id), COALESCE(SUM(s.total_pay), 0), COALESCE(SUM(s.non_taxable_pay), 0),
+		       COALESCE(SUM(s.balance_tax), 0), COALESCE(SUM(s.balance_local_tax), 0)
+		FROM year_end_settlements s
+		JOIN payroll_records p ON p.id = s.payroll_id
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE s.status = 'posted' AND `+reportedPayrollCond+` AND `+paidDateExpr+` >= ? AND `+paidDateExpr+` < ?
+	`, from, until).Scan(&settled.Headcount, &settled.TaxablePay, &settled.NonTaxablePay, &settled.IncomeTax, &settled.LocalTax)
+	if err != nil {
+		return nil, err
+	}
+	lines["A04"].add(settled)
+
+	// Pension providers withhold on DB and DC benefits and file them themselves
+	var severance withholdingStatusLine
+	err = db.QueryRow(`
+		SELECT COUNT(DISTINCT employee_id), COALESCE(SUM(severance_pay), 0),
+		       COALESCE(SUM(income_tax), 0), COALESCE(SUM(local_tax), 0)
+		FROM severance_settlements
+		WHERE status = 'paid' AND pension_type = 'severance' AND pay_date >= ? AND pay_date < ?
+	`, from, until).Scan(&severance.Headcount, &severance.TaxablePay, &severance.IncomeTax, &severance.LocalTax)
+	if err != nil {
+		return nil, err
+	}
+	lines["A22"].add(severance)
+
+	// Leavers are settled with their last pay; tax withheld from daily workers is final
+	rows, err = db.Query(`
+		SELECT p.employee_id, p.pay_period_start
+		FROM payroll_records p
+		JOIN employees e ON e.id = p.employee_id
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE e.status = 'terminated' AND COALESCE(p.salary_type, 'monthly') != 'daily'
+		  AND `+reportedPayrollCond+` AND `+paidDateExpr+` >= ? AND `+paidDateExpr+` < ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM payroll_records later
+			WHERE later.employee_id = p.employee_id AND later.pay_period_start > p.pay_period_start
+		  )
+	`, from, until)
+	if err != nil {
+		return nil, err
+	}
+	type leaver struct {
+		employeeID int
+		lastPeriod time.Time
+	}
+	var leavers []leaver
+	for rows.Next() {
+		var l leaver
+		if err := rows.Scan(&l.employeeID, &l.lastPeriod); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		leavers = append(leavers, l)
+	}
+	rows.Close()
+	for _, l := range leavers {
+		receipt, err := loadWithholdingReceipt(db, l.employeeID, l.lastPeriod.Year())
+		if err != nil {
+			return nil, err
+		}
+		s := receipt.Settlement
+		lines["A02"].add(withholdingStatusLine{
+			Headcount:     1,
+			TaxablePay:    s.TotalPay,
+			NonTaxablePay: s.NonTaxablePay,
+			IncomeTax:     s.BalanceTax,
+			LocalTax:      s.BalanceLocalTax,
+		})
+	}
+
+	var incomeTaxes, localTaxes []models.Money
+	for _, code := range withholdingIncomeCodes {
+		line := lines[code.Code]
+		if code.Subtotal {
+			for _, detail := range withholdingIncomeCodes {
+				if !detail.Subtotal && strings.HasPrefix(detail.Code, code.Prefix) {
+					line.add(*lines[detail.Code])
+				}
+			}
+		} else {
+			incomeTaxes = append(incomeTaxes, line.IncomeTax)
+			localTaxes = append(localTaxes, line.LocalTax)
+		}
+		report.Lines = append(report.Lines, *line)
+	}
+	report.IncomeTax = settleWithholdingTax(incomeTaxes)
+	report.LocalTax = settleWithholdingTax(localTaxes)
+	return report, nil
+}
+
+type withholdingStatusSummaryRow struct {
+	Title     string
+	IncomeTax models.Money
+	LocalTax  models.Money
+}
+
+// withholdingStatusSummary are the settlement rows shown below the income codes
+func withholdingStatusSummary(report *withholdingStatusReport) []withholdingStatusSummaryRow {
+	return []withholdingStatusSummaryRow{
+		{"징수세액", report.IncomeTax.Withheld, report.LocalTax.Withheld},
+		{"당월 발생 환급세액", report.IncomeTax.Refund, report.LocalTax.Refund},
+		{"당월조정환급세액", report.IncomeTax.RefundOffset, report.LocalTax.RefundOffset},
+		{"납부세액", report.IncomeTax.Payable, report.LocalTax.Payable},
+		{"차월이월환급세액", report.IncomeTax.RefundCarryover, report.LocalTax.RefundCarryover},
+	}
+}
+
+// withholdingStatusCSV writes the report in EUC-KR for entry into Hometax: a row per income code,
+// then the settlement of the tax
+func withholdingStatusCSV(report *withholdingStatusReport) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(eucKR().Writer(&buf))
+	money := func(m models.Money) string { return strconv.FormatInt(int64(m), 10) }
+
+	writer.Write([]string{"지급연월", "코드", "소득구분", "인원", "총지급액", "과세소득", "비과세소득", "소득세", "지방소득세"})
+	for _, line := range report.Lines {
+		writer.Write([]string{report.PayMonth, line.Code, line.Name, strconv.Itoa(line.Headcount),
+			money(line.TotalPay), money(line.TaxablePay), money(line.NonTaxablePay), money(line.IncomeTax), money(line.LocalTax)})
+	}
+	for _, row := range withholdingStatusSummary(report) {
+		writer.Write([]string{report.PayMonth, "", row.Title, "", "", "", "", money(row.IncomeTax), money(row.LocalTax)})
+	}
+	writer.Flush()
+	return buf.Bytes()
+}
+
+// withholdingStatusPDF prints the report in the layout of 소득세법 시행규칙 별지 제21호서식
+func withholdingStatusPDF(report *withholdingStatusReport) ([]byte, error) {
+	pdf, err := newKoreanPDF("P")
+	if err != nil {
+		return nil, err
+	}
+	pdf.SetMargins(10, 10, 10)
+	pdf.AddPage()
+
+	const rowHeight = 7.0
+	cell := func(width float64, text, align string, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont(pdfFont, style, 8)
+		pdf.CellFormat(width, rowHeight, text, "1", 0, align, false, 0, "")
+	}
+	won := func(m models.Money) string { return groupThousands(int64(m)) }
+
+	pdf.SetFont(pdfFont, "", 8)
+	pdf.CellFormat(190, 5, "[소득세법 시행규칙 별지 제21호서식]", "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFont, "B", 15)
+	pdf.CellFormat(190, 10, "원천징수이행상황신고서", "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.CellFormat(190, 6, fmt.Sprintf("지급연월 %s  (매월분)", report.PayMonth), "", 1, "C", false, 0, "")
+	pdf.Ln(3)
+
+	cell(30, "법인명(상호)", "C", true)
+	cell(65, report.Company.Name, "L", false)
+	cell(30, "사업자등록번호", "C", true)
+	cell(65, report.Company.RegistrationNumber, "L", false)
+	pdf.Ln(rowHeight)
+	cell(30, "사업장 소재지", "C", true)
+	cell(65, report.Company.Address, "L", false)
+	cell(30, "전화번호", "C", true)
+	cell(65, report.Company.Phone, "L", false)
+	pdf.Ln(rowHeight + 4)
+
+	pdf.SetFont(pdfFont, "B", 9)
+	pdf.CellFormat(190, rowHeight, "1. 원천징수 명세 및 납부세액", "", 1, "L", false, 0, "")
+	widths := []float64{34, 11, 11, 27, 27, 27, 27, 26}
+	for i, title := range []string{"소득자 소득구분", "코드", "인원", "총지급액", "과세소득", "비과세소득", "소득세 등", "지방소득세"} {
+		cell(widths[i], title, "C", true)
+	}
+	pdf.Ln(rowHeight)
+	for _, line := range report.Lines {
+		subtotal := line.Code == "A10" || line.Code == "A20" || line.Code == "A99"
+		cell(widths[0], line.Name, "L", subtotal)
+		cell(widths[1], line.Code, "C", subtotal)
+		cell(widths[2], strconv.Itoa(line.Headcount), "R", subtotal)
+		cell(widths[3], won(line.TotalPay), "R", subtotal)
+		cell(widths[4], won(line.TaxablePay), "R", subtotal)
+		cell(widths[5], won(line.NonTaxablePay), "R", subtotal)
+		cell(widths[6], won(line.IncomeTax), "R", subtotal)
+		cell(widths[7], won(line.LocalTax), "R", subtotal)
+		pdf.Ln(rowHeight)
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont(pdfFont, "B", 9)
+	pdf.CellFormat(190, rowHeight, "2. 환급세액 조정 및 납부세액", "", 1, "L", false, 0, "")
+	cell(75, "구분", "C", true)
+	cell(40, "소득세 등", "C", true)
+	cell(35, "지방소득세", "C", true)
+	cell(40, "계", "C", true)
+	pdf.Ln(rowHeight)
+	for _, row := range withholdingStatusSummary(report) {
+		cell(75, row.Title, "L", true)
+		cell(40, won(row.IncomeTax), "R", false)
+		cell(35, won(row.LocalTax), "R", false)
+		cell(40, won(row.IncomeTax+row.LocalTax), "R", false)
+		pdf.Ln(rowHeight)
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(190, 7, "「소득세법」 제128조에 따라 위와 같이 원천징수이행상황을 신고합니다.", "", 1, "C", false, 0, "")
+	pdf.CellFormat(190, 7, report.GeneratedAt.Format("2006년 01월 02일"), "", 1, "R", false, 0, "")
+	pdf.CellFormat(190, 7, fmt.Sprintf("신고인(원천징수의무자)  %s  (서명 또는 인)", report.Company.Name), "", 1, "R", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetWithholdingStatusReport returns the 원천징수이행상황신고서 of the pay made in a month
+// (pay_month=YYYY-MM, default this month) as JSON, PDF in the form layout or CSV (format)
+func GetWithholdingStatusReport(c *gin.Context) {
+	month, err := time.Parse("2006-01", c.DefaultQuery("pay_month", time.Now().Format("2006-01")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay month format (YYYY-MM)"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "pdf" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be json, pdf or csv"})
+		return
+	}
+
+	report, err := loadWithholdingStatus(database.DB, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load withholding status"})
+		return
+	}
+
+	switch format {
+	case "pdf":
+		content, err := withholdingStatusPDF(report)
+		if err != nil {
+			respondPDFError(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=withholding_status_%s.pdf", report.PayMonth))
+		c.Data(http.StatusOK, "application/pdf", content)
+	case "csv":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=withholding_status_%s.csv", report.PayMonth))
+		c.Data(http.StatusOK, "text/csv; charset=euc-kr", withholdingStatusCSV(report))
+	default:
+		c.JSON(http.StatusOK, report)
+	}
+}
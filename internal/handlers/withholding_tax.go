@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTableDependents is the largest dependent count with its own column in the 간이세액표
+const maxTableDependents = 11
+
+// withholdingExcessThreshold is the monthly pay up to which the 간이세액표 lists the tax.
+// Pay above it adds the tax of the matching excessPayBracket to the tax at the threshold.
+const withholdingExcessThreshold = 10000000
+
+type excessPayBracket struct {
+	Over    float64
+	BaseTax float64
+	Rate    float64
+}
+
+// withholdingExcessBrackets are the 1천만원 초과 rules of the 간이세액표 (소득세법 시행령 별표 2), highest first
+var withholdingExcessBrackets = []excessPayBracket{
+	{87000000, 31034600, 0.45},
+	{45000000, 13394600, 0.42},
+	{30000000, 7394600, 0.40},
+	{28000000, 6610600, 0.98 * 0.40},
+	{14000000, 1397000, 0.98 * 0.38},
+	{10000000, 25000, 0.98 * 0.35},
+}
+
+// withholdingRates are the ratios of the table tax an employee may choose to have withheld
+var withholdingRates = map[int]bool{80: true, 100: true, 120: true}
+
+// errNoWithholdingTable is returned when no 간이세액표 is in force for a pay period
+var errNoWithholdingTable = errors.New("No withholding tax table is in force for the pay period; import the NTS table first")
+
+// withholdingBracket is one row of the 간이세액표: the tax per dependent count for pay in [From, To)
+type withholdingBracket struct {
+	From  float64
+	To    float64
+	Taxes [maxTableDependents]float64
+}
+
+type withholdingTable struct {
+	ID            int
+	Name          string
+	EffectiveDate time.Time
+	Brackets      []withholdingBracket
+}
+
+type WithholdingSettingsRequest struct {
+	Dependents      int     `json:"dependents" binding:"required"`
+	WithholdingRate int     `json:"withholding_rate" binding:"required"`
+	ResidentNumber  *string `json:"resident_number"` // 주민등록번호, 생략 시 기존 값 유지
+}
+
+// taxFor returns the tax of a row for the dependent count. Beyond 11 dependents each further
+// dependent lowers the tax by the difference between the 10 and 11 dependent columns.
+func (b withholdingBracket) taxFor(dependents int) float64 {
+	if dependents < 1 {
+		dependents = 1
+	}
+	if dependents <= maxTableDependents {
+		return b.Taxes[dependents-1]
+	}
+
+	step := b.Taxes[maxTableDependents-2] - b.Taxes[maxTableDependents-1]
+	return math.Max(b.Taxes[maxTableDependents-1]-step*float64(dependents-maxTableDependents), 0)
+}
+
+// monthlyTax looks up the monthly income tax on taxable pay before the withholding ratio
+func (t *withholdingTable) monthlyTax(pay float64, dependents int) float64 {
+	if len(t.Brackets) == 0 || pay < t.Brackets[0].From {
+		return 0
+	}
+
+	if pay >= withholdingExcessThreshold {
+		base := t.Brackets[0]
+		for _, b := range t.Brackets {
+			if b.From <= withholdingExcessThreshold {
+				base = b
+			}
+		}
+		tax := base.taxFor(dependents)
+		for _, excess := range withholdingExcessBrackets {
+			if pay > excess.Over {
+				tax += excess.BaseTax + (pay-excess.Over)*excess.Rate
+				break
+			}
+		}
+		return tax
+	}
+
+	// Pay beyond the last imported row is taxed at that row
+	bracket := t.Brackets[len(t.Brackets)-1]
+	for _, b := range t.Brackets {
+		if pay >= b.From && pay < b.To {
+			bracket = b
+			break
+		}
+	}
+	return bracket.taxFor(dependents)
+}
+
+// incomeTax applies the employee's withholding ratio to the table tax, dropping amounts under 10 won
+func (t *withholdingTable) incomeTax(pay models.Money, dependents, rate int) models.Money {
+	if rate == 0 {
+		rate = 100
+	}
+	tax := t.monthlyTax(pay.Float64(), dependents) * float64(rate) / 100
+	return models.TruncateWon(tax).Truncate(10)
+}
+
+// loadWithholdingTable loads the 간이세액표 in force on the given date
+func loadWithholdingTable(db dbtx, asOf time.Time) (*withholdingTable, error) {
+	var table withholdingTable
+	err := db.QueryRow(`
+		SELECT id, name, effective_date FROM withholding_tax_tables
+		WHERE effective_date <= ?
+		ORDER BY effective_date DESC LIMIT 1
+	`, asOf).Scan(&table.ID, &table.Name, &table.EffectiveDate)
+	if err == sql.ErrNoRows {
+		return nil, errNoWithholdingTable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT pay_from, pay_to, dependents, tax_amount FROM withholding_tax_brackets
+		WHERE table_id = ?
+		ORDER BY pay_from, dependents
+	`, table.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from, to, tax float64
+		var dependents int
+		if err := rows.Scan(&from, &to, &dependents, &tax); err != nil {
+			return nil, err
+		}
+		if dependents < 1 || dependents > maxTableDependents {
+			continue
+		}
+		if n := len(table.Brackets); n == 0 || table.Brackets[n-1].From != from {
+			table.Brackets = append(table.Brackets, withholdingBracket{From: from, To: to})
+		}
+		table.Brackets[len(table.Brackets)-1].Taxes[dependents-1] = tax
+	}
+	return &table, rows.Err()
+}
+
+// employeeWithholding returns the employee's dependent count and chosen withholding ratio
+func employeeWithholding(db dbtx, employeeID int) (int, int, error) {
+	var dependents, rate sql.NullInt64
+	err := db.QueryRow("SELECT dependents, withholding_rate FROM employees WHERE id = ?", employeeID).Scan(&dependents, &rate)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	d, r := int(dependents.Int64), int(rate.Int64)
+	if d < 1 {
+		d = 1
+	}
+	if !withholdingRates[r] {
+		r = 100
+	}
+	return d, r, nil
+}
+
+// applyWithholding sets the 간이세액표 of the pay period and the employee's withholding settings on the calculator
+func (pc *PayrollCalculator) applyWithholding(db dbtx, employeeID int, periodEnd time.Time) error {
+	table, err := loadWithholdingTable(db, periodEnd)
+	if err != nil {
+		return err
+	}
+	pc.WithholdingTable = table
+
+	pc.Dependents, pc.WithholdingRate, err = employeeWithholding(db, employeeID)
+	return err
+}
+
+// respondPayrollCalculatorError reports an error from setting up a payroll calculation
+func respondPayrollCalculatorError(c *gin.Context, err error) {
+	if err == errNoWithholdingTable || err == errNoInsuranceRates {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	respondAllowanceError(c, err)
+}
+
+// parseWithholdingCSV reads the 간이세액표 as published by the NTS: monthly pay from (이상) and to (미만)
+// in thousand won, followed by the tax in won for 1 to 11 dependents. Rows that do not start with
+// an amount, such as headers, are skipped. An empty upper bound closes the row at its lower bound.
+func parseWithholdingCSV(r io.Reader) ([]withholdingBracket, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	parseAmount := func(field string) (float64, error) {
+		field = strings.TrimSpace(strings.ReplaceAll(field, ",", ""))
+		if field == "" || field == "-" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(field, 64)
+	}
+
+	var brackets []withholdingBracket
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		from, err := parseAmount(record[0])
+		if err != nil {
+			continue
+		}
+		if len(record) < 2+maxTableDependents {
+			return nil, fmt.Errorf("line %d: expected %d columns, got %d", line, 2+maxTableDependents, len(record))
+		}
+
+		to, err := parseAmount(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid upper bound %q", line, record[1])
+		}
+		if to == 0 {
+			to = from
+		}
+		if to < from {
+			return nil, fmt.Errorf("line %d: upper bound is below the lower bound", line)
+		}
+
+		bracket := withholdingBracket{From: from * 1000, To: to * 1000}
+		for i := 0; i < maxTableDependents; i++ {
+			tax, err := parseAmount(record[2+i])
+			if err != nil || tax < 0 {
+				return nil, fmt.Errorf("line %d: invalid tax amount %q", line, record[2+i])
+			}
+			bracket.Taxes[i] = tax
+		}
+		brackets = append(brackets, bracket)
+	}
+
+	if len(brackets) == 0 {
+		return nil, errors.New("no tax rows found")
+	}
+
+	sort.Slice(brackets, func(i, j int) bool { return brackets[i].From < brackets[j].From })
+	for i := 1; i < len(brackets); i++ {
+		if brackets[i].From == brackets[i-1].From {
+			return nil, fmt.Errorf("duplicate row for pay from %.0f", brackets[i].From)
+		}
+	}
+	return brackets, nil
+}
+
+func GetWithholdingTaxTables(c *gin.Context) {
+	rows, err := database.DB.Query(`
+		SELECT t.id, t.name, t.effective_date, t.created_by, t.created_at, COUNT(DISTINCT b.pay_from)
+		FROM withholding_tax_tables t
+		LEFT JOIN withholding_tax_brackets b ON b.table_id = t.id
+		GROUP BY t.id, t.name, t.effective_date, t.created_by, t.created_at
+		ORDER BY t.effective_date DESC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	tables := []models.WithholdingTaxTable{}
+	for rows.Next() {
+		var table models.WithholdingTaxTable
+		err := rows.Scan(&table.ID, &table.Name, &table.EffectiveDate, &table.CreatedBy, &table.CreatedAt, &table.BracketCount)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan withholding tax table"})
+			return
+		}
+		tables = append(tables, table)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tables": tables})
+}
+
+// ImportWithholdingTaxTable imports a version of the 간이세액표 from an uploaded CSV file
+// (multipart form fields: name, effective_date, file)
+func ImportWithholdingTaxTable(c *gin.Context) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Table name is required"})
+		return
+	}
+
+	effectiveDate, err := time.Parse("2006-01-02", c.PostForm("effective_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective date format (YYYY-MM-DD)"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer f.Close()
+
+	brackets, err := parseWithholdingCSV(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid withholding tax table: " + err.Error()})
+		return
+	}
+
+	var existingID int
+	err = database.DB.QueryRow("SELECT id FROM withholding_tax_tables WHERE effective_date = ?", effectiveDate).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A withholding tax table already exists for this effective date", "table_id": existingID})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO withholding_tax_tables (name, effective_date, created_by) VALUES (?, ?, ?)
+	`, name, effectiveDate, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create withholding tax table"})
+		return
+	}
+	tableID, _ := result.LastInsertId()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO withholding_tax_brackets (table_id, pay_from, pay_to, dependents, tax_amount)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tax brackets"})
+		return
+	}
+	defer stmt.Close()
+
+	for _, b := range brackets {
+		for i, tax := range b.Taxes {
+			if _, err := stmt.Exec(tableID, b.From, b.To, i+1, tax); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tax brackets"})
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":        "Withholding tax table imported successfully",
+		"id":             tableID,
+		"name":           name,
+		"effective_date": effectiveDate.Format("2006-01-02"),
+		"bracket_count":  len(brackets),
+	})
+}
+
+// LookupWithholdingTax returns the monthly income tax for taxable pay under the table in force on
+// the date (default today)
+func LookupWithholdingTax(c *gin.Context) {
+	pay, err := strconv.ParseFloat(c.Query("pay"), 64)
+	if err != nil || pay < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid monthly taxable pay"})
+		return
+	}
+
+	dependents := 1
+	if d := c.Query("dependents"); d != "" {
+		if dependents, err = strconv.Atoi(d); err != nil || dependents < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dependent count"})
+			return
+		}
+	}
+
+	rate := 100
+	if r := c.Query("withholding_rate"); r != "" {
+		if rate, err = strconv.Atoi(r); err != nil || !withholdingRates[rate] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Withholding rate must be 80, 100 or 120"})
+			return
+		}
+	}
+
+	asOf := time.Now().Truncate(24 * time.Hour)
+	if date := c.Query("date"); date != "" {
+		if asOf, err = time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	table, err := loadWithholdingTable(database.DB, asOf)
+	if err != nil {
+		respondPayrollCalculatorError(c, err)
+		return
+	}
+
+	incomeTax := table.incomeTax(models.Won(pay), dependents, rate)
+	c.JSON(http.StatusOK, gin.H{
+		"table_id":         table.ID,
+		"table_name":       table.Name,
+		"pay":              pay,
+		"dependents":       dependents,
+		"withholding_rate": rate,
+		"income_tax":       incomeTax,
+		"local_tax":        incomeTax.MulRate(0.1).Truncate(10),
+	})
+}
+
+func GetEmployeeWithholding(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	dependents, rate, err := employeeWithholding(database.DB, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	residentNumber, err := employeeResidentNumber(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decrypt resident number"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employee_id":      id,
+		"dependents":       dependents,
+		"withholding_rate": rate,
+		"resident_number":  maskResidentNumber(residentNumber),
+	})
+}
+
+// UpdateEmployeeWithholding sets the employee's dependent count (including the employee) and the
+// 80/100/120% withholding ratio they chose
+func UpdateEmployeeWithholding(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req WithholdingSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Dependents < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dependents must include the employee (at least 1)"})
+		return
+	}
+	if !withholdingRates[req.WithholdingRate] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Withholding rate must be 80, 100 or 120"})
+		return
+	}
+
+	query := "UPDATE employees SET dependents = ?, withholding_rate = ?, updated_at = CURRENT_TIMESTAMP"
+	args := []interface{}{req.Dependents, req.WithholdingRate}
+	if req.ResidentNumber != nil {
+		residentNumber, ok := normalizeResidentNumber(*req.ResidentNumber)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Resident number must be 13 digits (YYMMDD-NNNNNNN)"})
+			return
+		}
+		encrypted, err := encryptPII(residentNumber)
+		if keyErr, ok := err.(*encryptionKeyError); ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": keyErr.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt resident number"})
+			return
+		}
+		query += ", resident_number_encrypted = ?"
+		args = append(args, encrypted)
+	}
+
+	result, err := database.DB.Exec(query+" WHERE id = ?", append(args, id)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update withholding settings"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"employee_id": id, "dependents": req.Dependents, "withholding_rate": req.WithholdingRate})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"testing"
+
+	"labor-management-system/internal/models"
+)
+
+// testWithholdingTable has two rows of an imported 간이세액표. The row amounts are fixtures; what is
+// tested is how the table is applied: the dependent columns, the withholding ratio, the 10 won
+// truncation and the 1천만원 초과 rules of 소득세법 시행령 별표 2.
+var testWithholdingTable = &withholdingTable{
+	Brackets: []withholdingBracket{
+		{From: 3000000, To: 3020000, Taxes: [maxTableDependents]float64{
+			74350, 56850, 31940, 26690, 21440, 17690, 14310, 10940, 7560, 4190, 2000}},
+		{From: 10000000, To: 10020000, Taxes: [maxTableDependents]float64{
+			1507400, 1431570, 1200840, 1170840, 1140840, 1110840, 1080840, 1050840, 1020840, 990840, 960840}},
+	},
+}
+
+func TestWithholdingTableIncomeTax(t *testing.T) {
+	tests := []struct {
+		name       string
+		pay        models.Money
+		dependents int
+		rate       int
+		want       models.Money
+	}{
+		{"below the first row", 2990000, 1, 100, 0},
+		{"one dependent", 3010000, 1, 100, 74350},
+		{"two dependents", 3010000, 2, 100, 56850},
+		{"no dependents counts the employee", 3010000, 0, 100, 74350},
+		{"ratio defaults to 100", 3010000, 1, 0, 74350},
+		{"80 percent", 3010000, 2, 80, 45480},
+		{"120 percent drops units", 3010000, 3, 120, 38320},
+		// Beyond 11 dependents each one lowers the tax by the difference of the 10 and 11 columns
+		{"twelve dependents", 10000000, 12, 100, 960840 - 30000},
+		{"thirteen dependents", 10000000, 13, 100, 960840 - 2*30000},
+		{"twelve dependents floored at zero", 3010000, 12, 100, 0},
+		{"pay beyond the last row", 9000000, 1, 100, 1507400},
+		{"exactly 10 million", 10000000, 1, 100, 1507400},
+		// Over 10 up to 14 million won: the 10 million tax + 25,000 + the excess x 98% x 35%
+		{"12 million", 12000000, 1, 100, 1507400 + 686000 + 25000},
+		// Over 14 up to 28 million won: the 10 million tax + 1,397,000 + the excess over 14 million x 98% x 38%
+		{"15 million", 15000000, 1, 100, 1507400 + 1397000 + 372400},
+		// Over 45 up to 87 million won: the 10 million tax + 13,394,600 + the excess over 45 million x 42%
+		{"50 million", 50000000, 1, 100, 1507400 + 13394600 + 2100000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testWithholdingTable.incomeTax(tt.pay, tt.dependents, tt.rate); got != tt.want {
+				t.Errorf("incomeTax(%d, %d, %d) = %d, want %d", tt.pay, tt.dependents, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
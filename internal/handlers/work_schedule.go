@@ -0,0 +1,595 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkScheduleRequest struct {
+	Name             string                   `json:"name" binding:"required"`
+	Description      string                   `json:"description"`
+	WeeklyHoliday    time.Weekday             `json:"weekly_holiday"`
+	LateGraceMinutes int                      `json:"late_grace_minutes"`
+	Days             []models.WorkScheduleDay `json:"days" binding:"required"`
+}
+
+var (
+	errWorkHoursRequired = errors.New("working_hours and work_days are required unless work_schedule_id is given")
+	errScheduleInactive  = errors.New("work schedule is no longer in use")
+)
+
+// parseClockMinutes parses an "HH:MM" clock time into minutes since midnight
+func parseClockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// clockSpan returns the minutes from start to end, running past midnight if end is earlier
+func clockSpan(start, end int) int {
+	if end <= start {
+		end += 24 * 60
+	}
+	return end - start
+}
+
+// scheduleDayMinutes returns the time from start to end of the day and the total break time, in minutes
+func scheduleDayMinutes(day models.WorkScheduleDay) (span, breaks int) {
+	start, _ := parseClockMinutes(day.StartTime)
+	end, _ := parseClockMinutes(day.EndTime)
+	span = clockSpan(start, end)
+
+	for _, b := range day.Breaks {
+		bs, _ := parseClockMinutes(b.Start)
+		be, _ := parseClockMinutes(b.End)
+		breaks += clockSpan(bs, be)
+	}
+	return span, breaks
+}
+
+// scheduleWeeklyHours is the contractual working time per week (소정근로시간)
+func scheduleWeeklyHours(schedule *models.WorkSchedule) float64 {
+	minutes := 0
+	for _, day := range schedule.Days {
+		span, breaks := scheduleDayMinutes(day)
+		minutes += span - breaks
+	}
+	return float64(minutes) / 60
+}
+
+// monthlyScheduledHours converts contractual weekly hours into monthly hours (월 소정근로시간): the
+// weekly hours up to 40 plus the paid weekly holiday, times 365 / 7 / 12 weeks, rounded to the hour
+// as in the 209 hours of a 40-hour week
+func monthlyScheduledHours(weeklyHours float64) float64 {
+	baseHours := minFloat(weeklyHours, 40)
+	holidayHours := 0.0
+	if baseHours >= weeklyHolidayMinHours {
+		holidayHours = baseHours / 40 * standardDailyHours
+	}
+	return math.Round((baseHours + holidayHours) * 365 / 7 / 12)
+}
+
+// contractMonthlyHours returns the monthly contractual hours of a contract, from its work schedule or
+// else its free-text working hours. Contracts whose hours cannot be read are taken as full-time.
+func contractMonthlyHours(db dbtx, scheduleID sql.NullInt64, workingHours, workDays, breakTime string) (float64, error) {
+	if scheduleID.Valid {
+		schedule, err := loadWorkSchedule(db, int(scheduleID.Int64))
+		if err != nil {
+			return 0, err
+		}
+		return monthlyScheduledHours(scheduleWeeklyHours(schedule)), nil
+	}
+
+	spans := parseClockRanges(workingHours)
+	days := parseWorkDays(workDays)
+	if len(spans) == 0 || days == 0 {
+		return standardMonthlyHours, nil
+	}
+	dailyMinutes := spans[0]
+	for _, span := range parseClockRanges(breakTime) {
+		dailyMinutes -= span
+	}
+	if dailyMinutes <= 0 {
+		return standardMonthlyHours, nil
+	}
+	return monthlyScheduledHours(float64(dailyMinutes*days) / 60), nil
+}
+
+// employeeMonthlyHours returns the monthly contractual hours of the employee's active contract, or
+// the 209 hours of a full-time week without one
+func employeeMonthlyHours(db dbtx, employeeID int) (float64, error) {
+	var scheduleID sql.NullInt64
+	var workingHours, workDays string
+	var breakTime sql.NullString
+	err := db.QueryRow(`
+		SELECT work_schedule_id, working_hours, work_days, break_time FROM employment_contracts
+		WHERE employee_id = ? AND is_active = 1
+		ORDER BY start_date DESC LIMIT 1
+	`, employeeID).Scan(&scheduleID, &workingHours, &workDays, &breakTime)
+	if err == sql.ErrNoRows {
+		return standardMonthlyHours, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return contractMonthlyHours(db, scheduleID, workingHours, workDays, breakTime.String)
+}
+
+// scheduleDay returns the working time on the weekday, or nil on an off day
+func scheduleDay(schedule *models.WorkSchedule, weekday time.Weekday) *models.WorkScheduleDay {
+	for i := range schedule.Days {
+		if schedule.Days[i].Weekday == weekday {
+			return &schedule.Days[i]
+		}
+	}
+	return nil
+}
+
+// weekdayName returns the Korean name of the weekday (월요일 ...)
+func weekdayName(weekday time.Weekday) string {
+	return string(weekdayNames[(int(weekday)+6)%7]) + "요일"
+}
+
+// describeWorkSchedule renders the schedule as the free-text contract fields
+// (working_hours, work_days, break_time, weekly_holiday) shown on the contract
+func describeWorkSchedule(schedule *models.WorkSchedule) (workingHours, workDays, breakTime, weeklyHoliday string) {
+	days := append([]models.WorkScheduleDay(nil), schedule.Days...)
+	// Monday first
+	sort.Slice(days, func(i, j int) bool {
+		return (int(days[i].Weekday)+6)%7 < (int(days[j].Weekday)+6)%7
+	})
+
+	sameHours := true
+	var names, hours, breaks []string
+	seenBreaks := make(map[string]bool)
+	for _, day := range days {
+		name := string(weekdayNames[(int(day.Weekday)+6)%7])
+		names = append(names, name)
+		hours = append(hours, fmt.Sprintf("%s %s-%s", name, day.StartTime, day.EndTime))
+		if day.StartTime != days[0].StartTime || day.EndTime != days[0].EndTime {
+			sameHours = false
+		}
+		for _, b := range day.Breaks {
+			window := b.Start + "-" + b.End
+			if !seenBreaks[window] {
+				seenBreaks[window] = true
+				breaks = append(breaks, window)
+			}
+		}
+	}
+
+	if len(days) > 0 && sameHours {
+		workingHours = days[0].StartTime + "-" + days[0].EndTime
+	} else {
+		workingHours = strings.Join(hours, ", ")
+	}
+
+	// Consecutive days are written as a range (월-금)
+	consecutive := len(days) > 2
+	for i := 1; i < len(days) && consecutive; i++ {
+		consecutive = (int(days[i].Weekday)+6)%7 == (int(days[i-1].Weekday)+6)%7+1
+	}
+	if consecutive {
+		workDays = names[0] + "-" + names[len(names)-1]
+	} else {
+		workDays = strings.Join(names, ",")
+	}
+
+	breakTime = strings.Join(breaks, ", ")
+	weeklyHoliday = weekdayName(schedule.WeeklyHoliday)
+	return
+}
+
+func validateWorkScheduleRequest(req WorkScheduleRequest) error {
+	if len(req.Days) == 0 {
+		return errors.New("at least one working day is required")
+	}
+	if req.WeeklyHoliday < time.Sunday || req.WeeklyHoliday > time.Saturday {
+		return errors.New("weekly_holiday must be a weekday from 0 (Sunday) to 6 (Saturday)")
+	}
+	if req.LateGraceMinutes < 0 {
+		return errors.New("late_grace_minutes cannot be negative")
+	}
+
+	seen := make(map[time.Weekday]bool)
+	for _, day := range req.Days {
+		if day.Weekday < time.Sunday || day.Weekday > time.Saturday {
+			return errors.New("weekday must be from 0 (Sunday) to 6 (Saturday)")
+		}
+		if seen[day.Weekday] {
+			return fmt.Errorf("%s is listed more than once", weekdayName(day.Weekday))
+		}
+		seen[day.Weekday] = true
+
+		if _, err := parseClockMinutes(day.StartTime); err != nil {
+			return err
+		}
+		if _, err := parseClockMinutes(day.EndTime); err != nil {
+			return err
+		}
+		for _, b := range day.Breaks {
+			if _, err := parseClockMinutes(b.Start); err != nil {
+				return err
+			}
+			if _, err := parseClockMinutes(b.End); err != nil {
+				return err
+			}
+		}
+
+		span, breaks := scheduleDayMinutes(day)
+		if breaks >= span {
+			return fmt.Errorf("breaks on %s are longer than the working time", weekdayName(day.Weekday))
+		}
+	}
+
+	if seen[req.WeeklyHoliday] {
+		return errors.New("the weekly holiday cannot be a working day")
+	}
+	return nil
+}
+
+func loadWorkSchedule(db dbtx, id int) (*models.WorkSchedule, error) {
+	var schedule models.WorkSchedule
+	var description sql.NullString
+	err := db.QueryRow(`
+		SELECT id, name, description, weekly_holiday, late_grace_minutes, is_active, created_at, updated_at
+		FROM work_schedules WHERE id = ?
+	`, id).Scan(
+		&schedule.ID, &schedule.Name, &description, &schedule.WeeklyHoliday,
+		&schedule.LateGraceMinutes, &schedule.IsActive, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Description = description.String
+
+	rows, err := db.Query(`
+		SELECT weekday, start_time, end_time, breaks FROM work_schedule_days
+		WHERE schedule_id = ?
+		ORDER BY weekday
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedule.Days = []models.WorkScheduleDay{}
+	for rows.Next() {
+		var day models.WorkScheduleDay
+		var breaksJSON sql.NullString
+		if err := rows.Scan(&day.Weekday, &day.StartTime, &day.EndTime, &breaksJSON); err != nil {
+			return nil, err
+		}
+		day.Breaks = []models.BreakWindow{}
+		if breaksJSON.Valid && breaksJSON.String != "" {
+			if err := json.Unmarshal([]byte(breaksJSON.String), &day.Breaks); err != nil {
+				return nil, err
+			}
+		}
+		schedule.Days = append(schedule.Days, day)
+	}
+
+	return &schedule, rows.Err()
+}
+
+// applyWorkSchedule loads the schedule referenced by the terms and fills in the
+// free-text working time fields from it. Terms without a schedule are returned as is.
+func applyWorkSchedule(db dbtx, terms *models.ContractTerms) (*models.WorkSchedule, error) {
+	if terms.WorkScheduleID == 0 {
+		if strings.TrimSpace(terms.WorkingHours) == "" || strings.TrimSpace(terms.WorkDays) == "" {
+			return nil, errWorkHoursRequired
+		}
+		return nil, nil
+	}
+
+	schedule, err := loadWorkSchedule(db, terms.WorkScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !schedule.IsActive {
+		return nil, errScheduleInactive
+	}
+
+	terms.WorkingHours, terms.WorkDays, terms.BreakTime, terms.WeeklyHoliday = describeWorkSchedule(schedule)
+	return schedule, nil
+}
+
+func respondWorkScheduleError(c *gin.Context, err error) {
+	switch err {
+	case sql.ErrNoRows:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Work schedule not found"})
+	case errWorkHoursRequired, errScheduleInactive:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work schedule"})
+	}
+}
+
+// employeeWorkSchedule returns the schedule of the employee's active contract, or nil if it has none
+func employeeWorkSchedule(db dbtx, employeeID int) (*models.WorkSchedule, error) {
+	var scheduleID sql.NullInt64
+	err := db.QueryRow(`
+		SELECT work_schedule_id FROM employment_contracts
+		WHERE employee_id = ? AND is_active = 1
+		ORDER BY start_date DESC LIMIT 1
+	`, employeeID).Scan(&scheduleID)
+	if err == sql.ErrNoRows || (err == nil && !scheduleID.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return loadWorkSchedule(db, int(scheduleID.Int64))
+}
+
+// workScheduleSummary adds the weekly hours and the part-time classification to a schedule
+func workScheduleSummary(schedule *models.WorkSchedule) gin.H {
+	weeklyHours := scheduleWeeklyHours(schedule)
+	return gin.H{
+		"schedule":           schedule,
+		"weekly_hours":       weeklyHours,
+		"work_days_per_week": len(schedule.Days),
+		// 단시간근로자: shorter than the 40-hour week of full-time workers
+		"part_time": weeklyHours < 40,
+		// 주휴수당 and annual leave apply from 15 hours a week (근로기준법 제18조 제3항)
+		"weekly_holiday_pay_eligible": weeklyHours >= 15,
+	}
+}
+
+func GetWorkSchedules(c *gin.Context) {
+	rows, err := database.DB.Query(`
+		SELECT id FROM work_schedules WHERE is_active = 1 ORDER BY id
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan work schedule"})
+			return
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	schedules := []gin.H{}
+	for _, id := range ids {
+		schedule, err := loadWorkSchedule(database.DB, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work schedule"})
+			return
+		}
+		schedules = append(schedules, workScheduleSummary(schedule))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"work_schedules": schedules})
+}
+
+func GetWorkSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid work schedule ID"})
+		return
+	}
+
+	schedule, err := loadWorkSchedule(database.DB, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Work schedule not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, workScheduleSummary(schedule))
+}
+
+// saveWorkScheduleDays replaces the working days of a schedule
+func saveWorkScheduleDays(tx dbtx, scheduleID int64, days []models.WorkScheduleDay) error {
+	if _, err := tx.Exec("DELETE FROM work_schedule_days WHERE schedule_id = ?", scheduleID); err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if day.Breaks == nil {
+			day.Breaks = []models.BreakWindow{}
+		}
+		breaksJSON, _ := json.Marshal(day.Breaks)
+		_, err := tx.Exec(`
+			INSERT INTO work_schedule_days (schedule_id, weekday, start_time, end_time, breaks)
+			VALUES (?, ?, ?, ?, ?)
+		`, scheduleID, day.Weekday, day.StartTime, day.EndTime, string(breaksJSON))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CreateWorkSchedule(c *gin.Context) {
+	var req WorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateWorkScheduleRequest(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO work_schedules (name, description, weekly_holiday, late_grace_minutes)
+		VALUES (?, ?, ?, ?)
+	`, req.Name, req.Description, req.WeeklyHoliday, req.LateGraceMinutes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create work schedule"})
+		return
+	}
+
+	scheduleID, _ := result.LastInsertId()
+	if err := saveWorkScheduleDays(tx, scheduleID, req.Days); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save working days"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	schedule, err := loadWorkSchedule(database.DB, int(scheduleID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created work schedule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, workScheduleSummary(schedule))
+}
+
+// UpdateWorkSchedule changes a schedule. Contracts keep the working time text they were
+// saved with; the new times apply to attendance from now on.
+func UpdateWorkSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid work schedule ID"})
+		return
+	}
+
+	var req WorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateWorkScheduleRequest(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE work_schedules SET name = ?, description = ?, weekly_holiday = ?,
+		                          late_grace_minutes = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND is_active = 1
+	`, req.Name, req.Description, req.WeeklyHoliday, req.LateGraceMinutes, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update work schedule"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Work schedule not found"})
+		return
+	}
+
+	if err := saveWorkScheduleDays(tx, int64(id), req.Days); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save working days"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	schedule, err := loadWorkSchedule(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve work schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, workScheduleSummary(schedule))
+}
+
+// DeleteWorkSchedule retires a schedule that no active contract uses
+func DeleteWorkSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid work schedule ID"})
+		return
+	}
+
+	var contracts int
+	err = database.DB.QueryRow(`
+		SELECT COUNT(*) FROM employment_contracts WHERE work_schedule_id = ? AND is_active = 1
+	`, id).Scan(&contracts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if contracts > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Work schedule is used by %d active contracts", contracts)})
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE work_schedules SET is_active = 0, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete work schedule"})
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Work schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Work schedule deleted successfully"})
+}
+
+// GetEmployeeWorkSchedule returns the schedule of the employee's active contract with its weekly hours
+func GetEmployeeWorkSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	schedule, err := employeeWorkSchedule(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work schedule"})
+		return
+	}
+	if schedule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee's active contract has no work schedule"})
+		return
+	}
+
+	summary := workScheduleSummary(schedule)
+	summary["employee_id"] = id
+	c.JSON(http.StatusOK, summary)
+}
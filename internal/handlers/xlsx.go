@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// xlsxCell is a spreadsheet cell. Value is a string, float64 or models.Money; Format is
+// "" (general), "won" (#,##0) or "hours" (0.00).
+type xlsxCell struct {
+	Value  interface{}
+	Format string
+	Bold   bool
+}
+
+type xlsxSheet struct {
+	Name         string
+	ColumnWidths []float64
+	Rows         [][]xlsxCell
+}
+
+// xlsxStyles are the cell styles of styles.xml by format, regular then bold
+var xlsxStyles = map[string][2]int{"": {0, 1}, "won": {2, 3}, "hours": {4, 5}}
+
+const xlsxStylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="10"/><name val="맑은 고딕"/></font><font><b/><sz val="10"/><name val="맑은 고딕"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="6">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="3" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>
+<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="2" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+
+// xlsxColumnName returns the column letters of a zero-based column index (0 = A, 26 = AA)
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func xlsxEscape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+func writeXLSXSheet(w io.Writer, sheet xlsxSheet) error {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(sheet.ColumnWidths) > 0 {
+		buf.WriteString("<cols>")
+		for i, width := range sheet.ColumnWidths {
+			fmt.Fprintf(&buf, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+		}
+		buf.WriteString("</cols>")
+	}
+	buf.WriteString("<sheetData>")
+	for r, row := range sheet.Rows {
+		fmt.Fprintf(&buf, `<row r="%d">`, r+1)
+		for col, cell := range row {
+			if cell.Value == nil {
+				continue
+			}
+			ref := xlsxColumnName(col) + strconv.Itoa(r+1)
+			style := xlsxStyles[cell.Format][0]
+			if cell.Bold {
+				style = xlsxStyles[cell.Format][1]
+			}
+			switch v := cell.Value.(type) {
+			case string:
+				fmt.Fprintf(&buf, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, style, xlsxEscape(v))
+			case float64:
+				fmt.Fprintf(&buf, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+			default:
+				fmt.Fprintf(&buf, `<c r="%s" s="%d"><v>%v</v></c>`, ref, style, v)
+			}
+		}
+		buf.WriteString("</row>")
+	}
+	buf.WriteString("</sheetData></worksheet>")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeXLSX writes a workbook of the given sheets
+func writeXLSX(w io.Writer, sheets ...xlsxSheet) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		Name    string
+		Content string
+	}{
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/styles.xml", xlsxStylesXML},
+	}
+
+	contentTypes := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`
+	workbookRels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	for i := range sheets {
+		n := i + 1
+		contentTypes += fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		workbook += fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxEscape(sheets[i].Name), n, n)
+		workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(sheets)+1)
+	files = append(files,
+		struct{ Name, Content string }{"[Content_Types].xml", contentTypes + "</Types>"},
+		struct{ Name, Content string }{"xl/workbook.xml", workbook + "</sheets></workbook>"},
+		struct{ Name, Content string }{"xl/_rels/workbook.xml.rels", workbookRels + "</Relationships>"},
+	)
+
+	for _, file := range files {
+		fw, err := zw.Create(file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, file.Content); err != nil {
+			return err
+		}
+	}
+	for i, sheet := range sheets {
+		fw, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeXLSXSheet(fw, sheet); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,638 @@
+package handlers
+
+import (
+	"database/sql"
+	"labor-management-system/database"
+	"labor-management-system/internal/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// earnedIncomeDeductionBrackets are the 근로소득공제 rules by total pay (소득세법 제47조), highest first
+var earnedIncomeDeductionBrackets = []excessPayBracket{
+	{100000000, 14750000, 0.02},
+	{45000000, 12000000, 0.05},
+	{15000000, 7500000, 0.15},
+	{5000000, 3500000, 0.40},
+	{0, 0, 0.70},
+}
+
+// earnedIncomeDeductionLimit caps the 근로소득공제 (소득세법 제47조 제2항)
+const earnedIncomeDeductionLimit = 20000000
+
+// incomeTaxRates are the 종합소득세 기본세율 by tax base (소득세법 제55조), highest first
+var incomeTaxRates = []excessPayBracket{
+	{1000000000, 384060000, 0.45},
+	{500000000, 174060000, 0.42},
+	{300000000, 94060000, 0.40},
+	{150000000, 37060000, 0.38},
+	{88000000, 15360000, 0.35},
+	{50000000, 6240000, 0.24},
+	{14000000, 840000, 0.15},
+	{0, 0, 0.06},
+}
+
+const (
+	personalDeductionPerDependent = 1500000 // 기본공제 1인당 150만원
+	standardTaxCredit             = 130000  // 표준세액공제 (특별소득·세액공제를 신청하지 않는 경우)
+)
+
+// bracketAmount applies the bracket the amount falls in
+func bracketAmount(brackets []excessPayBracket, amount float64) float64 {
+	for _, b := range brackets {
+		if amount > b.Over || b.Over == 0 {
+			return b.BaseTax + (amount-b.Over)*b.Rate
+		}
+	}
+	return 0
+}
+
+// earnedIncomeTaxCredit is the 근로소득세액공제 on the calculated tax, within the limit for the
+// total pay (소득세법 제59조)
+func earnedIncomeTaxCredit(calculatedTax, totalPay float64) float64 {
+	credit := calculatedTax * 0.55
+	if calculatedTax > 1300000 {
+		credit = 715000 + (calculatedTax-1300000)*0.30
+	}
+
+	var limit float64
+	switch {
+	case totalPay <= 33000000:
+		limit = 740000
+	case totalPay <= 70000000:
+		limit = math.Max(740000-(totalPay-33000000)*0.008, 660000)
+	case totalPay <= 120000000:
+		limit = math.Max(660000-(totalPay-70000000)*0.5, 500000)
+	default:
+		limit = math.Max(500000-(totalPay-120000000)*0.5, 200000)
+	}
+	return math.Min(credit, limit)
+}
+
+// cardDeduction is the 신용카드 등 소득공제 (조세특례제한법 제126조의2): spending over 25% of total pay,
+// met by credit card spending first, is deducted at 15% for credit cards and 30% for debit cards
+// and cash receipts, up to 3 million won (2.5 million over 70 million won of total pay)
+func cardDeduction(totalPay, credit, debit float64) float64 {
+	threshold := totalPay * 0.25
+	var deduction float64
+	if credit >= threshold {
+		deduction = (credit-threshold)*0.15 + debit*0.30
+	} else if debit > threshold-credit {
+		deduction = (debit - (threshold - credit)) * 0.30
+	}
+
+	limit := 3000000.0
+	if totalPay > 70000000 {
+		limit = 2500000
+	}
+	return math.Min(deduction, limit)
+}
+
+// specialTaxCredit is the 특별세액공제 on the declared expenses: 보장성 보험료 12% (up to 1 million won),
+// 의료비 over 3% of total pay 15% (general dependents up to 7 million won), 교육비 15% and 기부금
+// 15% up to 10 million won and 30% above
+func specialTaxCredit(s *models.YearEndSettlement) float64 {
+	totalPay := s.TotalPay.Float64()
+
+	insurance := math.Min(s.InsurancePremiums.Float64(), 1000000) * 0.12
+
+	threshold := totalPay * 0.03
+	general := s.MedicalExpenses.Float64() - threshold
+	special := s.MedicalExpensesSpecial.Float64()
+	if general < 0 {
+		special += general
+		general = 0
+	}
+	medical := (math.Min(general, 7000000) + math.Max(special, 0)) * 0.15
+
+	education := s.EducationExpenses.Float64() * 0.15
+
+	donations := s.Donations.Float64()
+	donation := math.Min(donations, 10000000)*0.15 + math.Max(donations-10000000, 0)*0.30
+
+	return insurance + medical + education + donation
+}
+
+// calculateYearEndTax works out the tax finally due for the year from the total pay, withheld tax
+// and declarations of the settlement and the pension and insurance contributions paid. Itemized
+// deductions (건강·고용보험료 특별소득공제 and 특별세액공제) and the 표준세액공제 are both tried and
+// the lower tax is kept. The balance against the withheld tax is truncated to 10 won.
+func calculateYearEndTax(s *models.YearEndSettlement, pensionPaid, insurancePaid models.Money) {
+	const localTaxRate = 0.1
+
+	totalPay := s.TotalPay.Float64()
+	s.EarnedIncomeDeduction = models.TruncateWon(math.Min(math.Min(bracketAmount(earnedIncomeDeductionBrackets, totalPay),
+		earnedIncomeDeductionLimit), totalPay))
+	s.EarnedIncomeAmount = s.TotalPay - s.EarnedIncomeDeduction
+
+	dependents := s.Dependents
+	if dependents < 1 {
+		dependents = 1
+	}
+	s.PersonalDeduction = models.Money(dependents * personalDeductionPerDependent)
+	s.PensionDeduction = pensionPaid
+	s.CardDeduction = models.TruncateWon(cardDeduction(totalPay, s.CreditCardSpending.Float64(), s.DebitCardSpending.Float64()))
+	itemizedCredit := models.TruncateWon(specialTaxCredit(s))
+
+	type outcome struct {
+		specialIncomeDeduction, taxBase, calculatedTax, earnedCredit, specialCredit, standardCredit, determined models.Money
+	}
+	calculate := func(itemized bool) outcome {
+		var o outcome
+		if itemized {
+			o.specialIncomeDeduction = insurancePaid
+			o.specialCredit = itemizedCredit
+		} else {
+			o.standardCredit = standardTaxCredit
+		}
+		o.taxBase = maxMoney(s.EarnedIncomeAmount-s.PersonalDeduction-s.PensionDeduction-o.specialIncomeDeduction-s.CardDeduction, 0)
+		o.calculatedTax = models.TruncateWon(bracketAmount(incomeTaxRates, o.taxBase.Float64()))
+		o.earnedCredit = models.TruncateWon(earnedIncomeTaxCredit(o.calculatedTax.Float64(), totalPay))
+		o.determined = maxMoney(o.calculatedTax-o.earnedCredit-o.specialCredit-o.standardCredit, 0)
+		return o
+	}
+
+	chosen := calculate(true)
+	if standard := calculate(false); standard.determined < chosen.determined {
+		chosen = standard
+	}
+
+	s.SpecialIncomeDeduction = chosen.specialIncomeDeduction
+	s.TaxBase = chosen.taxBase
+	s.CalculatedTax = chosen.calculatedTax
+	s.EarnedIncomeTaxCredit = chosen.earnedCredit
+	s.SpecialTaxCredit = chosen.specialCredit
+	s.StandardTaxCredit = chosen.standardCredit
+	s.DeterminedTax = chosen.determined
+	s.DeterminedLocalTax = s.DeterminedTax.MulRate(localTaxRate)
+	s.BalanceTax = (s.DeterminedTax - s.WithheldTax).Truncate(10)
+	s.BalanceLocalTax = (s.DeterminedLocalTax - s.WithheldLocalTax).Truncate(10)
+}
+
+// computeYearEndSettlement fills in the year's pay, withheld tax and dependents from payroll and the
+// employee record and calculates the tax due on the settlement's declarations
+func computeYearEndSettlement(db dbtx, s *models.YearEndSettlement) error {
+	from := strconv.Itoa(s.TaxYear) + "-01-01"
+	until := strconv.Itoa(s.TaxYear+1) + "-01-01"
+
+	// Only approved or paid payroll has been withheld; adjustments settled later belong to the year of
+	// the payroll they correct and count once the payroll they are settled with is approved or paid
+	var pensionPaid, insurancePaid models.Money
+	err := db.QueryRow(`
+		SELECT COALESCE(SUM(taxable_pay), 0), COALESCE(SUM(non_taxable_pay), 0),
+		       COALESCE(SUM(income_tax), 0), COALESCE(SUM(local_tax), 0), COALESCE(SUM(national_pension), 0),
+		       COALESCE(SUM(health_insurance + long_term_care + employment_insurance), 0)
+		FROM (
+			SELECT p.taxable_pay, p.non_taxable_pay, p.income_tax, p.local_tax, p.national_pension,
+			       p.health_insurance, p.long_term_care, p.employment_insurance
+			FROM payroll_records p
+			LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+			WHERE p.employee_id = ? AND p.pay_period_start >= ? AND p.pay_period_start < ? AND `+reportedPayrollCond+`
+			UNION ALL
+			SELECT a.taxable_pay, a.non_taxable_pay, a.income_tax, a.local_tax, a.national_pension,
+			       a.health_insurance, a.long_term_care, a.employment_insurance
+			FROM payroll_adjustments a
+			JOIN payroll_records o ON o.id = a.original_payroll_id
+			JOIN payroll_records p ON p.id = a.applied_payroll_id
+			LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+			WHERE a.employee_id = ? AND a.status = 'applied' AND `+reportedPayrollCond+`
+			  AND o.pay_period_start >= ? AND o.pay_period_start < ?
+		) paid
+	`, s.EmployeeID, from, until, s.EmployeeID, from, until).Scan(&s.TotalPay, &s.NonTaxablePay, &s.WithheldTax,
+		&s.WithheldLocalTax, &pensionPaid, &insurancePaid)
+	if err != nil {
+		return err
+	}
+
+	if s.Dependents, _, err = employeeWithholding(db, s.EmployeeID); err != nil {
+		return err
+	}
+
+	calculateYearEndTax(s, pensionPaid, insurancePaid)
+	return nil
+}
+
+// yearEndSettlementColumns are the stored fields after employee_id and tax_year, in the order of
+// yearEndSettlementFields
+var yearEndSettlementColumns = []string{
+	"insurance_premiums", "medical_expenses", "medical_expenses_special", "education_expenses", "donations",
+	"credit_card_spending", "debit_card_spending", "dependents", "total_pay", "non_taxable_pay",
+	"earned_income_deduction", "earned_income_amount", "personal_deduction", "pension_deduction",
+	"special_income_deduction", "card_deduction", "tax_base", "calculated_tax", "earned_income_tax_credit",
+	"special_tax_credit", "standard_tax_credit", "determined_tax", "determined_local_tax", "withheld_tax",
+	"withheld_local_tax", "balance_tax", "balance_local_tax",
+}
+
+func yearEndSettlementFields(s *models.YearEndSettlement) []interface{} {
+	return []interface{}{
+		&s.InsurancePremiums, &s.MedicalExpenses, &s.MedicalExpensesSpecial, &s.EducationExpenses, &s.Donations,
+		&s.CreditCardSpending, &s.DebitCardSpending, &s.Dependents, &s.TotalPay, &s.NonTaxablePay,
+		&s.EarnedIncomeDeduction, &s.EarnedIncomeAmount, &s.PersonalDeduction, &s.PensionDeduction,
+		&s.SpecialIncomeDeduction, &s.CardDeduction, &s.TaxBase, &s.CalculatedTax, &s.EarnedIncomeTaxCredit,
+		&s.SpecialTaxCredit, &s.StandardTaxCredit, &s.DeterminedTax, &s.DeterminedLocalTax, &s.WithheldTax,
+		&s.WithheldLocalTax, &s.BalanceTax, &s.BalanceLocalTax,
+	}
+}
+
+var yearEndSettlementSelectQuery = `
+	SELECT s.id, s.employee_id, e.name, s.tax_year, s.` + strings.Join(yearEndSettlementColumns, ", s.") + `,
+	       s.status, s.payroll_id, s.confirmed_at, s.created_at, s.updated_at
+	FROM year_end_settlements s
+	JOIN employees e ON e.id = s.employee_id`
+
+func scanYearEndSettlement(row rowScanner) (*models.YearEndSettlement, error) {
+	var s models.YearEndSettlement
+	dest := append([]interface{}{&s.ID, &s.EmployeeID, &s.EmployeeName, &s.TaxYear}, yearEndSettlementFields(&s)...)
+	dest = append(dest, &s.Status, &s.PayrollID, &s.ConfirmedAt, &s.CreatedAt, &s.UpdatedAt)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func loadYearEndSettlement(db dbtx, employeeID, taxYear int) (*models.YearEndSettlement, error) {
+	return scanYearEndSettlement(db.QueryRow(yearEndSettlementSelectQuery+" WHERE s.employee_id = ? AND s.tax_year = ?",
+		employeeID, taxYear))
+}
+
+// saveYearEndSettlement stores the declarations and calculated amounts of a draft settlement
+func saveYearEndSettlement(db dbtx, s *models.YearEndSettlement) error {
+	values := []interface{}{s.EmployeeID, s.TaxYear}
+	for _, field := range yearEndSettlementFields(s) {
+		switch v := field.(type) {
+		case *models.Money:
+			values = append(values, *v)
+		case *int:
+			values = append(values, *v)
+		}
+	}
+
+	updates := make([]string, len(yearEndSettlementColumns))
+	for i, column := range yearEndSettlementColumns {
+		updates[i] = column + " = excluded." + column
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO year_end_settlements (employee_id, tax_year, `+strings.Join(yearEndSettlementColumns, ", ")+`)
+		VALUES (?, ?`+strings.Repeat(", ?", len(yearEndSettlementColumns))+`)
+		ON CONFLICT(employee_id, tax_year) DO UPDATE SET
+			`+strings.Join(updates, ", ")+`, updated_at = CURRENT_TIMESTAMP
+	`, values...)
+	return err
+}
+
+// yearEndEmployeeIDs lists the employees with approved or paid payroll in the tax year
+func yearEndEmployeeIDs(db dbtx, taxYear int) ([]int, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT p.employee_id FROM payroll_records p
+		LEFT JOIN payroll_runs r ON r.id = p.payroll_run_id
+		WHERE p.pay_period_start >= ? AND p.pay_period_start < ? AND `+reportedPayrollCond+`
+		ORDER BY p.employee_id
+	`, strconv.Itoa(taxYear)+"-01-01", strconv.Itoa(taxYear+1)+"-01-01")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employeeIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		employeeIDs = append(employeeIDs, id)
+	}
+	return employeeIDs, rows.Err()
+}
+
+// applyYearEndSettlement adds the confirmed settlement of the previous tax year to a February payroll.
+// payrollID is the record being recalculated, so a settlement already posted to it is kept.
+func (pc *PayrollCalculator) applyYearEndSettlement(db dbtx, employeeID int, periodStart time.Time, payrollID int) error {
+	pc.YearEndSettlementID, pc.YearEndTax, pc.YearEndLocalTax = 0, 0, 0
+	if periodStart.Month() != time.February {
+		return nil
+	}
+
+	err := db.QueryRow(`
+		SELECT id, balance_tax, balance_local_tax FROM year_end_settlements
+		WHERE employee_id = ? AND tax_year = ? AND (status = 'confirmed' OR (status = 'posted' AND payroll_id = ?))
+	`, employeeID, periodStart.Year()-1, payrollID).Scan(&pc.YearEndSettlementID, &pc.YearEndTax, &pc.YearEndLocalTax)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// postYearEndSettlement marks the settlement as posted to the payroll record
+func postYearEndSettlement(tx dbtx, settlementID int, payrollID int64) error {
+	if settlementID == 0 {
+		return nil
+	}
+	_, err := tx.Exec(`
+		UPDATE year_end_settlements SET status = 'posted', payroll_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, payrollID, settlementID)
+	return err
+}
+
+// releaseYearEndSettlements returns settlements posted to the payroll records matching the condition
+// to confirmed, before those records are deleted or recalculated
+func releaseYearEndSettlements(tx dbtx, condition string, args ...interface{}) error {
+	_, err := tx.Exec(`
+		UPDATE year_end_settlements SET status = 'confirmed', payroll_id = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'posted' AND payroll_id IN (SELECT id FROM payroll_records WHERE `+condition+`)
+	`, args...)
+	return err
+}
+
+type YearEndDeclarationRequest struct {
+	InsurancePremiums      float64 `json:"insurance_premiums" binding:"min=0"`
+	MedicalExpenses        float64 `json:"medical_expenses" binding:"min=0"`
+	MedicalExpensesSpecial float64 `json:"medical_expenses_special" binding:"min=0"`
+	EducationExpenses      float64 `json:"education_expenses" binding:"min=0"`
+	Donations              float64 `json:"donations" binding:"min=0"`
+	CreditCardSpending     float64 `json:"credit_card_spending" binding:"min=0"`
+	DebitCardSpending      float64 `json:"debit_card_spending" binding:"min=0"`
+}
+
+type CalculateYearEndSettlementsRequest struct {
+	TaxYear int `json:"tax_year" binding:"required"`
+}
+
+// yearEndParams reads the employee ID and tax year of the URL
+func yearEndParams(c *gin.Context) (int, int, bool) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return 0, 0, false
+	}
+	taxYear, err := strconv.Atoi(c.Param("year"))
+	if err != nil || taxYear < 2000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax year"})
+		return 0, 0, false
+	}
+	return employeeID, taxYear, true
+}
+
+// GetYearEndSettlements lists the settlements of a tax year (tax_year, default last year)
+func GetYearEndSettlements(c *gin.Context) {
+	taxYear, err := strconv.Atoi(c.DefaultQuery("tax_year", strconv.Itoa(time.Now().Year()-1)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax year"})
+		return
+	}
+
+	rows, err := database.DB.Query(yearEndSettlementSelectQuery+" WHERE s.tax_year = ? ORDER BY e.name", taxYear)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer rows.Close()
+
+	settlements := []models.YearEndSettlement{}
+	var balanceTax, balanceLocalTax models.Money
+	for rows.Next() {
+		s, err := scanYearEndSettlement(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan year-end settlement"})
+			return
+		}
+		balanceTax += s.BalanceTax
+		balanceLocalTax += s.BalanceLocalTax
+		settlements = append(settlements, *s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tax_year":          taxYear,
+		"settlements":       settlements,
+		"balance_tax":       balanceTax,
+		"balance_local_tax": balanceLocalTax,
+	})
+}
+
+// CalculateYearEndSettlements creates or recalculates the draft settlement of every employee paid in
+// the tax year. Confirmed and posted settlements are left as they are.
+func CalculateYearEndSettlements(c *gin.Context) {
+	var req CalculateYearEndSettlementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	employeeIDs, err := yearEndEmployeeIDs(tx, req.TaxYear)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	calculated, unchanged := 0, 0
+	for _, employeeID := range employeeIDs {
+		s, err := loadYearEndSettlement(tx, employeeID, req.TaxYear)
+		if err == sql.ErrNoRows {
+			s = &models.YearEndSettlement{EmployeeID: employeeID, TaxYear: req.TaxYear, Status: "draft"}
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if s.Status != "draft" {
+			unchanged++
+			continue
+		}
+
+		err = computeYearEndSettlement(tx, s)
+		if err == nil {
+			err = saveYearEndSettlement(tx, s)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate year-end settlement"})
+			return
+		}
+		calculated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tax_year": req.TaxYear, "calculated": calculated, "unchanged": unchanged})
+}
+
+// GetEmployeeYearEndSettlement returns the employee's settlement of the tax year. A draft is
+// recalculated against the current payroll records.
+func GetEmployeeYearEndSettlement(c *gin.Context) {
+	employeeID, taxYear, ok := yearEndParams(c)
+	if !ok {
+		return
+	}
+
+	s, err := loadYearEndSettlement(database.DB, employeeID, taxYear)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Year-end settlement not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if s.Status == "draft" {
+		if err := computeYearEndSettlement(database.DB, s); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate year-end settlement"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// UpdateEmployeeYearEndSettlement saves the deductions the employee declared and recalculates the draft
+func UpdateEmployeeYearEndSettlement(c *gin.Context) {
+	employeeID, taxYear, ok := yearEndParams(c)
+	if !ok {
+		return
+	}
+
+	var req YearEndDeclarationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	s, err := loadYearEndSettlement(tx, employeeID, taxYear)
+	if err == sql.ErrNoRows {
+		s = &models.YearEndSettlement{EmployeeID: employeeID, TaxYear: taxYear, Status: "draft"}
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if s.Status != "draft" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft settlements can be changed", "status": s.Status})
+		return
+	}
+
+	s.InsurancePremiums = models.Won(req.InsurancePremiums)
+	s.MedicalExpenses = models.Won(req.MedicalExpenses)
+	s.MedicalExpensesSpecial = models.Won(req.MedicalExpensesSpecial)
+	s.EducationExpenses = models.Won(req.EducationExpenses)
+	s.Donations = models.Won(req.Donations)
+	s.CreditCardSpending = models.Won(req.CreditCardSpending)
+	s.DebitCardSpending = models.Won(req.DebitCardSpending)
+
+	if err := computeYearEndSettlement(tx, s); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate year-end settlement"})
+		}
+		return
+	}
+	if err := saveYearEndSettlement(tx, s); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save year-end settlement"})
+		return
+	}
+
+	s, err = loadYearEndSettlement(tx, employeeID, taxYear)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load year-end settlement"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// transitionYearEndSettlement moves a settlement between draft and confirmed. Confirming recalculates
+// it first; a confirmed settlement is posted with the next February payroll generated or entered.
+func transitionYearEndSettlement(c *gin.Context, from, to string) {
+	employeeID, taxYear, ok := yearEndParams(c)
+	if !ok {
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	s, err := loadYearEndSettlement(tx, employeeID, taxYear)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Year-end settlement not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+	if s.Status != from {
+		c.JSON(http.StatusConflict, gin.H{"error": "Settlement must be " + from + " to become " + to, "status": s.Status})
+		return
+	}
+
+	if to == "confirmed" {
+		err = computeYearEndSettlement(tx, s)
+		if err == nil {
+			err = saveYearEndSettlement(tx, s)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate year-end settlement"})
+			return
+		}
+		_, err = tx.Exec(`
+			UPDATE year_end_settlements SET status = 'confirmed', confirmed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, s.ID)
+	} else {
+		_, err = tx.Exec(`
+			UPDATE year_end_settlements SET status = ?, confirmed_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+		`, to, s.ID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update year-end settlement"})
+		return
+	}
+
+	s, err = loadYearEndSettlement(tx, employeeID, taxYear)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load year-end settlement"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+func ConfirmEmployeeYearEndSettlement(c *gin.Context) {
+	transitionYearEndSettlement(c, "draft", "confirmed")
+}
+
+func ReopenEmployeeYearEndSettlement(c *gin.Context) {
+	transitionYearEndSettlement(c, "confirmed", "draft")
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"testing"
+
+	"labor-management-system/internal/models"
+)
+
+// The expected amounts are worked by hand from 소득세법 제47조 (근로소득공제), 제55조 (기본세율),
+// 제59조 (근로소득세액공제 and its limit by total pay) and 제59조의4 (표준세액공제 13만원).
+func TestCalculateYearEndTax(t *testing.T) {
+	tests := []struct {
+		name                string
+		settlement          models.YearEndSettlement
+		pensionPaid         models.Money
+		insurancePaid       models.Money
+		wantDeduction       models.Money // 근로소득공제
+		wantTaxBase         models.Money
+		wantCalculatedTax   models.Money
+		wantEarnedCredit    models.Money
+		wantStandardCredit  models.Money
+		wantDeterminedTax   models.Money
+		wantBalanceTax      models.Money
+		wantBalanceLocalTax models.Money
+	}{
+		{
+			// 근로소득공제 12,000,000 + 5,000,000 x 5% = 12,250,000; tax base 37,750,000 - 1,500,000 -
+			// 2,250,000 - 2,200,000 = 31,800,000; tax 840,000 + 17,800,000 x 15% = 3,510,000; the credit
+			// 715,000 + 2,210,000 x 30% = 1,378,000 is limited to max(740,000 - 17,000,000 x 0.008, 660,000).
+			// Deducting the insurance premiums beats the 표준세액공제 (3,050,000).
+			name: "50 million won, itemized",
+			settlement: models.YearEndSettlement{
+				TotalPay: 50000000, Dependents: 1, WithheldTax: 1500000, WithheldLocalTax: 150000,
+			},
+			pensionPaid:         2250000,
+			insurancePaid:       2200000,
+			wantDeduction:       12250000,
+			wantTaxBase:         31800000,
+			wantCalculatedTax:   3510000,
+			wantEarnedCredit:    660000,
+			wantStandardCredit:  0,
+			wantDeterminedTax:   2850000,
+			wantBalanceTax:      1350000,
+			wantBalanceLocalTax: 135000,
+		},
+		{
+			// 근로소득공제 7,500,000 + 15,000,000 x 15% = 9,750,000. With the 표준세액공제 the tax base is
+			// 20,250,000 - 3,000,000 - 1,350,000 = 15,900,000, the tax 840,000 + 1,900,000 x 15% = 1,125,000
+			// and the credit 1,125,000 x 55% = 618,750, leaving 376,250 (425,250 with itemized deductions).
+			// The local tax refund of 22,375 drops the units to 22,370.
+			name: "30 million won, standard credit, refund",
+			settlement: models.YearEndSettlement{
+				TotalPay: 30000000, Dependents: 2, WithheldTax: 600000, WithheldLocalTax: 60000,
+			},
+			pensionPaid:         1350000,
+			insurancePaid:       1200000,
+			wantDeduction:       9750000,
+			wantTaxBase:         15900000,
+			wantCalculatedTax:   1125000,
+			wantEarnedCredit:    618750,
+			wantStandardCredit:  130000,
+			wantDeterminedTax:   376250,
+			wantBalanceTax:      -223750,
+			wantBalanceLocalTax: -22370,
+		},
+		{
+			// 근로소득공제 3,500,000 + 3,000,000 x 40% = 4,700,000; tax base 3,300,000 - 1,500,000 =
+			// 1,800,000; the tax of 108,000 is offset by the 59,400 credit and the 표준세액공제
+			name: "8 million won, no tax",
+			settlement: models.YearEndSettlement{
+				TotalPay: 8000000, Dependents: 1, WithheldTax: 0, WithheldLocalTax: 0,
+			},
+			wantDeduction:       4700000,
+			wantTaxBase:         1800000,
+			wantCalculatedTax:   108000,
+			wantEarnedCredit:    59400,
+			wantStandardCredit:  130000,
+			wantDeterminedTax:   0,
+			wantBalanceTax:      0,
+			wantBalanceLocalTax: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.settlement
+			calculateYearEndTax(&s, tt.pensionPaid, tt.insurancePaid)
+			checks := []struct {
+				field     string
+				got, want models.Money
+			}{
+				{"earned income deduction", s.EarnedIncomeDeduction, tt.wantDeduction},
+				{"tax base", s.TaxBase, tt.wantTaxBase},
+				{"calculated tax", s.CalculatedTax, tt.wantCalculatedTax},
+				{"earned income tax credit", s.EarnedIncomeTaxCredit, tt.wantEarnedCredit},
+				{"standard tax credit", s.StandardTaxCredit, tt.wantStandardCredit},
+				{"determined tax", s.DeterminedTax, tt.wantDeterminedTax},
+				{"balance tax", s.BalanceTax, tt.wantBalanceTax},
+				{"balance local tax", s.BalanceLocalTax, tt.wantBalanceLocalTax},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %d, want %d", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
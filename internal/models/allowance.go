@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// AllowanceType is a kind of allowance (수당) and how it is taxed and counted in wages
+type AllowanceType struct {
+	ID              int       `json:"id" db:"id"`
+	Code            string    `json:"code" db:"code"`
+	Name            string    `json:"name" db:"name"`
+	PaymentType     string    `json:"payment_type" db:"payment_type"` // fixed, variable
+	Taxable         bool      `json:"taxable" db:"taxable"`
+	NonTaxableLimit float64   `json:"non_taxable_limit" db:"non_taxable_limit"` // monthly cap of the non-taxable part
+	OrdinaryWage    bool      `json:"ordinary_wage" db:"ordinary_wage"`         // included in 통상임금
+	MinimumWage     bool      `json:"minimum_wage" db:"minimum_wage"`           // counted toward 최저임금 when paid as fixed
+	Description     string    `json:"description" db:"description"`
+	IsActive        bool      `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ContractAllowance is an allowance item agreed in a contract
+type ContractAllowance struct {
+	AllowanceTypeID int     `json:"allowance_type_id"`
+	Code            string  `json:"code"`
+	Name            string  `json:"name"`
+	Amount          float64 `json:"amount"`
+}
+
+// PayrollAllowanceItem is an allowance paid in one payroll record
+type PayrollAllowanceItem struct {
+	AllowanceTypeID  int    `json:"allowance_type_id" db:"allowance_type_id"`
+	Name             string `json:"name" db:"name"`
+	Amount           Money  `json:"amount" db:"amount"`
+	TaxableAmount    Money  `json:"taxable_amount" db:"taxable_amount"`
+	NonTaxableAmount Money  `json:"non_taxable_amount" db:"non_taxable_amount"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ContractTerms is a snapshot of the terms of an employment contract at one version
+type ContractTerms struct {
+	ContractType   string              `json:"contract_type"`
+	StartDate      string              `json:"start_date"`
+	EndDate        string              `json:"end_date"`
+	Workplace      string              `json:"workplace"`
+	JobDescription string              `json:"job_description"`
+	WorkingHours   string              `json:"working_hours"`
+	WorkDays       string              `json:"work_days"`
+	BreakTime      string              `json:"break_time"`
+	WeeklyHoliday  string              `json:"weekly_holiday"`
+	WorkScheduleID int                 `json:"work_schedule_id,omitempty"`
+	BaseSalary     float64             `json:"base_salary"`
+	Allowances     string              `json:"allowances"`
+	AllowanceItems []ContractAllowance `json:"allowance_items,omitempty"`
+	Benefits       string              `json:"benefits"`
+	ContractTerms  string              `json:"contract_terms"`
+}
+
+// FieldChange records the previous and new value of an amended contract field
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// ContractVersion is the original contract (version 1) or an amendment (변경계약)
+type ContractVersion struct {
+	ID            int                    `json:"id" db:"id"`
+	ContractID    int                    `json:"contract_id" db:"contract_id"`
+	Version       int                    `json:"version" db:"version"`
+	EffectiveDate time.Time              `json:"effective_date" db:"effective_date"`
+	Terms         ContractTerms          `json:"terms" db:"terms"`
+	Changes       map[string]FieldChange `json:"changes" db:"changes"`
+	Reason        sql.NullString         `json:"reason" db:"reason"`
+	SignedDate    sql.NullTime           `json:"signed_date" db:"signed_date"`
+	CreatedBy     sql.NullInt64          `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+}
+
+// ContractSignatureRequest is a one-time e-signing link sent to the employee for one contract version
+type ContractSignatureRequest struct {
+	ID              int            `json:"id" db:"id"`
+	ContractID      int            `json:"contract_id" db:"contract_id"`
+	Version         int            `json:"version" db:"version"`
+	Status          string         `json:"status" db:"status"`
+	ExpiresAt       time.Time      `json:"expires_at" db:"expires_at"`
+	SignerName      sql.NullString `json:"signer_name" db:"signer_name"`
+	SignatureType   sql.NullString `json:"signature_type" db:"signature_type"`
+	SignerIP        sql.NullString `json:"signer_ip" db:"signer_ip"`
+	SignerUserAgent sql.NullString `json:"signer_user_agent" db:"signer_user_agent"`
+	DocumentHash    sql.NullString `json:"document_hash" db:"document_hash"`
+	SignedAt        sql.NullTime   `json:"signed_at" db:"signed_at"`
+	SignedFilePath  sql.NullString `json:"signed_file_path" db:"signed_file_path"`
+	CreatedBy       int            `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+}
@@ -27,67 +27,95 @@ type Employee struct {
 }
 
 type EmploymentContract struct {
-	ID             int            `json:"id" db:"id"`
-	EmployeeID     int            `json:"employee_id" db:"employee_id"`
-	ContractType   string         `json:"contract_type" db:"contract_type"`
-	StartDate      time.Time      `json:"start_date" db:"start_date"`
-	EndDate        sql.NullTime   `json:"end_date" db:"end_date"`
-	Workplace      string         `json:"workplace" db:"workplace"`
-	JobDescription sql.NullString `json:"job_description" db:"job_description"`
-	WorkingHours   string         `json:"working_hours" db:"working_hours"`
-	WorkDays       string         `json:"work_days" db:"work_days"`
-	BaseSalary     float64        `json:"base_salary" db:"base_salary"`
-	Allowances     sql.NullString `json:"allowances" db:"allowances"`
-	Benefits       sql.NullString `json:"benefits" db:"benefits"`
-	ContractTerms  sql.NullString `json:"contract_terms" db:"contract_terms"`
-	SignedDate     sql.NullTime   `json:"signed_date" db:"signed_date"`
-	IsActive       bool           `json:"is_active" db:"is_active"`
-	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	ID                 int            `json:"id" db:"id"`
+	EmployeeID         int            `json:"employee_id" db:"employee_id"`
+	ContractType       string         `json:"contract_type" db:"contract_type"`
+	StartDate          time.Time      `json:"start_date" db:"start_date"`
+	EndDate            sql.NullTime   `json:"end_date" db:"end_date"`
+	Workplace          string         `json:"workplace" db:"workplace"`
+	JobDescription     sql.NullString `json:"job_description" db:"job_description"`
+	WorkingHours       string         `json:"working_hours" db:"working_hours"`
+	WorkDays           string         `json:"work_days" db:"work_days"`
+	BreakTime          sql.NullString `json:"break_time" db:"break_time"`
+	WeeklyHoliday      sql.NullString `json:"weekly_holiday" db:"weekly_holiday"`
+	WorkScheduleID     sql.NullInt64  `json:"work_schedule_id" db:"work_schedule_id"`
+	BaseSalary         float64        `json:"base_salary" db:"base_salary"`
+	Allowances         sql.NullString `json:"allowances" db:"allowances"`
+	Benefits           sql.NullString `json:"benefits" db:"benefits"`
+	ContractTerms      sql.NullString `json:"contract_terms" db:"contract_terms"`
+	SignedDate         sql.NullTime   `json:"signed_date" db:"signed_date"`
+	IsActive           bool           `json:"is_active" db:"is_active"`
+	CurrentVersion     int            `json:"current_version" db:"current_version"`
+	LockedAt           sql.NullTime   `json:"locked_at" db:"locked_at"`
+	PreviousContractID sql.NullInt64  `json:"previous_contract_id" db:"previous_contract_id"`
+	NextContractID     sql.NullInt64  `json:"next_contract_id" db:"next_contract_id"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
 }
 
 type AttendanceLog struct {
-	ID            int            `json:"id" db:"id"`
-	EmployeeID    int            `json:"employee_id" db:"employee_id"`
-	WorkDate      time.Time      `json:"work_date" db:"work_date"`
-	ClockIn       sql.NullString `json:"clock_in" db:"clock_in"`
-	ClockOut      sql.NullString `json:"clock_out" db:"clock_out"`
-	BreakStart    sql.NullString `json:"break_start" db:"break_start"`
-	BreakEnd      sql.NullString `json:"break_end" db:"break_end"`
-	TotalHours    sql.NullFloat64 `json:"total_hours" db:"total_hours"`
-	OvertimeHours sql.NullFloat64 `json:"overtime_hours" db:"overtime_hours"`
-	Status        string         `json:"status" db:"status"`
-	Notes         sql.NullString `json:"notes" db:"notes"`
-	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+	ID             int             `json:"id" db:"id"`
+	EmployeeID     int             `json:"employee_id" db:"employee_id"`
+	WorkDate       time.Time       `json:"work_date" db:"work_date"`
+	ClockIn        sql.NullString  `json:"clock_in" db:"clock_in"`
+	ClockOut       sql.NullString  `json:"clock_out" db:"clock_out"`
+	BreakStart     sql.NullString  `json:"break_start" db:"break_start"`
+	BreakEnd       sql.NullString  `json:"break_end" db:"break_end"`
+	TotalHours     sql.NullFloat64 `json:"total_hours" db:"total_hours"`
+	OvertimeHours  sql.NullFloat64 `json:"overtime_hours" db:"overtime_hours"`
+	OvertimeStatus string          `json:"overtime_status" db:"overtime_status"`
+	Status         string          `json:"status" db:"status"`
+	Notes          sql.NullString  `json:"notes" db:"notes"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 type PayrollRecord struct {
-	ID                  int       `json:"id" db:"id"`
-	EmployeeID          int       `json:"employee_id" db:"employee_id"`
-	PayPeriodStart      time.Time `json:"pay_period_start" db:"pay_period_start"`
-	PayPeriodEnd        time.Time `json:"pay_period_end" db:"pay_period_end"`
-	BaseSalary          float64   `json:"base_salary" db:"base_salary"`
-	OvertimeHours       float64   `json:"overtime_hours" db:"overtime_hours"`
-	OvertimePay         float64   `json:"overtime_pay" db:"overtime_pay"`
-	HolidayHours        float64   `json:"holiday_hours" db:"holiday_hours"`
-	HolidayPay          float64   `json:"holiday_pay" db:"holiday_pay"`
-	Allowances          float64   `json:"allowances" db:"allowances"`
-	Bonus               float64   `json:"bonus" db:"bonus"`
-	GrossPay            float64   `json:"gross_pay" db:"gross_pay"`
-	IncomeTax           float64   `json:"income_tax" db:"income_tax"`
-	LocalTax            float64   `json:"local_tax" db:"local_tax"`
-	NationalPension     float64   `json:"national_pension" db:"national_pension"`
-	HealthInsurance     float64   `json:"health_insurance" db:"health_insurance"`
-	EmploymentInsurance float64   `json:"employment_insurance" db:"employment_insurance"`
-	LongTermCare        float64   `json:"long_term_care" db:"long_term_care"`
-	OtherDeductions     float64   `json:"other_deductions" db:"other_deductions"`
-	TotalDeductions     float64   `json:"total_deductions" db:"total_deductions"`
-	NetPay              float64   `json:"net_pay" db:"net_pay"`
-	PayDate             sql.NullTime `json:"pay_date" db:"pay_date"`
-	IsPaid              bool      `json:"is_paid" db:"is_paid"`
-	CreatedAt           time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+	ID                   int           `json:"id" db:"id"`
+	EmployeeID           int           `json:"employee_id" db:"employee_id"`
+	PayrollRunID         sql.NullInt64 `json:"payroll_run_id" db:"payroll_run_id"`
+	PayPeriodStart       time.Time     `json:"pay_period_start" db:"pay_period_start"`
+	PayPeriodEnd         time.Time     `json:"pay_period_end" db:"pay_period_end"`
+	SalaryType           string        `json:"salary_type" db:"salary_type"`
+	WageRate             Money         `json:"wage_rate" db:"wage_rate"`       // 월급, 시급 또는 일급
+	WorkedHours          float64       `json:"worked_hours" db:"worked_hours"` // 시급제 근로시간
+	WorkedDays           float64       `json:"worked_days" db:"worked_days"`   // 일급제 근무일수
+	BaseSalary           Money         `json:"base_salary" db:"base_salary"`   // 기간의 기본급
+	OvertimeHours        float64       `json:"overtime_hours" db:"overtime_hours"`
+	OvertimePay          Money         `json:"overtime_pay" db:"overtime_pay"`
+	HolidayHours         float64       `json:"holiday_hours" db:"holiday_hours"`
+	HolidayPay           Money         `json:"holiday_pay" db:"holiday_pay"`
+	HolidayOvertimeHours float64       `json:"holiday_overtime_hours" db:"holiday_overtime_hours"` // 휴일근로 8시간 초과
+	NightHours           float64       `json:"night_hours" db:"night_hours"`                       // 야간근로 (22:00~06:00)
+	NightPay             Money         `json:"night_pay" db:"night_pay"`
+	WeeklyHolidayHours   float64       `json:"weekly_holiday_hours" db:"weekly_holiday_hours"` // 주휴시간
+	WeeklyHolidayPay     Money         `json:"weekly_holiday_pay" db:"weekly_holiday_pay"`     // 주휴수당
+	Allowances           Money         `json:"allowances" db:"allowances"`
+	Bonus                Money         `json:"bonus" db:"bonus"`
+	UnpaidLeaveDays      float64       `json:"unpaid_leave_days" db:"unpaid_leave_days"`
+	UnpaidLeaveDeduction Money         `json:"unpaid_leave_deduction" db:"unpaid_leave_deduction"`
+	GrossPay             Money         `json:"gross_pay" db:"gross_pay"`
+	TaxablePay           Money         `json:"taxable_pay" db:"taxable_pay"`
+	NonTaxablePay        Money         `json:"non_taxable_pay" db:"non_taxable_pay"`
+	IncomeTax            Money         `json:"income_tax" db:"income_tax"`
+	LocalTax             Money         `json:"local_tax" db:"local_tax"`
+	NationalPension      Money         `json:"national_pension" db:"national_pension"`
+	HealthInsurance      Money         `json:"health_insurance" db:"health_insurance"`
+	EmploymentInsurance  Money         `json:"employment_insurance" db:"employment_insurance"`
+	LongTermCare         Money         `json:"long_term_care" db:"long_term_care"`
+	PensionBase          Money         `json:"pension_base" db:"pension_base"`
+	HealthInsuranceBase  Money         `json:"health_insurance_base" db:"health_insurance_base"`
+	YearEndTax           Money         `json:"year_end_tax" db:"year_end_tax"`
+	YearEndLocalTax      Money         `json:"year_end_local_tax" db:"year_end_local_tax"`
+	AdjustmentPay        Money         `json:"adjustment_pay" db:"adjustment_pay"`               // 소급·정정 지급액 (총 지급액 제외)
+	AdjustmentDeductions Money         `json:"adjustment_deductions" db:"adjustment_deductions"` // 소급·정정 공제액 (총 공제액 포함)
+	OtherDeductions      Money         `json:"other_deductions" db:"other_deductions"`
+	TotalDeductions      Money         `json:"total_deductions" db:"total_deductions"`
+	NetPay               Money         `json:"net_pay" db:"net_pay"`
+	PayDate              sql.NullTime  `json:"pay_date" db:"pay_date"`
+	IsPaid               bool          `json:"is_paid" db:"is_paid"`
+	CreatedAt            time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 type LeaveRequest struct {
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Money is an amount in whole won. Payroll amounts are kept as Money so that every line is
+// truncated by its own statutory rule and totals add up exactly wherever they are shown.
+type Money int64
+
+// moneyEpsilon absorbs float error in rate products, so 0.045 x 2,000,000 is not truncated to 89,999
+const moneyEpsilon = 1e-6
+
+// Won converts an entered amount to Money, rounding to the nearest won
+func Won(amount float64) Money {
+	return Money(math.Round(amount))
+}
+
+// TruncateWon converts a calculated amount to Money, dropping the fraction of a won
+func TruncateWon(amount float64) Money {
+	if amount < 0 {
+		return -TruncateWon(-amount)
+	}
+	return Money(math.Floor(amount + moneyEpsilon))
+}
+
+// MulRate multiplies the amount by a rate, dropping the fraction of a won
+func (m Money) MulRate(rate float64) Money {
+	return TruncateWon(float64(m) * rate)
+}
+
+// Truncate drops the part of the amount below unit, e.g. Truncate(10) for 원 단위 절사
+func (m Money) Truncate(unit Money) Money {
+	return m - m%unit
+}
+
+func (m Money) Float64() float64 {
+	return float64(m)
+}
+
+// Scan reads whole won from DECIMAL columns. Legacy fractional amounts are rounded.
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		*m = Won(v)
+	case []byte:
+		return m.Scan(string(v))
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid money value %q", v)
+		}
+		*m = Won(f)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", value)
+	}
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
@@ -0,0 +1,243 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PayrollRun is the payroll of one pay period (YYYY-MM). Its records are generated as drafts
+// and move through review and approval to payment; approved runs can no longer be changed.
+type PayrollRun struct {
+	ID          int           `json:"id" db:"id"`
+	PayPeriod   string        `json:"pay_period" db:"pay_period"`
+	PeriodStart time.Time     `json:"period_start" db:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end" db:"period_end"`
+	PayDate     sql.NullTime  `json:"pay_date" db:"pay_date"`
+	Status      string        `json:"status" db:"status"`
+	CreatedBy   int           `json:"created_by" db:"created_by"`
+	ApprovedBy  sql.NullInt64 `json:"approved_by" db:"approved_by"`
+	ApprovedAt  sql.NullTime  `json:"approved_at" db:"approved_at"`
+	PaidAt      sql.NullTime  `json:"paid_at" db:"paid_at"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// WithholdingTaxTable is a version of the NTS 근로소득 간이세액표, in force from its effective date
+type WithholdingTaxTable struct {
+	ID            int           `json:"id" db:"id"`
+	Name          string        `json:"name" db:"name"`
+	EffectiveDate time.Time     `json:"effective_date" db:"effective_date"`
+	CreatedBy     sql.NullInt64 `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	BracketCount  int           `json:"bracket_count"`
+}
+
+// MinimumWage is the statutory minimum hourly wage (최저임금) in force from January 1 of its year
+type MinimumWage struct {
+	ID          int            `json:"id" db:"id"`
+	Year        int            `json:"year" db:"year"`
+	HourlyWage  Money          `json:"hourly_wage" db:"hourly_wage"`
+	Description sql.NullString `json:"description" db:"description"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// InsuranceRate is the contribution rate of one social insurance from its effective date.
+// The long-term care rate is a ratio of the health insurance contribution.
+type InsuranceRate struct {
+	ID            int            `json:"id" db:"id"`
+	InsuranceType string         `json:"insurance_type" db:"insurance_type"`
+	EffectiveDate time.Time      `json:"effective_date" db:"effective_date"`
+	EmployeeRate  float64        `json:"employee_rate" db:"employee_rate"`
+	EmployerRate  float64        `json:"employer_rate" db:"employer_rate"`
+	Description   sql.NullString `json:"description" db:"description"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ContributionLimit is the floor and ceiling of the monthly income an insurance is charged on
+// (기준소득월액 상·하한) from its effective date
+type ContributionLimit struct {
+	ID            int       `json:"id" db:"id"`
+	InsuranceType string    `json:"insurance_type" db:"insurance_type"`
+	EffectiveDate time.Time `json:"effective_date" db:"effective_date"`
+	LowerLimit    float64   `json:"lower_limit" db:"lower_limit"`
+	UpperLimit    float64   `json:"upper_limit" db:"upper_limit"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// EmployeeInsuranceBase is the monthly income reported for an employee's insurance from its
+// effective date, which contributions are charged on instead of the actual pay
+type EmployeeInsuranceBase struct {
+	ID             int            `json:"id" db:"id"`
+	EmployeeID     int            `json:"employee_id" db:"employee_id"`
+	InsuranceType  string         `json:"insurance_type" db:"insurance_type"`
+	EffectiveDate  time.Time      `json:"effective_date" db:"effective_date"`
+	ReportedAmount float64        `json:"reported_amount" db:"reported_amount"`
+	Notes          sql.NullString `json:"notes" db:"notes"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+}
+
+// EmployeeBankAccount is the account an employee's pay is transferred to. The account number is
+// stored encrypted and only shown masked.
+type EmployeeBankAccount struct {
+	ID            int       `json:"id" db:"id"`
+	EmployeeID    int       `json:"employee_id" db:"employee_id"`
+	BankCode      string    `json:"bank_code" db:"bank_code"`
+	BankName      string    `json:"bank_name"`
+	AccountNumber string    `json:"account_number"`
+	AccountHolder string    `json:"account_holder" db:"account_holder"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PayrollTransferBatch is a bulk transfer (대량이체) file exported for the net pay of a payroll run.
+// Marking it transferred pays the records in it.
+type PayrollTransferBatch struct {
+	ID            int          `json:"id" db:"id"`
+	PayrollRunID  int          `json:"payroll_run_id" db:"payroll_run_id"`
+	BankCode      string       `json:"bank_code" db:"bank_code"`
+	TransferDate  time.Time    `json:"transfer_date" db:"transfer_date"`
+	RecordCount   int          `json:"record_count" db:"record_count"`
+	TotalAmount   Money        `json:"total_amount" db:"total_amount"`
+	FileFormat    string       `json:"file_format" db:"file_format"` // csv, fixed
+	Status        string       `json:"status" db:"status"`
+	CreatedBy     int          `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+	TransferredAt sql.NullTime `json:"transferred_at" db:"transferred_at"`
+}
+
+// YearEndSettlement is an employee's 연말정산 for a tax year: the year's pay and withheld tax from
+// payroll, the deductions the employee declared and the tax finally due. The balance is charged,
+// or refunded when negative, with the February payroll of the following year.
+type YearEndSettlement struct {
+	ID                     int           `json:"id" db:"id"`
+	EmployeeID             int           `json:"employee_id" db:"employee_id"`
+	EmployeeName           string        `json:"employee_name,omitempty"`
+	TaxYear                int           `json:"tax_year" db:"tax_year"`
+	InsurancePremiums      Money         `json:"insurance_premiums" db:"insurance_premiums"`
+	MedicalExpenses        Money         `json:"medical_expenses" db:"medical_expenses"`
+	MedicalExpensesSpecial Money         `json:"medical_expenses_special" db:"medical_expenses_special"`
+	EducationExpenses      Money         `json:"education_expenses" db:"education_expenses"`
+	Donations              Money         `json:"donations" db:"donations"`
+	CreditCardSpending     Money         `json:"credit_card_spending" db:"credit_card_spending"`
+	DebitCardSpending      Money         `json:"debit_card_spending" db:"debit_card_spending"`
+	Dependents             int           `json:"dependents" db:"dependents"`
+	TotalPay               Money         `json:"total_pay" db:"total_pay"`
+	NonTaxablePay          Money         `json:"non_taxable_pay" db:"non_taxable_pay"`
+	EarnedIncomeDeduction  Money         `json:"earned_income_deduction" db:"earned_income_deduction"`
+	EarnedIncomeAmount     Money         `json:"earned_income_amount" db:"earned_income_amount"`
+	PersonalDeduction      Money         `json:"personal_deduction" db:"personal_deduction"`
+	PensionDeduction       Money         `json:"pension_deduction" db:"pension_deduction"`
+	SpecialIncomeDeduction Money         `json:"special_income_deduction" db:"special_income_deduction"`
+	CardDeduction          Money         `json:"card_deduction" db:"card_deduction"`
+	TaxBase                Money         `json:"tax_base" db:"tax_base"`
+	CalculatedTax          Money         `json:"calculated_tax" db:"calculated_tax"`
+	EarnedIncomeTaxCredit  Money         `json:"earned_income_tax_credit" db:"earned_income_tax_credit"`
+	SpecialTaxCredit       Money         `json:"special_tax_credit" db:"special_tax_credit"`
+	StandardTaxCredit      Money         `json:"standard_tax_credit" db:"standard_tax_credit"`
+	DeterminedTax          Money         `json:"determined_tax" db:"determined_tax"`
+	DeterminedLocalTax     Money         `json:"determined_local_tax" db:"determined_local_tax"`
+	WithheldTax            Money         `json:"withheld_tax" db:"withheld_tax"`
+	WithheldLocalTax       Money         `json:"withheld_local_tax" db:"withheld_local_tax"`
+	BalanceTax             Money         `json:"balance_tax" db:"balance_tax"`
+	BalanceLocalTax        Money         `json:"balance_local_tax" db:"balance_local_tax"`
+	Status                 string        `json:"status" db:"status"`
+	PayrollID              sql.NullInt64 `json:"payroll_id" db:"payroll_id"`
+	ConfirmedAt            sql.NullTime  `json:"confirmed_at" db:"confirmed_at"`
+	CreatedAt              time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// PayrollAdjustment corrects a paid payroll record without rewriting it. It keeps the corrected input,
+// and each amount line holds the signed difference from what was paid. Pending adjustments are settled
+// with the employee's next payroll, which records them as adjustment pay and deductions.
+type PayrollAdjustment struct {
+	ID                   int           `json:"id" db:"id"`
+	OriginalPayrollID    int           `json:"original_payroll_id" db:"original_payroll_id"`
+	EmployeeID           int           `json:"employee_id" db:"employee_id"`
+	EmployeeName         string        `json:"employee_name,omitempty"`
+	PayPeriodStart       time.Time     `json:"pay_period_start"` // 원 급여 기간
+	PayPeriodEnd         time.Time     `json:"pay_period_end"`
+	Reason               string        `json:"reason" db:"reason"`
+	WageRate             Money         `json:"wage_rate" db:"wage_rate"` // 정정 후 월급, 시급 또는 일급
+	WorkedHours          float64       `json:"worked_hours" db:"worked_hours"`
+	WorkedDays           float64       `json:"worked_days" db:"worked_days"`
+	OvertimeHours        float64       `json:"overtime_hours" db:"overtime_hours"`
+	HolidayHours         float64       `json:"holiday_hours" db:"holiday_hours"`
+	HolidayOvertimeHours float64       `json:"holiday_overtime_hours" db:"holiday_overtime_hours"`
+	NightHours           float64       `json:"night_hours" db:"night_hours"`
+	UnpaidLeaveDays      float64       `json:"unpaid_leave_days" db:"unpaid_leave_days"`
+	BaseSalary           Money         `json:"base_salary" db:"base_salary"` // 이하 항목별 증감액
+	OvertimePay          Money         `json:"overtime_pay" db:"overtime_pay"`
+	HolidayPay           Money         `json:"holiday_pay" db:"holiday_pay"`
+	NightPay             Money         `json:"night_pay" db:"night_pay"`
+	WeeklyHolidayPay     Money         `json:"weekly_holiday_pay" db:"weekly_holiday_pay"`
+	Allowances           Money         `json:"allowances" db:"allowances"`
+	Bonus                Money         `json:"bonus" db:"bonus"`
+	UnpaidLeaveDeduction Money         `json:"unpaid_leave_deduction" db:"unpaid_leave_deduction"`
+	GrossPay             Money         `json:"gross_pay" db:"gross_pay"`
+	TaxablePay           Money         `json:"taxable_pay" db:"taxable_pay"`
+	NonTaxablePay        Money         `json:"non_taxable_pay" db:"non_taxable_pay"`
+	IncomeTax            Money         `json:"income_tax" db:"income_tax"`
+	LocalTax             Money         `json:"local_tax" db:"local_tax"`
+	NationalPension      Money         `json:"national_pension" db:"national_pension"`
+	HealthInsurance      Money         `json:"health_insurance" db:"health_insurance"`
+	LongTermCare         Money         `json:"long_term_care" db:"long_term_care"`
+	EmploymentInsurance  Money         `json:"employment_insurance" db:"employment_insurance"`
+	OtherDeductions      Money         `json:"other_deductions" db:"other_deductions"`
+	TotalDeductions      Money         `json:"total_deductions" db:"total_deductions"`
+	NetPay               Money         `json:"net_pay" db:"net_pay"`
+	Status               string        `json:"status" db:"status"` // pending, applied
+	AppliedPayrollID     sql.NullInt64 `json:"applied_payroll_id" db:"applied_payroll_id"`
+	CreatedBy            sql.NullInt64 `json:"created_by" db:"created_by"`
+	CreatedAt            time.Time     `json:"created_at" db:"created_at"`
+	AppliedAt            sql.NullTime  `json:"applied_at" db:"applied_at"`
+}
+
+// SeveranceSettlement is the 퇴직급여 of a retiring employee: the statutory severance pay on the
+// average wage and service period, the DC pension contributions still owed, and the 퇴직소득세
+type SeveranceSettlement struct {
+	ID                    int           `json:"id" db:"id"`
+	EmployeeID            int           `json:"employee_id" db:"employee_id"`
+	EmployeeName          string        `json:"employee_name,omitempty"`
+	RetirementDate        time.Time     `json:"retirement_date" db:"retirement_date"` // 퇴직일 (마지막 근무일 다음 날)
+	PensionType           string        `json:"pension_type" db:"pension_type"`       // severance, db, dc
+	ServiceStart          time.Time     `json:"service_start" db:"service_start"`
+	ServiceEnd            time.Time     `json:"service_end" db:"service_end"`
+	ExcludedDays          int           `json:"excluded_days" db:"excluded_days"`
+	ServiceDays           int           `json:"service_days" db:"service_days"`
+	AverageDailyWage      float64       `json:"average_daily_wage" db:"average_daily_wage"`
+	SeverancePay          Money         `json:"severance_pay" db:"severance_pay"`
+	DCContributions       Money         `json:"dc_contributions" db:"dc_contributions"`
+	DCShortfall           Money         `json:"dc_shortfall" db:"dc_shortfall"`
+	RetirementIncome      Money         `json:"retirement_income" db:"retirement_income"`
+	ServiceYears          int           `json:"service_years" db:"service_years"`
+	ServiceYearsDeduction Money         `json:"service_years_deduction" db:"service_years_deduction"`
+	ConvertedPay          Money         `json:"converted_pay" db:"converted_pay"`
+	ConvertedPayDeduction Money         `json:"converted_pay_deduction" db:"converted_pay_deduction"`
+	TaxBase               Money         `json:"tax_base" db:"tax_base"`
+	ConvertedTax          Money         `json:"converted_tax" db:"converted_tax"`
+	IncomeTax             Money         `json:"income_tax" db:"income_tax"`
+	LocalTax              Money         `json:"local_tax" db:"local_tax"`
+	CompanyPayment        Money         `json:"company_payment" db:"company_payment"`
+	Status                string        `json:"status" db:"status"` // draft, confirmed, paid
+	PayDate               sql.NullTime  `json:"pay_date" db:"pay_date"`
+	CreatedBy             sql.NullInt64 `json:"created_by" db:"created_by"`
+	ConfirmedAt           sql.NullTime  `json:"confirmed_at" db:"confirmed_at"`
+	CreatedAt             time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// RetirementPensionContribution is the yearly contribution owed to an employee's defined-contribution
+// pension: at least a twelfth of the year's wages
+type RetirementPensionContribution struct {
+	ID             int          `json:"id" db:"id"`
+	EmployeeID     int          `json:"employee_id" db:"employee_id"`
+	EmployeeName   string       `json:"employee_name,omitempty"`
+	PlanYear       int          `json:"plan_year" db:"plan_year"`
+	AnnualWages    Money        `json:"annual_wages" db:"annual_wages"`
+	RequiredAmount Money        `json:"required_amount" db:"required_amount"`
+	PaidAmount     Money        `json:"paid_amount" db:"paid_amount"`
+	PaidDate       sql.NullTime `json:"paid_date" db:"paid_date"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// BreakWindow is a break (휴게시간) within a working day, as "HH:MM" clock times
+type BreakWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// WorkScheduleDay is the working time on one weekday. Weekdays without a day are off days.
+type WorkScheduleDay struct {
+	Weekday   time.Weekday  `json:"weekday"`
+	StartTime string        `json:"start_time"`
+	EndTime   string        `json:"end_time"`
+	Breaks    []BreakWindow `json:"breaks"`
+}
+
+// WorkSchedule is a weekly working-time pattern referenced by employment contracts
+type WorkSchedule struct {
+	ID               int               `json:"id" db:"id"`
+	Name             string            `json:"name" db:"name"`
+	Description      string            `json:"description" db:"description"`
+	WeeklyHoliday    time.Weekday      `json:"weekly_holiday" db:"weekly_holiday"`
+	LateGraceMinutes int               `json:"late_grace_minutes" db:"late_grace_minutes"`
+	IsActive         bool              `json:"is_active" db:"is_active"`
+	Days             []WorkScheduleDay `json:"days"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`
+}
@@ -830,6 +830,7 @@ function getLeaveTypeLabel(type) {
         'annual': '연차',
         'sick': '병가',
         'personal': '개인사유',
+        'unpaid': '무급휴가',
         'maternity': '출산휴가',
         'paternity': '육아휴직'
     };